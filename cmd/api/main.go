@@ -27,11 +27,18 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/user/go-backend/internal/checks"
 	"github.com/user/go-backend/internal/config"
 	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/discovery"
+	"github.com/user/go-backend/internal/gitlab"
 	"github.com/user/go-backend/internal/handlers"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/outbox"
 	"github.com/user/go-backend/internal/repository"
 	"github.com/user/go-backend/internal/router"
+	"github.com/user/go-backend/internal/scanner"
+	"github.com/user/go-backend/internal/webhooks"
 )
 
 func main() {
@@ -84,10 +91,153 @@ func main() {
 	}
 
 	projectRepo := repository.NewProjectRepository(db)
+	exemptionRepo := repository.NewExemptionRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	webhookRepo, err := repository.NewWebhookRepository(db, cfg.WebhookSecretKey)
+	if err != nil {
+		logger.Error("failed to create webhook repository", "error", err)
+		os.Exit(1)
+	}
+	if len(cfg.WebhookSecretKey) == 0 {
+		logger.Warn("WEBHOOK_SECRET_KEY is not set; webhook secrets are stored unencrypted")
+	}
+	outboxRepo := repository.NewOutboxRepository(db)
+	syncRepo := repository.NewSyncRepository(db)
+	applicationRepo := repository.NewApplicationRepository(db)
+
+	// Project writes store their events in the outbox; the relay hands them
+	// to webhook subscriptions and any configured NDJSON sinks
+	dispatcher := webhooks.New(webhookRepo, webhooks.Config{}, logger)
+	sinks := []outbox.Sink{dispatcher}
+	if cfg.OutboxHTTPURL != "" {
+		sinks = append(sinks, outbox.NewHTTPSink(cfg.OutboxHTTPURL, nil))
+		logger.Info("relaying events over http", "url", cfg.OutboxHTTPURL)
+	}
+	if cfg.OutboxFile != "" {
+		fileSink, err := outbox.OpenFileSink(cfg.OutboxFile)
+		if err != nil {
+			logger.Error("failed to open event file", "error", err)
+			os.Exit(1)
+		}
+		defer fileSink.Close()
+		sinks = append(sinks, fileSink)
+		logger.Info("relaying events to file", "path", cfg.OutboxFile)
+	}
+	relay := outbox.NewRelay(outboxRepo, sinks, outbox.Config{}, logger)
+
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Every instance follows the events any relay published, woken by its
+	// notifications, and fans them out to the event streams it serves
+	wake, err := database.Listen(backgroundCtx, cfg.DatabaseURL, repository.EventsChannel)
+	if err != nil {
+		logger.Error("failed to listen for published events", "error", err)
+		os.Exit(1)
+	}
+	broker := outbox.NewBroker()
+	follower := outbox.NewFollower(outboxRepo, broker, wake, outbox.Config{PollInterval: 10 * time.Second}, logger)
+
+	profiles := checks.NewProfiles(cfg.Profiles)
+
+	// Each GitLab instance with a token gets a client, rate limited on its
+	// own. Projects of other instances cannot be scanned.
+	clients := gitlab.Instances{}
+	groupListers := make(map[string]handlers.GroupProjectLister)
+	hookSecrets := make(map[string]string)
+	var instances []models.GitLabInstance
+	for _, instance := range cfg.GitLabInstances {
+		instances = append(instances, models.GitLabInstance{
+			Name:       instance.Name,
+			URL:        instance.URL,
+			RateLimit:  instance.RateLimit,
+			Burst:      instance.Burst,
+			Scanning:   instance.ScanningEnabled(),
+			Hooks:      instance.WebhookSecret != "",
+			SyncGroups: instance.SyncGroups,
+		})
+		if instance.WebhookSecret != "" {
+			hookSecrets[instance.Name] = instance.WebhookSecret
+		}
+		if !instance.ScanningEnabled() {
+			logger.Warn("no gitlab token set, scanning disabled", "instance", instance.Name)
+			continue
+		}
+
+		client, err := gitlab.NewClient(gitlab.Config{
+			BaseURL:   instance.URL,
+			Token:     instance.Token,
+			RateLimit: instance.RateLimit,
+			Burst:     instance.Burst,
+		})
+		if err != nil {
+			logger.Error("failed to create gitlab client", "instance", instance.Name, "error", err)
+			os.Exit(1)
+		}
+		clients[instance.Name] = client
+		groupListers[instance.Name] = client
+		logger.Info("gitlab scanning enabled", "instance", instance.Name, "gitlab_url", instance.URL)
+	}
+
+	// The scanner stays nil without any GitLab token; handlers that need it
+	// report scanning as unavailable
+	var projectScanner handlers.Scanner
+	var memberReviewer handlers.MemberReviewer
+	var rescans handlers.RescanQueue
+	syncTriggers := make(map[string]handlers.SyncTrigger)
+	if len(clients) > 0 {
+		gitlabScanner := scanner.New(clients, profiles, projectRepo, logger)
+		queue := scanner.NewQueue(gitlabScanner, 4, 1000, logger)
+		go queue.Run(backgroundCtx)
+		projectScanner, memberReviewer, rescans = gitlabScanner, gitlabScanner, queue
+
+		for _, instance := range cfg.GitLabInstances {
+			if len(instance.SyncGroups) == 0 {
+				continue
+			}
+			syncer := discovery.New(clients[instance.Name], projectRepo, syncRepo, queue, discovery.Config{
+				Instance: instance.Name,
+				Groups:   instance.SyncGroups,
+				Interval: cfg.GitLabSyncInterval,
+			}, logger)
+			go syncer.Run(backgroundCtx)
+			syncTriggers[instance.Name] = syncer
+			logger.Info("gitlab group sync enabled", "instance", instance.Name, "groups", instance.SyncGroups, "interval", cfg.GitLabSyncInterval)
+		}
+	}
 
-	projectHandler := handlers.NewProjectHandler(projectRepo, logger)
+	go cleanupIdempotencyKeys(backgroundCtx, idempotencyRepo, logger)
+	go cleanupOutbox(backgroundCtx, outboxRepo, cfg.OutboxRetention, logger)
+	go relay.Run(backgroundCtx)
+	go dispatcher.Run(backgroundCtx)
+	go follower.Run(backgroundCtx)
 
-	handler := router.New(projectHandler, logger)
+	// GitLab hooks keep projects current between scans for the instances
+	// with a secret set
+	var gitlabHook *handlers.GitLabHookHandler
+	if len(hookSecrets) > 0 {
+		gitlabHook = handlers.NewGitLabHookHandler(projectRepo, rescans, hookSecrets, logger)
+	}
+
+	handler := router.New(router.Handlers{
+		Idempotency: handlers.NewIdempotencyMiddleware(idempotencyRepo, cfg.IdempotencyTTL, logger),
+		Project:     handlers.NewProjectHandler(projectRepo, logger),
+		Gate:        handlers.NewGateHandler(projectRepo, exemptionRepo, projectScanner, logger),
+		Scan:        handlers.NewScanHandler(projectScanner, logger),
+		Member:      handlers.NewMemberHandler(memberReviewer, logger),
+		Exemption:   handlers.NewExemptionHandler(projectRepo, exemptionRepo, logger),
+		Report:      handlers.NewReportHandler(projectRepo, exemptionRepo, logger),
+		Import:      handlers.NewImportHandler(projectRepo, logger),
+		Export:      handlers.NewExportHandler(projectRepo, logger),
+		Batch:       handlers.NewBatchHandler(projectRepo, logger),
+		Webhook:     handlers.NewWebhookHandler(webhookRepo, dispatcher, logger),
+		Events:      handlers.NewEventsHandler(broker, outboxRepo, groupListers, logger),
+		GitLabHook:  gitlabHook,
+		Sync:        handlers.NewSyncHandler(syncRepo, syncTriggers, logger),
+		Instance:    handlers.NewInstanceHandler(instances, logger),
+		Check:       handlers.NewCheckHandler(profiles, logger),
+		Application: handlers.NewApplicationHandler(projectRepo, applicationRepo, logger),
+	}, logger)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
@@ -96,6 +246,9 @@ func main() {
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
+	// Shutdown waits for open requests, so end event streams first; their
+	// clients reconnect to another instance
+	srv.RegisterOnShutdown(broker.Close)
 
 	go func() {
 		logger.Info("server starting", "address", srv.Addr)
@@ -122,6 +275,50 @@ func main() {
 	logger.Info("server stopped")
 }
 
+// cleanupIdempotencyKeys periodically deletes expired idempotency records
+func cleanupIdempotencyKeys(ctx context.Context, repo repository.IdempotencyRepository, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeleteExpired(ctx)
+			if err != nil {
+				logger.Error("failed to delete expired idempotency keys", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("deleted expired idempotency keys", "count", deleted)
+			}
+		}
+	}
+}
+
+// cleanupOutbox periodically deletes events published longer ago than retention
+func cleanupOutbox(ctx context.Context, repo repository.OutboxRepository, retention time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeletePublished(ctx, time.Now().Add(-retention))
+			if err != nil {
+				logger.Error("failed to delete published events", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.Info("deleted published events", "count", deleted)
+			}
+		}
+	}
+}
+
 // setupLogger configures structured logging with slog
 func setupLogger(level string) *slog.Logger {
 	var logLevel slog.Level
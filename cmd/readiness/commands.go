@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/user/go-backend/pkg/client"
+)
+
+func (a *app) newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: readiness %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// list prints every project matching the filters, paging through the API
+func (a *app) list(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("list", "")
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	ready := fs.String("ready", "", "Only ready (true) or not ready (false) projects")
+	failing := fs.String("failing", "", "Only projects failing the named check, e.g. codeowners_exists")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := client.ListOptions{Failing: *failing}
+	if *ready != "" {
+		v, err := strconv.ParseBool(*ready)
+		if err != nil {
+			return fmt.Errorf("invalid -ready value %q: must be true or false", *ready)
+		}
+		opts.Ready = &v
+	}
+
+	var projects []*client.Project
+	for project, err := range a.client.Projects(ctx, opts) {
+		if err != nil {
+			return err
+		}
+		projects = append(projects, project)
+	}
+
+	switch *format {
+	case "table":
+		return a.writeProjectTable(projects)
+	case "json":
+		return writeJSON(a.stdout, projects)
+	case "csv":
+		return writeProjectCSV(a.stdout, projects)
+	default:
+		return fmt.Errorf("unknown format %q: must be table, json or csv", *format)
+	}
+}
+
+// show prints one project's checks with pass/fail markers
+func (a *app) show(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("show", "<project-id|gitlab-id|path>")
+	format := fs.String("format", "table", "Output format: table or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("show takes exactly one project ID")
+	}
+
+	project, err := a.client.GetProject(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "table":
+		defs, err := a.client.ListChecks(ctx)
+		if err != nil {
+			return err
+		}
+		return a.writeChecks(project, defs)
+	case "json":
+		return writeJSON(a.stdout, project)
+	default:
+		return fmt.Errorf("unknown format %q: must be table or json", *format)
+	}
+}
+
+// check gates on readiness: it prints the failing checks of every project
+// that is not ready and returns errNotReady if there were any
+func (a *app) check(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("check", "<project-id>...")
+	quiet := fs.Bool("q", false, "Only set the exit code")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("check requires at least one project ID")
+	}
+
+	defs, err := a.client.ListChecks(ctx)
+	if err != nil {
+		return err
+	}
+
+	allReady := true
+	for _, projectID := range fs.Args() {
+		project, err := a.client.GetProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+
+		failing := project.FailingChecks(defs)
+		if len(failing) > 0 {
+			allReady = false
+		}
+		if *quiet {
+			continue
+		}
+
+		if len(failing) == 0 {
+			fmt.Fprintf(a.stdout, "%s %s is ready\n", a.pass(), project.ProjectID)
+			continue
+		}
+		fmt.Fprintf(a.stdout, "%s %s is not ready: %d failing check(s)\n", a.fail(), project.ProjectID, len(failing))
+		for _, c := range failing {
+			fmt.Fprintf(a.stdout, "    - %s: %s\n", c.Name, c.Description)
+		}
+	}
+
+	if !allReady {
+		return errNotReady
+	}
+	return nil
+}
+
+// gate asks the server's deploy gate, which also honors exemptions and data
+// freshness, and returns errNotReady if it does not pass
+func (a *app) gate(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("gate", "<project-id>")
+	environment := fs.String("environment", "production", "Target environment")
+	maxAge := fs.Duration("max-age", 0, "Require a full scan newer than this, rescanning if needed (e.g. 30m)")
+	format := fs.String("format", "text", "Output format: text or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("gate takes exactly one project ID")
+	}
+
+	result, err := a.client.Gate(ctx, fs.Arg(0), client.GateOptions{
+		Environment: *environment,
+		MaxAge:      *maxAge,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "text":
+		a.writeGate(result)
+	case "json":
+		if err := writeJSON(a.stdout, result); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format %q: must be text or json", *format)
+	}
+
+	if !result.Passed {
+		return errNotReady
+	}
+	return nil
+}
+
+// rescan triggers a synchronous scan and prints the refreshed checks
+func (a *app) rescan(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("rescan", "<project-id>...")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("rescan requires at least one project ID")
+	}
+
+	for _, projectID := range fs.Args() {
+		project, err := a.client.ScanProject(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		failing := len(project.FailingChecks(nil))
+		fmt.Fprintf(a.stdout, "%s %s rescanned: %d failing check(s)\n", a.status(failing == 0), project.ProjectID, failing)
+	}
+	return nil
+}
+
+// report downloads a JUnit or SARIF report, e.g. for a GitLab CI artifact
+func (a *app) report(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("report", "<project-id>")
+	format := fs.String("format", client.ReportJUnit, "Report format: junit or sarif")
+	environment := fs.String("environment", "production", "Environment whose exemptions apply")
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("report takes exactly one project ID")
+	}
+
+	data, err := a.client.ProjectReport(ctx, fs.Arg(0), *format, *environment)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = a.stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
+
+// importProjects bulk creates or updates projects from a CSV or NDJSON file
+func (a *app) importProjects(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("import", "<file|->")
+	format := fs.String("format", "", "File format: csv or ndjson (default from the file extension, else csv)")
+	partial := fs.Bool("partial", false, "Import valid rows even when others fail")
+	onConflict := fs.String("on-conflict", "skip", "Existing projects: skip, update or fail")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("import takes exactly one file")
+	}
+
+	path := fs.Arg(0)
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = client.ImportCSV
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ndjson", ".jsonl":
+			*format = client.ImportNDJSON
+		}
+	}
+
+	summary, err := a.client.ImportProjects(ctx, data, client.ImportOptions{
+		Format:     *format,
+		Partial:    *partial,
+		OnConflict: *onConflict,
+	})
+	if summary != nil {
+		a.writeImportSummary(summary)
+	}
+	if err != nil {
+		return err
+	}
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d row(s) failed to import", summary.Failed)
+	}
+	return nil
+}
+
+// export downloads the full inventory, streaming it to a file or stdout
+func (a *app) export(ctx context.Context, args []string) error {
+	fs := a.newFlagSet("export", "")
+	format := fs.String("format", "", "Export format: csv, ndjson or xlsx (default from -o's extension, else csv)")
+	ready := fs.String("ready", "", "Only ready (true) or not ready (false) projects")
+	failing := fs.String("failing", "", "Only projects failing the named check, e.g. codeowners_exists")
+	output := fs.String("o", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := client.ExportOptions{Format: *format, Failing: *failing}
+	if *ready != "" {
+		v, err := strconv.ParseBool(*ready)
+		if err != nil {
+			return fmt.Errorf("invalid -ready value %q", *ready)
+		}
+		opts.Ready = &v
+	}
+	if opts.Format == "" && *output != "" {
+		switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(*output), ".")); ext {
+		case client.ExportNDJSON, client.ExportXLSX:
+			opts.Format = ext
+		case "jsonl":
+			opts.Format = client.ExportNDJSON
+		}
+	}
+
+	if *output == "" {
+		_, err := a.client.ExportProjects(ctx, a.stdout, opts)
+		return err
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if _, err := a.client.ExportProjects(ctx, f, opts); err != nil {
+		f.Close()
+		os.Remove(*output)
+		return err
+	}
+	return f.Close()
+}
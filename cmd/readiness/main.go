@@ -0,0 +1,158 @@
+// Command readiness is a command-line client for the Project Readiness API.
+//
+// It is meant for both engineers at a terminal and CI jobs: `readiness check`
+// exits non-zero when a project is not production ready so it can be used as
+// a pipeline gate.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/user/go-backend/pkg/client"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitNotReady = 1 // One or more projects failed readiness
+	exitError    = 2 // Usage, network or server errors
+)
+
+// errNotReady is returned by commands that gate on readiness
+var errNotReady = errors.New("not ready")
+
+const usage = `Usage: readiness [global flags] <command> [flags] [args]
+
+Commands:
+  list     List projects as a table, JSON or CSV
+  show     Show a project's readiness checks
+  check    Exit 1 unless every given project is ready
+  gate     Exit 1 unless the deploy gate passes for an environment
+  rescan   Re-evaluate a project's checks against GitLab
+  report   Write a JUnit or SARIF report for CI artifacts
+  import   Create or update projects from a CSV or NDJSON file
+  export   Download every project as CSV, NDJSON or XLSX
+
+Global flags:
+`
+
+type app struct {
+	client  *client.Client
+	stdout  io.Writer
+	stderr  io.Writer
+	noColor bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("readiness", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		global.PrintDefaults()
+	}
+
+	server := global.String("server", envOr("READINESS_URL", "http://localhost:8080"), "API base URL (env READINESS_URL)")
+	instance := global.String("instance", os.Getenv("READINESS_INSTANCE"), "GitLab instance of the projects; the server's default when empty (env READINESS_INSTANCE)")
+	noColor := global.Bool("no-color", os.Getenv("NO_COLOR") != "", "Disable colored output (env NO_COLOR)")
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+
+	if global.NArg() == 0 {
+		global.Usage()
+		return exitError
+	}
+
+	c, err := client.New(client.Config{BaseURL: *server, UserAgent: "readiness-cli/1.0", Instance: *instance})
+	if err != nil {
+		fmt.Fprintf(stderr, "readiness: %v\n", err)
+		return exitError
+	}
+
+	a := &app{
+		client:  c,
+		stdout:  stdout,
+		stderr:  stderr,
+		noColor: *noColor || !isTerminal(stdout),
+	}
+
+	command, rest := global.Arg(0), global.Args()[1:]
+
+	var cmdErr error
+	switch command {
+	case "list":
+		cmdErr = a.list(ctx, rest)
+	case "show":
+		cmdErr = a.show(ctx, rest)
+	case "check":
+		cmdErr = a.check(ctx, rest)
+	case "gate":
+		cmdErr = a.gate(ctx, rest)
+	case "rescan":
+		cmdErr = a.rescan(ctx, rest)
+	case "report":
+		cmdErr = a.report(ctx, rest)
+	case "import":
+		cmdErr = a.importProjects(ctx, rest)
+	case "export":
+		cmdErr = a.export(ctx, rest)
+	case "help":
+		global.Usage()
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "readiness: unknown command %q\n\n", command)
+		global.Usage()
+		return exitError
+	}
+
+	switch {
+	case cmdErr == nil:
+		return exitOK
+	case errors.Is(cmdErr, errNotReady):
+		return exitNotReady
+	case errors.Is(cmdErr, flag.ErrHelp):
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "readiness: %v\n", cmdErr)
+		return exitError
+	}
+}
+
+func envOr(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// isTerminal reports whether w is an interactive terminal, so colors are not
+// written into CI logs or pipes
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
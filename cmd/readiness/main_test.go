@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/handlers"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository/repotest"
+	"github.com/user/go-backend/internal/router"
+)
+
+func setupServer(t *testing.T) string {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := repotest.NewProjectRepository()
+	exemptions := repotest.NewExemptionRepository()
+	srv := httptest.NewServer(router.New(router.Handlers{
+		Project: handlers.NewProjectHandler(repo, logger),
+		Gate:    handlers.NewGateHandler(repo, exemptions, nil, logger),
+		Scan:    handlers.NewScanHandler(nil, logger),
+		Check:   handlers.NewCheckHandler(checks.NewProfiles(nil), logger),
+	}, logger))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	ready := &models.Project{
+		ProjectID: "ready",
+		Results: map[string]bool{
+			"project_present":              true,
+			"app_name_set":                 true,
+			"moab_id_set":                  true,
+			"codeowners_exists":            true,
+			"codeowners_valid":             true,
+			"codeowners_catch_all":         true,
+			"branch_protection_enabled":    true,
+			"codeowner_approval_required":  true,
+			"push_merge_restricted":        true,
+			"force_push_disabled":          true,
+			"release_tags_protected":       true,
+			"push_rules_enabled":           true,
+			"signed_commits_required":      true,
+			"min_approvals_required":       true,
+			"author_approval_prevented":    true,
+			"committer_approval_prevented": true,
+			"approvals_removed_on_commit":  true,
+			"pipeline_success_required":    true,
+			"threads_resolved_required":    true,
+			"visibility_not_public":        true,
+			"credentials_expire":           true,
+			"owners_limited":               true,
+			"maintainers_limited":          true,
+			"external_writers_absent":      true,
+			"blocked_members_absent":       true,
+			"sast_enabled":                 true,
+			"secret_detection_enabled":     true,
+			"dependency_scanning_enabled":  true,
+			"container_scanning_enabled":   true,
+		},
+	}
+	for _, p := range []*models.Project{ready, {ProjectID: "partial", Results: map[string]bool{"project_present": true}}} {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+
+	return srv.URL
+}
+
+func runCLI(t *testing.T, server string, args ...string) (int, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), append([]string{"-server", server}, args...), &stdout, &stderr)
+	return code, stdout.String() + stderr.String()
+}
+
+func TestCheckExitCodes(t *testing.T) {
+	server := setupServer(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+	}{
+		{"ready project", []string{"check", "ready"}, exitOK, "ready is ready"},
+		{"not ready project", []string{"check", "partial"}, exitNotReady, "codeowners_exists"},
+		{"any not ready fails", []string{"check", "ready", "partial"}, exitNotReady, "partial is not ready"},
+		{"missing project", []string{"check", "nope"}, exitError, "project_id not found"},
+		{"no arguments", []string{"check"}, exitError, "Usage"},
+		{"gate passes", []string{"gate", "ready"}, exitOK, "ready may deploy to production"},
+		{"gate fails", []string{"gate", "-environment", "staging", "partial"}, exitNotReady, "may not deploy to staging"},
+		{"rescan without scanner", []string{"rescan", "ready"}, exitError, "Scanning is not configured"},
+		{"unknown command", []string{"frobnicate"}, exitError, "unknown command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, out := runCLI(t, server, tt.args...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d\n%s", code, tt.wantCode, out)
+			}
+			if !strings.Contains(out, tt.wantOut) {
+				t.Errorf("output does not contain %q:\n%s", tt.wantOut, out)
+			}
+		})
+	}
+}
+
+func TestListFormats(t *testing.T) {
+	server := setupServer(t)
+
+	code, out := runCLI(t, server, "list", "-format", "csv", "-ready", "false")
+	if code != exitOK {
+		t.Fatalf("exit code = %d\n%s", code, out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "instance,project_id,ready,project_present") || !strings.HasPrefix(lines[1], "default,partial,false,true") {
+		t.Errorf("unexpected CSV output:\n%s", out)
+	}
+
+	code, out = runCLI(t, server, "list")
+	if code != exitOK || !strings.Contains(out, "29/29") || !strings.Contains(out, "1/29") {
+		t.Errorf("unexpected table output (exit %d):\n%s", code, out)
+	}
+
+	code, out = runCLI(t, server, "show", "partial")
+	if code != exitOK || !strings.Contains(out, "FAIL  app_name_set") || strings.Contains(out, "\033[") {
+		t.Errorf("unexpected show output (exit %d):\n%s", code, out)
+	}
+}
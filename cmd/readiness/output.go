@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/user/go-backend/pkg/client"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorBold  = "\033[1m"
+)
+
+func (a *app) colorize(color, s string) string {
+	if a.noColor {
+		return s
+	}
+	return color + s + colorReset
+}
+
+func (a *app) pass() string { return a.colorize(colorGreen, "PASS") }
+func (a *app) fail() string { return a.colorize(colorRed, "FAIL") }
+
+func (a *app) status(passed bool) string {
+	if passed {
+		return a.pass()
+	}
+	return a.fail()
+}
+
+func (a *app) writeProjectTable(projects []*client.Project) error {
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSTANCE\tPROJECT\tREADY\tPASSED\tFAILING\tUPDATED")
+	for _, p := range projects {
+		checks := p.CheckIDs()
+		failing := p.FailingChecks(nil)
+
+		failingNames := "-"
+		if len(failing) > 0 {
+			failingNames = failing[0].Name
+			if len(failing) > 1 {
+				failingNames += fmt.Sprintf(" (+%d more)", len(failing)-1)
+			}
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%s\t%s\n",
+			p.Instance,
+			p.ProjectID,
+			a.status(len(failing) == 0),
+			len(checks)-len(failing), len(checks),
+			failingNames,
+			p.UpdatedAt.Format(time.RFC3339),
+		)
+	}
+	return tw.Flush()
+}
+
+// writeChecks prints the project's checks, described by defs, by category
+func (a *app) writeChecks(p *client.Project, defs []*client.CheckDefinition) error {
+	fmt.Fprintf(a.stdout, "%s %s\n", a.colorize(colorBold, "Project"), p.ProjectID)
+	if p.PathWithNamespace != "" {
+		fmt.Fprintf(a.stdout, "%s (%s)\n", p.PathWithNamespace, p.WebURL)
+	}
+	if p.Archived {
+		fmt.Fprintln(a.stdout, "Archived")
+	}
+	if p.Profile != "" {
+		fmt.Fprintf(a.stdout, "Profile %s\n", p.Profile)
+	}
+	fmt.Fprintf(a.stdout, "Updated %s\n", p.UpdatedAt.Format(time.RFC3339))
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	category := ""
+	for _, c := range p.Checks(defs) {
+		if c.Category != category {
+			category = c.Category
+			fmt.Fprintf(tw, "\n%s\n", a.colorize(colorBold, category))
+		}
+		description := c.Description
+		if !c.Passed && c.Reason != "" {
+			description += ": " + c.Reason
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", a.status(c.Passed), c.Name, description)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	failing := len(p.FailingChecks(nil))
+	if failing == 0 {
+		fmt.Fprintf(a.stdout, "\n%s all checks passed\n", a.pass())
+	} else {
+		fmt.Fprintf(a.stdout, "\n%s %d check(s) failing\n", a.fail(), failing)
+	}
+	return nil
+}
+
+func (a *app) writeGate(r *client.GateResult) {
+	verdict := "may deploy to"
+	if !r.Passed {
+		verdict = "may not deploy to"
+	}
+	fmt.Fprintf(a.stdout, "%s %s %s %s\n", a.status(r.Passed), r.ProjectID, verdict, r.Environment)
+
+	for _, c := range r.FailingChecks {
+		fmt.Fprintf(a.stdout, "    %s %s: %s\n", a.fail(), c.Name, c.Description)
+	}
+	for _, c := range r.ExemptedChecks {
+		fmt.Fprintf(a.stdout, "    %s %s: %s\n", a.colorize(colorBold, "EXEMPT"), c.Name, c.Description)
+	}
+
+	f := r.Freshness
+	if f.UpdatedAt.IsZero() {
+		fmt.Fprint(a.stdout, "Never scanned")
+	} else {
+		fmt.Fprintf(a.stdout, "Last scanned %s (%s ago)", f.UpdatedAt.Format(time.RFC3339), time.Duration(f.AgeSeconds)*time.Second)
+	}
+	if f.Rescanned {
+		fmt.Fprint(a.stdout, ", rescanned")
+	}
+	if f.Stale {
+		fmt.Fprint(a.stdout, ", "+a.colorize(colorRed, "stale"))
+	}
+	fmt.Fprintln(a.stdout)
+	if f.ScanError != "" {
+		fmt.Fprintf(a.stdout, "Rescan failed: %s\n", f.ScanError)
+	}
+}
+
+func (a *app) writeImportSummary(s *client.ImportSummary) {
+	verb := "Imported"
+	if !s.Committed {
+		verb = "Rejected"
+	}
+	fmt.Fprintf(a.stdout, "%s %d row(s): %d created, %d updated, %d skipped, %d failed\n",
+		verb, s.Total, s.Created, s.Updated, s.Skipped, s.Failed)
+	for _, e := range s.Errors {
+		id := e.ProjectID
+		if id == "" {
+			id = "-"
+		}
+		fmt.Fprintf(a.stdout, "    %s line %d (%s): %s\n", a.fail(), e.Line, id, e.Error)
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeProjectCSV writes one row per project with a column per check the
+// server reported, in display order
+func writeProjectCSV(w io.Writer, projects []*client.Project) error {
+	cw := csv.NewWriter(w)
+
+	var ids []string
+	for _, p := range projects {
+		for _, id := range p.CheckIDs() {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	header := []string{"instance", "project_id", "ready"}
+	header = append(header, ids...)
+	header = append(header, "created_at", "updated_at")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, p := range projects {
+		row := []string{p.Instance, p.ProjectID, strconv.FormatBool(p.Ready())}
+		for _, id := range ids {
+			row = append(row, strconv.FormatBool(p.Result(id)))
+		}
+		row = append(row, p.CreatedAt.Format(time.RFC3339), p.UpdatedAt.Format(time.RFC3339))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/gitlab/projects": {
+        "/applications": {
             "get": {
-                "description": "Get a paginated list of projects with their readiness status",
+                "description": "List the applications APP_NAME values are expected to name, ordered by name",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,30 +34,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "gitlab"
-                ],
-                "summary": "List projects",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Number of items to return (max 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query"
-                    }
+                    "applications"
                 ],
+                "summary": "List registered applications",
                 "responses": {
                     "200": {
-                        "description": "List of projects with pagination metadata",
+                        "description": "Registered applications",
                         "schema": {
-                            "$ref": "#/definitions/models.PaginatedResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Application"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -68,34 +67,46 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new project with initial readiness checks",
+            "put": {
+                "description": "Upload the registered application list as CSV with a name column and an optional description column. The upload replaces the whole list; any invalid row rejects the file.",
                 "consumes": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "gitlab"
+                    "applications"
                 ],
-                "summary": "Create a new project",
+                "summary": "Replace registered applications",
                 "parameters": [
                     {
-                        "description": "Project data",
-                        "name": "project",
+                        "description": "CSV file",
+                        "name": "file",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Project"
+                            "type": "string"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created project",
+                    "200": {
+                        "description": "Applications replaced",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ApplicationImportSummary"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -104,8 +115,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Project already exists",
+                    "413": {
+                        "description": "File too large",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -119,9 +130,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/gitlab/projects/{id}": {
+        "/checks": {
             "get": {
-                "description": "Get a single project with all readiness check data",
+                "description": "List every readiness check in display order. Each check's ID is the name of its boolean in project JSON and the value accepted by the failing filter, exports, imports and exemptions.",
                 "consumes": [
                     "application/json"
                 ],
@@ -129,23 +140,85 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "gitlab"
+                    "checks"
                 ],
-                "summary": "Get project by ID",
+                "summary": "List readiness checks",
+                "responses": {
+                    "200": {
+                        "description": "Registered checks",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/checks.Definition"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Server-sent event stream of project changes from every instance: project.created, project.updated, project.deleted, project.ready, project.regressed, check.changed and scan.completed. Each event's id is its sequence number; reconnecting with Last-Event-ID (or last_event_id) first replays the events published since, as far back as the outbox retention. The instance filter applies to every other filter; project_id and group combine, so a stream with both receives events for either.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Stream events",
                 "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Only events for these projects; repeat or comma-separate",
+                        "name": "project_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only events for projects on this GitLab instance; the default instance when group is set",
+                        "name": "instance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events for projects in this GitLab group (ID or full path), including subgroups",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this sequence number, for clients that cannot set Last-Event-ID",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this sequence number",
+                        "name": "Last-Event-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Project details with readiness status",
+                        "description": "Stream of events, each sent as JSON data",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.Event"
                         }
                     },
                     "400": {
@@ -154,22 +227,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Project ID not found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
+                    "502": {
+                        "description": "GitLab group lookup failed",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing project's readiness checks",
+            }
+        },
+        "/gitlab/hooks": {
+            "post": {
+                "description": "Endpoint for GitLab project, group and system hooks. Pushes to the default branch rescan the checks reading the files they touched (.gitlab-ci.yml, CODEOWNERS), repository_update rescans every file-based check, and project_update, project_rename and project_transfer rescan every check. project_create registers the project and project_destroy marks it absent. Rescans run in the background; events for unregistered projects are ignored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -179,30 +248,60 @@ const docTemplate = `{
                 "tags": [
                     "gitlab"
                 ],
-                "summary": "Update project",
+                "summary": "Receive GitLab hook",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Secret configured for the hook",
+                        "name": "X-Gitlab-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Updated project data",
-                        "name": "project",
+                        "description": "Hook payload",
+                        "name": "event",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Project"
+                            "$ref": "#/definitions/gitlab.HookEvent"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated project",
+                        "description": "Event handled or ignored",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GitLabHookResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Rescan queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GitLabHookResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -211,8 +310,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Project not found",
+                        "description": "Hooks are not configured for the instance",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -224,9 +329,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a project by ID",
+            }
+        },
+        "/gitlab/instances": {
+            "get": {
+                "description": "List the GitLab instances projects can be registered against, with their rate limits and which of scanning, hooks and group sync are configured. Projects of an instance are served under /gitlab/instances/{instance}/projects.",
                 "consumes": [
                     "application/json"
                 ],
@@ -236,19 +343,132 @@ const docTemplate = `{
                 "tags": [
                     "gitlab"
                 ],
-                "summary": "Delete project",
+                "summary": "List GitLab instances",
+                "responses": {
+                    "200": {
+                        "description": "Configured instances",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.GitLabInstance"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects": {
+            "get": {
+                "description": "Get a paginated list of projects with their readiness status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "List projects",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of items to return (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only projects on the named GitLab instance; every instance by default",
+                        "name": "instance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only projects that pass (true) or fail (false) every check",
+                        "name": "ready",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only projects failing the named check, e.g. codeowners_exists",
+                        "name": "failing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only projects whose CODEOWNERS file names the owner, e.g. @platform/leads",
+                        "name": "code_owner",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Project deleted successfully",
+                    "200": {
+                        "description": "List of projects with pagination metadata",
+                        "schema": {
+                            "$ref": "#/definitions/models.PaginatedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new project with initial readiness checks. Projects are on the default GitLab instance unless the body names another.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Create a new project",
+                "parameters": [
+                    {
+                        "description": "Project data",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created project",
                         "schema": {
                             "$ref": "#/definitions/models.SuccessResponse"
                         }
@@ -259,8 +479,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Project ID not found",
+                    "409": {
+                        "description": "Project already exists",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -274,24 +494,1635 @@ const docTemplate = `{
                 }
             }
         },
-        "/health": {
+        "/gitlab/projects/export": {
             "get": {
-                "description": "Check if the API is healthy and running",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Stream the full inventory, honoring the same filters as listing. Columns follow models.Project (instance, project_id, each check, created_at, updated_at) plus a derived ready column; rows are ordered by instance and project_id. CSV and NDJSON exports can be uploaded again through the import endpoint.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "health"
+                    "gitlab"
+                ],
+                "summary": "Export projects",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only projects on the named GitLab instance; every instance by default",
+                        "name": "instance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only projects that pass (true) or fail (false) every check",
+                        "name": "ready",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only projects failing the named check, e.g. codeowners_exists",
+                        "name": "failing",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only projects whose CODEOWNERS file names the owner, e.g. @platform/leads",
+                        "name": "code_owner",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
-                        "description": "Health status",
+                        "description": "Export file",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/import": {
+            "post": {
+                "description": "Upload projects as CSV (a project_id column, an optional instance column and one column per check) or NDJSON (one project per line). Every row is validated first. In atomic mode any invalid row rejects the whole file and nothing is written; in partial mode valid rows are committed and invalid ones reported.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Bulk import projects",
+                "parameters": [
+                    {
+                        "description": "CSV or NDJSON file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Overrides the Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "atomic",
+                            "partial"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Transaction mode",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "skip",
+                            "update",
+                            "fail"
+                        ],
+                        "type": "string",
+                        "default": "skip",
+                        "description": "What to do with existing projects",
+                        "name": "on_conflict",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import summary",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ImportSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Rows failed validation; nothing was imported",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/models.ImportSummary"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}": {
+            "get": {
+                "description": "Get a single project with all readiness check data and its GitLab metadata. The project is found by its project_id, or else by its numeric GitLab ID or full path (with the slashes encoded as %2F) as last seen by a scan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Get project by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID, numeric GitLab ID or URL-encoded full path",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project details with readiness status",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing project's readiness checks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Update project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated project data",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Project"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated project",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a project by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Delete project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Project deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}/exemptions": {
+            "get": {
+                "description": "List every exemption recorded for a project, including expired ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exemptions"
+                ],
+                "summary": "List exemptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Project exemptions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Exemption"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Waive a readiness check for a project, optionally for one environment and until expires_at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exemptions"
+                ],
+                "summary": "Create exemption",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Exemption (check_name and reason are required)",
+                        "name": "exemption",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Exemption"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created exemption",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.Exemption"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}/exemptions/{exemptionID}": {
+            "delete": {
+                "description": "Revoke an exemption",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exemptions"
+                ],
+                "summary": "Delete exemption",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Exemption ID",
+                        "name": "exemptionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Exemption deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Exemption not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}/gate": {
+            "get": {
+                "description": "Decide whether a project may deploy, taking active exemptions and data freshness into account. With max_age set, stale data triggers a synchronous rescan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Deploy gate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "production",
+                        "description": "Target environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Require a full scan newer than this many minutes",
+                        "name": "max_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Seconds to wait for a rescan of stale data (max 10)",
+                        "name": "scan_timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Gate decision",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}/members": {
+            "get": {
+                "description": "List the project's members, directly or through its groups, read live from GitLab, with the owners, maintainers, external users with write access and inactive accounts the access review checks count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Project member report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Member report",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/checks.MemberReview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found, in storage or GitLab",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "GitLab request failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Scanning is not configured for the project's instance",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}/report": {
+            "get": {
+                "description": "Render each readiness check as a JUnit testcase or SARIF result so CI jobs can publish it as an artifact. Failing checks with an active exemption for the environment are reported as skipped/suppressed.",
+                "produces": [
+                    "text/xml",
+                    "application/sarif+json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Project readiness report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "junit",
+                            "sarif"
+                        ],
+                        "type": "string",
+                        "default": "junit",
+                        "description": "Report format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "production",
+                        "description": "Environment whose exemptions apply",
+                        "name": "environment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Report document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects/{id}/scan": {
+            "post": {
+                "description": "Synchronously re-evaluate a project's readiness checks against GitLab",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Rescan project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rescanned project",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Project ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "GitLab request failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Scanning is not configured for the project's instance",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/projects:batch": {
+            "post": {
+                "description": "Apply up to 1000 operations in one transaction. In atomic mode every operation succeeds or none are applied; in best_effort mode each succeeds or fails on its own. Each result carries the status the operation would get as a standalone request; operations rolled back because of another failure get 424. Operations that name no instance are on the default instance. A project may appear only once per batch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Batch write projects",
+                "parameters": [
+                    {
+                        "description": "Operations",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Per-operation results",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BatchResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Too many operations",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Atomic batch rolled back",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.ErrorResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "details": {
+                                            "$ref": "#/definitions/models.BatchResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/sync": {
+            "get": {
+                "description": "Get the reports of GitLab group syncs, newest first: projects registered, projects marked absent and groups that could not be listed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "List group sync runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of items to return (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only runs syncing the named GitLab instance; every instance by default",
+                        "name": "instance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sync runs with pagination metadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.GroupSyncRun"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Sync the configured groups of a GitLab instance now instead of waiting for the schedule. The sync runs in the background; follow it with GET /gitlab/sync/latest.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Start group sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "default",
+                        "description": "GitLab instance",
+                        "name": "instance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Sync queued",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Group sync is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/gitlab/sync/latest": {
+            "get": {
+                "description": "Get the report of the most recent group sync of a GitLab instance, which is still running when it has no finished_at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gitlab"
+                ],
+                "summary": "Get latest group sync run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "default",
+                        "description": "GitLab instance",
+                        "name": "instance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest sync run",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.GroupSyncRun"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "No sync has run",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API is healthy and running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "Health status",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles": {
+            "get": {
+                "description": "List the readiness profiles projects can be held to, the default one first. Each lists the threshold it puts on every measured check; a project's profile takes effect at its next scan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checks"
+                ],
+                "summary": "List readiness profiles",
+                "responses": {
+                    "200": {
+                        "description": "Configured profiles",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/checks.Profile"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/duplicate-moab-ids": {
+            "get": {
+                "description": "List every MOAB ID captured from more than one project's .gitlab-ci.yml at its last scan, with the projects setting it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Duplicate MOAB IDs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only projects on this GitLab instance",
+                        "name": "instance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Duplicate MOAB IDs",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DuplicateMoabID"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/unregistered-app-names": {
+            "get": {
+                "description": "List projects whose APP_NAME, as captured at their last scan, names no registered application. Projects without an APP_NAME are left to the app_name_set check.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Unregistered APP_NAME values",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only projects on this GitLab instance",
+                        "name": "instance",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Projects with unregistered APP_NAME values",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UnregisteredAppName"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "List every webhook subscription. Secrets are not returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WebhookSubscription"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a URL to readiness events. Deliveries are signed with HMAC-SHA256 in X-Readiness-Signature; the secret is generated when omitted and only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhook",
+                "parameters": [
+                    {
+                        "description": "Subscription; an empty events list receives every event",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created webhook, including its secret",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Get a webhook subscription. The secret is not returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a webhook's URL, events, state and description. Sending a secret rotates it; otherwise the current one is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated webhook",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a webhook subscription and its delivery log",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Get a webhook's delivery log, newest first, with the outcome of each delivery's latest attempt",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Only deliveries in this state",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of items to return (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries with pagination metadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.WebhookDelivery"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryID}/redeliver": {
+            "post": {
+                "description": "Queue a new delivery of the same event, e.g. after fixing a receiver. The original delivery stays in the log.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver webhook event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Queued delivery",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.WebhookDelivery"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
@@ -299,35 +2130,876 @@ const docTemplate = `{
         }
     },
     "definitions": {
-        "models.ErrorDetail": {
+        "checks.Definition": {
+            "type": "object",
+            "properties": {
+                "captures": {
+                    "description": "Captures names the value a capturing check reads, such as a CI\nvariable. Profiles may give the format it must have.",
+                    "type": "string",
+                    "example": "MOAB_ID"
+                },
+                "category": {
+                    "type": "string",
+                    "example": "gitlab_presence"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "CODEOWNERS file exists"
+                },
+                "files": {
+                    "description": "Files are the repository files the check reads from the default\nbranch. Pushes changing one of them rescan the check.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "codeowners_exists"
+                },
+                "pipeline": {
+                    "description": "Pipeline is set for checks reading the latest finished pipeline on\nthe default branch. Pipelines finishing there rescan them.",
+                    "type": "boolean"
+                },
+                "remediation": {
+                    "description": "How to make the check pass",
+                    "type": "string"
+                },
+                "severity": {
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "critical"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/checks.Severity"
+                        }
+                    ],
+                    "example": "high"
+                },
+                "threshold": {
+                    "type": "string",
+                    "example": "\u003e= 1"
+                },
+                "unit": {
+                    "description": "Unit is what a measured check counts. Only measured checks take\nthresholds, starting from Threshold unless a profile sets one.",
+                    "type": "string",
+                    "example": "approvals"
+                }
+            }
+        },
+        "checks.MemberReview": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "description": "Members whose account is not active",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "external_writers": {
+                    "description": "External users with Developer or above",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "maintainers": {
+                    "description": "Active users with the Maintainer role",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/checks.ReviewedMember"
+                    }
+                },
+                "owners": {
+                    "description": "Active users with the Owner role",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unverified": {
+                    "description": "Writers whose external status is hidden",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "checks.Profile": {
+            "type": "object",
+            "properties": {
+                "formats": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "moab_id_set": "^MOAB-[0-9]{6}$"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "tier-1"
+                },
+                "protected_branches": {
+                    "description": "ProtectedBranches are branch names or wildcard patterns, as GitLab\nwrites them, that the branch protection checks hold to the same rules\nas the default branch",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "release/*",
+                        "hotfix/*"
+                    ]
+                },
+                "protected_tags": {
+                    "description": "ProtectedTags are the tag names or wildcard patterns releases are\ntagged with, which must be protected. Profiles listing none use\nDefaultReleaseTags.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "v*"
+                    ]
+                },
+                "thresholds": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "min_approvals_required": "\u003e= 2"
+                    }
+                }
+            }
+        },
+        "checks.ReviewedMember": {
+            "type": "object",
+            "properties": {
+                "access_level": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "bot": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-01-31"
+                },
+                "external": {
+                    "description": "External is looked up for active members with write access, and\nonly administrator tokens can see it. It is null where unknown.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "inherited": {
+                    "description": "The role comes from a group, not a direct membership",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "Maintainer"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                }
+            }
+        },
+        "checks.Severity": {
+            "type": "string",
+            "enum": [
+                "low",
+                "medium",
+                "high",
+                "critical"
+            ],
+            "x-enum-varnames": [
+                "SeverityLow",
+                "SeverityMedium",
+                "SeverityHigh",
+                "SeverityCritical"
+            ]
+        },
+        "checks.Status": {
+            "type": "string",
+            "enum": [
+                "pass",
+                "fail",
+                "error",
+                "unknown",
+                "not_applicable"
+            ],
+            "x-enum-comments": {
+                "StatusError": "GitLab refused or failed a request the check needed",
+                "StatusUnknown": "Never evaluated"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "GitLab refused or failed a request the check needed",
+                "Never evaluated",
+                ""
+            ],
+            "x-enum-varnames": [
+                "StatusPass",
+                "StatusFail",
+                "StatusError",
+                "StatusUnknown",
+                "StatusNotApplicable"
+            ]
+        },
+        "gitlab.HookChange": {
+            "type": "object",
+            "properties": {
+                "ref": {
+                    "type": "string"
+                }
+            }
+        },
+        "gitlab.HookCommit": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "modified": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "gitlab.HookEvent": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gitlab.HookChange"
+                    }
+                },
+                "commits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/gitlab.HookCommit"
+                    }
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Project system hooks (project_create, project_update, ...)",
+                    "type": "string"
+                },
+                "object_attributes": {
+                    "description": "Pipeline events",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/gitlab.HookPipeline"
+                        }
+                    ]
+                },
+                "object_kind": {
+                    "type": "string"
+                },
+                "old_path_with_namespace": {
+                    "type": "string"
+                },
+                "path_with_namespace": {
+                    "type": "string"
+                },
+                "project": {
+                    "$ref": "#/definitions/gitlab.HookProject"
+                },
+                "project_id": {
+                    "type": "integer"
+                },
+                "project_path_with_namespace": {
+                    "description": "Project membership system hooks (user_add_to_team, ...), which name\nthe project in their own field",
+                    "type": "string"
+                },
+                "project_visibility": {
+                    "type": "string"
+                },
+                "ref": {
+                    "description": "Push and repository_update events",
+                    "type": "string"
+                },
+                "total_commits_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "gitlab.HookPipeline": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "ref": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "gitlab.HookProject": {
+            "type": "object",
+            "properties": {
+                "default_branch": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "path_with_namespace": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Application": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "payments"
+                }
+            }
+        },
+        "models.ApplicationImportSummary": {
+            "type": "object",
+            "properties": {
+                "applications": {
+                    "description": "Applications now registered",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BatchOperation": {
+            "type": "object",
+            "properties": {
+                "instance": {
+                    "type": "string"
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "upsert",
+                        "delete"
+                    ]
+                },
+                "project": {
+                    "$ref": "#/definitions/models.Project"
+                },
+                "project_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "default": "atomic",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ]
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchOperation"
+                    }
+                }
+            }
+        },
+        "models.BatchResult": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BatchItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CheckChange": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "previous": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CheckResult": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "severity": {
+                    "$ref": "#/definitions/checks.Severity"
+                },
+                "status": {
+                    "$ref": "#/definitions/checks.Status"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DuplicateMoabID": {
+            "type": "object",
+            "properties": {
+                "moab_id": {
+                    "type": "string",
+                    "example": "MOAB-123456"
+                },
+                "projects": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProjectRef"
+                    }
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "details": {
+                    "description": "Structured context, e.g. per-row import errors"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.EventData"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "Sequence orders events as they were published, across instances. It\nis assigned when the event leaves the outbox and is the ID of\nserver-sent events.",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventData": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckChange"
+                    }
+                },
+                "project": {
+                    "$ref": "#/definitions/models.Project"
+                }
+            }
+        },
+        "models.Exemption": {
+            "type": "object",
+            "properties": {
+                "check_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Freshness": {
             "type": "object",
             "properties": {
-                "details": {
-                    "type": "object",
-                    "additionalProperties": true
+                "age_seconds": {
+                    "type": "integer"
                 },
-                "message": {
+                "max_age_seconds": {
+                    "type": "integer"
+                },
+                "rescanned": {
+                    "type": "boolean"
+                },
+                "scan_error": {
+                    "type": "string"
+                },
+                "stale": {
+                    "type": "boolean"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.GateResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "environment": {
+                    "type": "string"
+                },
+                "exempted_checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckResult"
+                    }
+                },
+                "exemptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Exemption"
+                    }
+                },
+                "failing_checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CheckResult"
+                    }
+                },
+                "freshness": {
+                    "$ref": "#/definitions/models.Freshness"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "passed": {
+                    "type": "boolean"
+                },
+                "project_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GitLabHookResult": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "ignore, rescan, create or remove",
+                    "type": "string"
+                },
+                "checks": {
+                    "description": "Checks queued for rescan; empty means every check",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "event": {
+                    "description": "e.g. push or project_update",
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "The instance the hook was received for",
+                    "type": "string"
+                },
+                "project_id": {
+                    "description": "The stored project acted on",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Why the event was ignored",
+                    "type": "string"
+                }
+            }
+        },
+        "models.GitLabInstance": {
+            "type": "object",
+            "properties": {
+                "burst": {
                     "type": "integer"
                 },
+                "hooks": {
+                    "description": "A hook secret is configured",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "requests_per_second": {
+                    "description": "0 means unthrottled",
+                    "type": "number"
+                },
+                "scanning": {
+                    "description": "A token is configured",
+                    "type": "boolean"
+                },
+                "sync_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://gitlab.com"
+                }
+            }
+        },
+        "models.GroupSyncError": {
+            "type": "object",
+            "properties": {
                 "error": {
-                    "$ref": "#/definitions/models.ErrorDetail"
+                    "type": "string"
                 },
-                "message": {
+                "group": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupSyncRun": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Projects registered by this run",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupSyncError"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "marked_absent": {
+                    "description": "Projects no longer in any group",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "projects_seen": {
+                    "description": "Projects found across the groups",
+                    "type": "integer"
+                },
+                "started_at": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
                 },
-                "timestamp": {
+                "trigger": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportRowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "project_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportSummary": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "False when an atomic import was rolled back",
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ImportRowError"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "atomic or partial",
+                    "type": "string"
+                },
+                "on_conflict": {
+                    "description": "skip, update or fail",
                     "type": "string"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
                 }
             }
         },
@@ -369,57 +3041,78 @@ const docTemplate = `{
         "models.Project": {
             "type": "object",
             "properties": {
-                "app_name_set": {
+                "archived": {
                     "type": "boolean"
                 },
-                "approvals_removed_on_commit": {
-                    "type": "boolean"
-                },
-                "author_approval_prevented": {
-                    "type": "boolean"
+                "code_owners": {
+                    "description": "CodeOwners lists the owners the project's CODEOWNERS file names, as\nits last scan found them. Nil keeps the stored list on update.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "@platform/leads"
+                    ]
                 },
-                "branch_protection_enabled": {
-                    "description": "Branch protection checks",
-                    "type": "boolean"
+                "created_at": {
+                    "type": "string"
                 },
-                "codeowner_approval_required": {
-                    "type": "boolean"
+                "default_branch": {
+                    "type": "string",
+                    "example": "main"
                 },
-                "codeowners_exists": {
-                    "type": "boolean"
+                "gitlab_id": {
+                    "type": "integer",
+                    "example": 42
                 },
-                "committer_approval_prevented": {
-                    "type": "boolean"
+                "instance": {
+                    "description": "GitLab project IDs are only unique within an instance, so projects are\nidentified by both",
+                    "type": "string",
+                    "example": "default"
                 },
-                "created_at": {
-                    "description": "Metadata",
+                "last_scanned_at": {
+                    "description": "LastScannedAt is when a scan last evaluated every check, or nil if\nnone has. Other writes, including rescans of some checks, keep it.",
                     "type": "string"
                 },
-                "force_push_disabled": {
-                    "type": "boolean"
+                "name": {
+                    "type": "string"
                 },
-                "min_approvals_required": {
-                    "type": "boolean"
+                "path_with_namespace": {
+                    "type": "string",
+                    "example": "platform/payments-api"
                 },
-                "moab_id_set": {
-                    "type": "boolean"
+                "profile": {
+                    "description": "Profile names the readiness profile whose thresholds scans hold the\nproject to. Empty is the default profile on create and keeps the\nstored profile on update.",
+                    "type": "string",
+                    "example": "tier-1"
                 },
                 "project_id": {
-                    "description": "ProjectID is the unique identifier from GitLab",
                     "type": "string"
                 },
-                "project_present": {
-                    "description": "GitLab presence checks",
-                    "type": "boolean"
+                "updated_at": {
+                    "type": "string"
                 },
-                "push_merge_restricted": {
-                    "type": "boolean"
+                "visibility": {
+                    "type": "string",
+                    "example": "private"
                 },
-                "push_rules_enabled": {
-                    "description": "Merge request checks",
-                    "type": "boolean"
+                "web_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProjectRef": {
+            "type": "object",
+            "properties": {
+                "instance": {
+                    "type": "string",
+                    "example": "default"
                 },
-                "updated_at": {
+                "path_with_namespace": {
+                    "type": "string",
+                    "example": "platform/payments-api"
+                },
+                "project_id": {
                     "type": "string"
                 }
             }
@@ -441,6 +3134,137 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.UnregisteredAppName": {
+            "type": "object",
+            "properties": {
+                "app_name": {
+                    "type": "string",
+                    "example": "paymnets"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "path_with_namespace": {
+                    "type": "string",
+                    "example": "platform/payments-api"
+                },
+                "project_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "project_id": {
+                    "type": "string"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Events lists the event types to deliver; empty delivers every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "description": "Secret is only returned when the subscription is created or rotated",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "default": true
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Secret is generated when empty on create, and kept when empty on update",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -451,8 +3275,8 @@ var SwaggerInfo = &swag.Spec{
 	Host:             "localhost:8080",
 	BasePath:         "/api/v1",
 	Schemes:          []string{"http", "https"},
-	Title:            "GitLab Project Readiness API",
-	Description:      "API for tracking GitLab project production readiness checks",
+	Title:            "Project Readiness API",
+	Description:      "API for tracking project production readiness",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -0,0 +1,79 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// ReadApplications parses a CSV list of registered applications: a name
+// column and an optional description column. Unlike project imports the
+// list is replaced as a whole, so any invalid row fails the file.
+func ReadApplications(r io.Reader, maxRows int) ([]*models.Application, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	nameCol, descriptionCol := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))) {
+		case "name":
+			if nameCol >= 0 {
+				return nil, errors.New(`duplicate column "name"`)
+			}
+			nameCol = i
+		case "description":
+			if descriptionCol >= 0 {
+				return nil, errors.New(`duplicate column "description"`)
+			}
+			descriptionCol = i
+		default:
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	if nameCol < 0 {
+		return nil, errors.New("missing required column name")
+	}
+
+	var apps []*models.Application
+	seen := make(map[string]int)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(apps) == maxRows {
+			return nil, fmt.Errorf("%w: at most %d are allowed", ErrTooManyRows, maxRows)
+		}
+		line, _ := cr.FieldPos(0)
+
+		app := &models.Application{Name: strings.TrimSpace(record[nameCol])}
+		if descriptionCol >= 0 {
+			app.Description = strings.TrimSpace(record[descriptionCol])
+		}
+		if app.Name == "" {
+			return nil, fmt.Errorf("line %d: name is required", line)
+		}
+		if first, ok := seen[app.Name]; ok {
+			return nil, fmt.Errorf("line %d: duplicate name %q, first seen on line %d", line, app.Name, first)
+		}
+		seen[app.Name] = line
+		apps = append(apps, app)
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,64 @@
+// Package bulk reads and writes projects in the flat file formats used for
+// onboarding and reporting: CSV with one column per readiness check,
+// newline-delimited JSON with one project per line, and (export only) XLSX.
+package bulk
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+// ParseFormat accepts a format name as used in the format query parameter
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON, "jsonl":
+		return FormatNDJSON, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unknown format %q: must be csv, ndjson or xlsx", name)
+	}
+}
+
+// FormatForContentType maps a request Content-Type to a format, returning
+// false for media types we do not read
+func FormatForContentType(contentType string) (Format, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return FormatCSV, true
+	case "application/x-ndjson", "application/ndjson", "application/jsonl", "application/x-jsonlines":
+		return FormatNDJSON, true
+	}
+	return "", false
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Extension is the file extension for downloads, without the dot
+func (f Format) Extension() string {
+	return string(f)
+}
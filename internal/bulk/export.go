@@ -0,0 +1,112 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+// metadataColumns hold models.ProjectMetadata, which comes from GitLab and
+// so is read-only
+var metadataColumns = []string{"gitlab_id", "name", "path_with_namespace", "default_branch", "web_url", "visibility", "archived"}
+
+// Columns is the export column order: the fields of a project's JSON in
+// order, with one column per registered check, followed by the derived ready
+// column. Import reads the same layout, ignoring the read-only columns.
+func Columns() []string {
+	columns := []string{"instance", "project_id", "profile"}
+	columns = append(columns, checks.IDs()...)
+	columns = append(columns, metadataColumns...)
+	return append(columns, "created_at", "updated_at", "ready")
+}
+
+// metadataFields returns the project's metadata in metadataColumns order.
+// Projects never found in GitLab have no ID.
+func metadataFields(m models.ProjectMetadata) []string {
+	id := ""
+	if m.GitLabID != 0 {
+		id = strconv.Itoa(m.GitLabID)
+	}
+	return []string{id, m.Name, m.PathWithNamespace, m.DefaultBranch, m.WebURL, m.Visibility, strconv.FormatBool(m.Archived)}
+}
+
+// Writer writes projects one at a time so exports never hold the whole
+// inventory in memory. Close must be called to complete the file.
+type Writer interface {
+	Write(project *models.Project) error
+	Close() error
+}
+
+// NewWriter returns a Writer for format. The header, if the format has one,
+// is written before the first project or on Close.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (c *csvWriter) header() error {
+	if c.wroteHeader {
+		return nil
+	}
+	c.wroteHeader = true
+	return c.w.Write(Columns())
+}
+
+func (c *csvWriter) Write(p *models.Project) error {
+	if err := c.header(); err != nil {
+		return err
+	}
+
+	record := []string{p.Key().Instance, p.ProjectID, p.Profile}
+	for _, check := range p.Checks() {
+		record = append(record, strconv.FormatBool(check.Passed))
+	}
+	record = append(record, metadataFields(p.ProjectMetadata)...)
+	record = append(record,
+		p.CreatedAt.UTC().Format(time.RFC3339),
+		p.UpdatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatBool(p.Ready()),
+	)
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	if err := c.header(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonWriter writes each project as its API JSON representation, which
+// is also what import accepts
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(p *models.Project) error {
+	return n.enc.Encode(p)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
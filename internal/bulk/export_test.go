@@ -0,0 +1,173 @@
+package bulk
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+func testProjects() []*models.Project {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []*models.Project{
+		{
+			ProjectID:       "a",
+			Profile:         "tier-1",
+			Results:         map[string]bool{"codeowners_exists": true},
+			ProjectMetadata: models.ProjectMetadata{GitLabID: 42, PathWithNamespace: "platform/a"},
+			CreatedAt:       at,
+			UpdatedAt:       at,
+		},
+		{ProjectID: `b<&>"`, Results: map[string]bool{"force_push_disabled": true}, CreatedAt: at, UpdatedAt: at},
+	}
+}
+
+func writeAll(t *testing.T, format Format, projects []*models.Project) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("NewWriter(%s) error = %v", format, err)
+	}
+	for _, p := range projects {
+		if err := w.Write(p); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestColumns(t *testing.T) {
+	columns := Columns()
+	if len(columns) != len(checks.IDs())+len(metadataColumns)+6 {
+		t.Fatalf("Columns() has %d entries", len(columns))
+	}
+	for _, name := range metadataColumns {
+		if !readOnlyColumns[name] {
+			t.Errorf("metadata column %s is not read-only", name)
+		}
+	}
+	if columns[0] != "instance" || columns[1] != "project_id" || columns[2] != "profile" || columns[3] != "project_present" || columns[len(columns)-1] != "ready" {
+		t.Errorf("Columns() = %v", columns)
+	}
+}
+
+// Exports must be accepted by import unchanged
+func TestExportRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatNDJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			data := writeAll(t, format, testProjects())
+
+			rows, err := Read(bytes.NewReader(data), format, 10, "")
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if len(rows) != 2 {
+				t.Fatalf("Read() returned %d rows, want 2", len(rows))
+			}
+			for _, row := range rows {
+				if row.Err != nil {
+					t.Errorf("line %d: %v", row.Line, row.Err)
+				}
+			}
+			if !rows[0].Project.Result("codeowners_exists") || rows[0].Project.Profile != "tier-1" || rows[1].Project.ProjectID != `b<&>"` || !rows[1].Project.Result("force_push_disabled") {
+				t.Errorf("round trip lost data: %+v, %+v", rows[0].Project, rows[1].Project)
+			}
+		})
+	}
+}
+
+func TestCSVWriter_Empty(t *testing.T) {
+	data := writeAll(t, FormatCSV, nil)
+	if got := strings.TrimSpace(string(data)); got != strings.Join(Columns(), ",") {
+		t.Errorf("empty export = %q, want header only", got)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	data := writeAll(t, FormatXLSX, testProjects())
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("export is not a zip: %v", err)
+	}
+
+	parts := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		parts[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		body, ok := parts[name]
+		if !ok {
+			t.Errorf("missing part %s", name)
+			continue
+		}
+		dec := xml.NewDecoder(bytes.NewReader(body))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("%s is not well-formed XML: %v", name, err)
+				break
+			}
+		}
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+		AutoFilter struct {
+			Ref string `xml:"ref,attr"`
+		} `xml:"autoFilter"`
+	}
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatalf("failed to parse worksheet: %v", err)
+	}
+
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("worksheet has %d rows, want 3", len(sheet.Rows))
+	}
+	if got := sheet.Rows[2].Cells[1].Inline; got != `b<&>"` {
+		t.Errorf("project ID cell = %q", got)
+	}
+	if c := sheet.Rows[1].Cells[6]; c.Ref != "G2" || c.Type != "b" || c.Value != "1" {
+		t.Errorf("codeowners_exists cell = %+v", c)
+	}
+	if c := sheet.Rows[1].Cells[len(checks.IDs())+3]; c.Value != "42" || c.Type != "" {
+		t.Errorf("gitlab_id cell = %+v", c)
+	}
+	if sheet.AutoFilter.Ref != "A1:"+columnName(len(Columns())-1)+"3" {
+		t.Errorf("autoFilter ref = %q", sheet.AutoFilter.Ref)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for col, want := range tests {
+		if got := columnName(col); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", col, got, want)
+		}
+	}
+}
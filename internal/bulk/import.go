@@ -0,0 +1,280 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+// maxLineBytes bounds a single NDJSON line
+const maxLineBytes = 1 << 20
+
+// readOnlyColumns appear in exported CSV files and are ignored on import so
+// an export can be edited and uploaded again
+var readOnlyColumns = map[string]bool{
+	"gitlab_id":           true,
+	"name":                true,
+	"path_with_namespace": true,
+	"default_branch":      true,
+	"web_url":             true,
+	"visibility":          true,
+	"archived":            true,
+	"ready":               true,
+	"created_at":          true,
+	"updated_at":          true,
+}
+
+// Row is one project read from an import file. Err is set when the row is
+// invalid, in which case Project may be nil.
+type Row struct {
+	Line    int
+	Project *models.Project
+	Err     error
+}
+
+// ProjectID returns the row's project ID, if it got far enough to have one
+func (r Row) ProjectID() string {
+	if r.Project == nil {
+		return ""
+	}
+	return r.Project.ProjectID
+}
+
+// ErrTooManyRows is returned when a file has more than the allowed rows
+var ErrTooManyRows = errors.New("too many rows")
+
+// Read parses every row of an import file. Rows that do not name a GitLab
+// instance are on instance, or the default instance when it is empty.
+// Problems with individual rows are reported on the row; an error is
+// returned only when the file as a whole cannot be read, such as a bad CSV
+// header or more than maxRows rows.
+func Read(r io.Reader, format Format, maxRows int, instance string) ([]Row, error) {
+	var rows []Row
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(r, maxRows)
+	case FormatNDJSON:
+		rows, err = readNDJSON(r, maxRows)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if row.Project != nil {
+			row.Project.Instance = models.NewProjectKey(cmp.Or(strings.TrimSpace(row.Project.Instance), instance), "").Instance
+		}
+	}
+
+	markDuplicates(rows)
+	return rows, nil
+}
+
+func readCSV(r io.Reader, maxRows int) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	// Spreadsheet exports often start with a byte order mark
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	columns, err := csvColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: at most %d are allowed", ErrTooManyRows, maxRows)
+		}
+
+		row := Row{Line: line}
+		if err != nil {
+			row.Err = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+		} else {
+			row.Project, row.Err = csvProject(header, columns, record)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvColumns validates a CSV header, returning the check name for each
+// column. instance, project_id, profile and read-only columns map to an
+// empty name.
+func csvColumns(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool)
+	hasProjectID := false
+
+	for i, name := range header {
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		seen[name] = true
+
+		switch {
+		case name == "project_id":
+			hasProjectID = true
+		case name == "instance", name == "profile":
+		case readOnlyColumns[name]:
+		default:
+			if _, ok := checks.Lookup(name); !ok {
+				return nil, fmt.Errorf("unknown column %q", name)
+			}
+			columns[i] = name
+		}
+	}
+
+	if !hasProjectID {
+		return nil, errors.New("missing project_id column")
+	}
+	return columns, nil
+}
+
+// csvProject builds a project from a record. Check columns left blank or
+// missing from the file are false.
+func csvProject(header, columns []string, record []string) (*models.Project, error) {
+	project := &models.Project{}
+
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		switch {
+		case header[i] == "project_id":
+			project.ProjectID = field
+		case header[i] == "instance":
+			project.Instance = field
+		case header[i] == "profile":
+			project.Profile = field
+		case columns[i] == "" || field == "":
+		default:
+			passed, err := strconv.ParseBool(field)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid boolean %q", columns[i], field)
+			}
+			project.SetCheck(columns[i], passed)
+		}
+	}
+
+	return project, nil
+}
+
+func readNDJSON(r io.Reader, maxRows int) ([]Row, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	var rows []Row
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, fmt.Errorf("%w: at most %d are allowed", ErrTooManyRows, maxRows)
+		}
+
+		row := Row{Line: line}
+		var project models.Project
+		if err := decodeNDJSON(data, &project); err != nil {
+			row.Err = fmt.Errorf("invalid JSON: %w", err)
+		} else {
+			// Metadata and evaluations come from GitLab, so they are
+			// read-only like in CSV
+			project.ProjectMetadata = models.ProjectMetadata{}
+			project.Evaluations = nil
+			row.Project = &project
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+	return rows, nil
+}
+
+// decodeNDJSON decodes one project, rejecting fields an export would not
+// have so that misspelled checks are not silently dropped
+func decodeNDJSON(data []byte, project *models.Project) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	// Scores are derived from the results, so imports ignore them
+	known := map[string]bool{"evaluations": true, "scores": true}
+	for _, name := range Columns() {
+		known[name] = name != "ready"
+	}
+	names := slices.Sorted(maps.Keys(fields))
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("unknown field %q", name)
+		}
+	}
+
+	return json.Unmarshal(data, project)
+}
+
+// markDuplicates rejects rows without a project ID and every repeat of a
+// project on the same instance after its first valid occurrence
+func markDuplicates(rows []Row) {
+	firstLine := make(map[models.ProjectKey]int)
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Err != nil {
+			continue
+		}
+
+		row.Project.ProjectID = strings.TrimSpace(row.Project.ProjectID)
+		if row.Project.ProjectID == "" {
+			row.Err = errors.New("project_id is required")
+			continue
+		}
+
+		key := row.Project.Key()
+		if first, ok := firstLine[key]; ok {
+			row.Err = fmt.Errorf("duplicate project_id, first seen on line %d", first)
+			continue
+		}
+		firstLine[key] = row.Line
+	}
+}
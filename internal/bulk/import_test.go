@@ -0,0 +1,139 @@
+package bulk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRead_CSV(t *testing.T) {
+	input := "\ufeffproject_id, codeowners_exists,force_push_disabled,ready\n" +
+		"1,true,false,false\n" +
+		"2,,TRUE,\n" +
+		"3,maybe,true,\n" +
+		"1,true,true,\n" +
+		",true,true,\n" +
+		"4,true\n"
+
+	rows, err := Read(strings.NewReader(input), FormatCSV, 100, "")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(rows) != 6 {
+		t.Fatalf("Read() returned %d rows, want 6", len(rows))
+	}
+
+	tests := []struct {
+		line    int
+		wantErr string
+	}{
+		{2, ""},
+		{3, ""},
+		{4, "invalid boolean"},
+		{5, "duplicate project_id, first seen on line 2"},
+		{6, "project_id is required"},
+		{7, "expected 4 fields"},
+	}
+	for i, tt := range tests {
+		row := rows[i]
+		if row.Line != tt.line {
+			t.Errorf("row %d: Line = %d, want %d", i, row.Line, tt.line)
+		}
+		if tt.wantErr == "" && row.Err != nil {
+			t.Errorf("line %d: unexpected error %v", tt.line, row.Err)
+		}
+		if tt.wantErr != "" && (row.Err == nil || !strings.Contains(row.Err.Error(), tt.wantErr)) {
+			t.Errorf("line %d: error = %v, want containing %q", tt.line, row.Err, tt.wantErr)
+		}
+	}
+
+	first := rows[0].Project
+	if first.ProjectID != "1" || !first.Result("codeowners_exists") || first.Result("force_push_disabled") {
+		t.Errorf("line 2 parsed as %+v", first)
+	}
+	if second := rows[1].Project; second.Result("codeowners_exists") || !second.Result("force_push_disabled") {
+		t.Errorf("line 3 parsed as %+v", second)
+	}
+}
+
+func TestRead_CSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr string
+	}{
+		{"unknown column", "project_id,codeowner_exists", "unknown column"},
+		{"missing project_id", "codeowners_exists", "missing project_id"},
+		{"duplicate column", "project_id,project_id", "duplicate column"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.header+"\n"), FormatCSV, 100, "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRead_NDJSON(t *testing.T) {
+	input := `{"project_id":"a","codeowners_exists":true}
+
+{"project_id":"b","codeowner_exists":true}
+not json
+{"project_id":"a"}
+`
+
+	rows, err := Read(strings.NewReader(input), FormatNDJSON, 100, "")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Read() returned %d rows, want 4", len(rows))
+	}
+
+	if rows[0].Err != nil || !rows[0].Project.Result("codeowners_exists") {
+		t.Errorf("line 1 = %+v, %v", rows[0].Project, rows[0].Err)
+	}
+	wantLines := []int{1, 3, 4, 5}
+	for i, row := range rows {
+		if row.Line != wantLines[i] {
+			t.Errorf("row %d: Line = %d, want %d", i, row.Line, wantLines[i])
+		}
+		if i > 0 && row.Err == nil {
+			t.Errorf("line %d: expected an error", row.Line)
+		}
+	}
+}
+
+func TestRead_TooManyRows(t *testing.T) {
+	input := "project_id\n1\n2\n3\n"
+	if _, err := Read(strings.NewReader(input), FormatCSV, 2, ""); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("Read() error = %v, want ErrTooManyRows", err)
+	}
+}
+
+func TestRead_Instance(t *testing.T) {
+	input := "instance,project_id\n" +
+		"onprem,1\n" +
+		",1\n" +
+		"default,1\n"
+
+	rows, err := Read(strings.NewReader(input), FormatCSV, 100, "saas")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// A blank instance takes the import's, so only explicit repeats collide
+	want := []string{"onprem", "saas", "default"}
+	for i, row := range rows {
+		if row.Err != nil {
+			t.Errorf("line %d: unexpected error %v", row.Line, row.Err)
+			continue
+		}
+		if row.Project.Instance != want[i] {
+			t.Errorf("line %d: Instance = %q, want %q", row.Line, row.Project.Instance, want[i])
+		}
+	}
+}
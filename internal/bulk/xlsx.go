@@ -0,0 +1,220 @@
+package bulk
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// The XLSX writer produces the smallest SpreadsheetML package Excel, Numbers
+// and LibreOffice accept. Cells use inline strings rather than a shared
+// string table so the worksheet can be streamed row by row into the zip.
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Projects" sheetId="1" r:id="rId1"/></sheets>
+<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">Projects!$A$1:$%s$%d</definedName></definedNames>
+</workbook>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+// Style 1 is the bold header, style 2 a date-time cell
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>
+</styleSheet>`
+
+const xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>
+<sheetData>`
+
+// excelEpoch is day zero of Excel's 1900 date system, accounting for its
+// fictitious 29 February 1900
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	columns []string
+	row     int
+}
+
+// newXLSXWriter writes the fixed package parts up front and opens the
+// worksheet, which must be the last part so it can be streamed
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	x := &xlsxWriter{zip: zip.NewWriter(w), columns: Columns()}
+
+	for _, part := range []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	} {
+		if err := x.writePart(part.name, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	return x, nil
+}
+
+func (x *xlsxWriter) writePart(name, body string) error {
+	f, err := x.zip.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	_, err = io.WriteString(f, body)
+	return err
+}
+
+func (x *xlsxWriter) header() error {
+	if x.sheet != nil {
+		return nil
+	}
+
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("failed to create worksheet: %w", err)
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.sheet.WriteString(xlsxSheetStart)
+
+	x.startRow()
+	for i, name := range x.columns {
+		x.stringCell(i, name, 1)
+	}
+	return x.endRow()
+}
+
+func (x *xlsxWriter) Write(p *models.Project) error {
+	if err := x.header(); err != nil {
+		return err
+	}
+
+	x.startRow()
+	x.stringCell(0, p.Key().Instance, 0)
+	col := 1
+	x.stringCell(col, p.ProjectID, 0)
+	col++
+	x.stringCell(col, p.Profile, 0)
+	for _, check := range p.Checks() {
+		col++
+		x.boolCell(col, check.Passed)
+	}
+	if p.GitLabID != 0 {
+		x.numberCell(col+1, p.GitLabID)
+	}
+	for i, value := range []string{p.Name, p.PathWithNamespace, p.DefaultBranch, p.WebURL, p.Visibility} {
+		x.stringCell(col+2+i, value, 0)
+	}
+	col += len(metadataColumns)
+	x.boolCell(col, p.Archived)
+	x.timeCell(col+1, p.CreatedAt)
+	x.timeCell(col+2, p.UpdatedAt)
+	x.boolCell(col+3, p.Ready())
+	return x.endRow()
+}
+
+func (x *xlsxWriter) Close() error {
+	if err := x.header(); err != nil {
+		return err
+	}
+
+	lastCol := columnName(len(x.columns) - 1)
+	fmt.Fprintf(x.sheet, `</sheetData><autoFilter ref="A1:%s%d"/></worksheet>`, lastCol, x.row)
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+
+	// The workbook names the filter range, which is only known now
+	if err := x.writePart("xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, lastCol, x.row)); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+func (x *xlsxWriter) startRow() {
+	x.row++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.row)
+}
+
+func (x *xlsxWriter) endRow() error {
+	x.sheet.WriteString(`</row>`)
+	// Surface write errors per row rather than only at Close
+	if x.sheet.Buffered() > 32*1024 {
+		return x.sheet.Flush()
+	}
+	return nil
+}
+
+func (x *xlsxWriter) ref(col int) string {
+	return columnName(col) + strconv.Itoa(x.row)
+}
+
+func (x *xlsxWriter) stringCell(col int, value string, style int) {
+	fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr"`, x.ref(col))
+	if style != 0 {
+		fmt.Fprintf(x.sheet, ` s="%d"`, style)
+	}
+	x.sheet.WriteString(`><is><t>`)
+	xml.EscapeText(x.sheet, []byte(value))
+	x.sheet.WriteString(`</t></is></c>`)
+}
+
+func (x *xlsxWriter) boolCell(col int, value bool) {
+	v := 0
+	if value {
+		v = 1
+	}
+	fmt.Fprintf(x.sheet, `<c r="%s" t="b"><v>%d</v></c>`, x.ref(col), v)
+}
+
+func (x *xlsxWriter) numberCell(col int, value int) {
+	fmt.Fprintf(x.sheet, `<c r="%s"><v>%d</v></c>`, x.ref(col), value)
+}
+
+// timeCell writes a UTC timestamp as an Excel serial date so it sorts and
+// filters as a date
+func (x *xlsxWriter) timeCell(col int, t time.Time) {
+	days := t.UTC().Sub(excelEpoch).Hours() / 24
+	fmt.Fprintf(x.sheet, `<c r="%s" s="2"><v>%s</v></c>`, x.ref(col), strconv.FormatFloat(days, 'f', 6, 64))
+}
+
+// columnName converts a 0-based column index to its letters: 0 is A, 26 is AA
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
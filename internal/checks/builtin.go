@@ -0,0 +1,917 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/go-backend/internal/ciconfig"
+	"github.com/user/go-backend/internal/codeowners"
+	"github.com/user/go-backend/internal/gitlab"
+)
+
+// IDs of the built-in checks
+const (
+	ProjectPresent             = "project_present"
+	AppNameSet                 = "app_name_set"
+	MoabIDSet                  = "moab_id_set"
+	CodeownersExists           = "codeowners_exists"
+	CodeownersValid            = "codeowners_valid"
+	CodeownersCatchAll         = "codeowners_catch_all"
+	BranchProtectionEnabled    = "branch_protection_enabled"
+	CodeownerApprovalRequired  = "codeowner_approval_required"
+	PushMergeRestricted        = "push_merge_restricted"
+	ForcePushDisabled          = "force_push_disabled"
+	ReleaseTagsProtected       = "release_tags_protected"
+	PushRulesEnabled           = "push_rules_enabled"
+	SignedCommitsRequired      = "signed_commits_required"
+	MinApprovalsRequired       = "min_approvals_required"
+	AuthorApprovalPrevented    = "author_approval_prevented"
+	CommitterApprovalPrevented = "committer_approval_prevented"
+	ApprovalsRemovedOnCommit   = "approvals_removed_on_commit"
+	PipelineSuccessRequired    = "pipeline_success_required"
+	ThreadsResolvedRequired    = "threads_resolved_required"
+	VisibilityNotPublic        = "visibility_not_public"
+	CredentialsExpire          = "credentials_expire"
+	OwnersLimited              = "owners_limited"
+	MaintainersLimited         = "maintainers_limited"
+	ExternalWritersAbsent      = "external_writers_absent"
+	BlockedMembersAbsent       = "blocked_members_absent"
+	SASTEnabled                = "sast_enabled"
+	SecretDetectionEnabled     = "secret_detection_enabled"
+	DependencyScanningEnabled  = "dependency_scanning_enabled"
+	ContainerScanningEnabled   = "container_scanning_enabled"
+)
+
+// CIConfigPath is the pipeline file the CI variable checks read, unless the
+// project configures another
+const CIConfigPath = ciconfig.DefaultPath
+
+// CodeownersPaths are the locations GitLab looks for a CODEOWNERS file, in order
+var CodeownersPaths = []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+func init() {
+	for _, c := range []Checker{
+		Func(Definition{
+			ID:          ProjectPresent,
+			Category:    CategoryPresence,
+			Description: "Project exists in GitLab",
+			Remediation: "Verify the project ID and that the scanner's token can see the project.",
+			Severity:    SeverityCritical,
+		}, func(_ context.Context, t *Target) (Outcome, error) {
+			// Only found projects are evaluated
+			return Pass("Project found in GitLab as "+t.Project.PathWithNamespace, t.Project), nil
+		}),
+		Func(Definition{
+			ID:          AppNameSet,
+			Category:    CategoryPresence,
+			Description: "APP_NAME variable is set by the CI/CD configuration",
+			Remediation: "Set APP_NAME under the top-level variables: block of .gitlab-ci.yml, a file it includes, or the project's or a parent group's CI/CD variables.",
+			Severity:    SeverityMedium,
+			Files:       []string{CIConfigPath},
+			Captures:    "APP_NAME",
+		}, ciVariable(AppNameSet, "APP_NAME")),
+		Func(Definition{
+			ID:          MoabIDSet,
+			Category:    CategoryPresence,
+			Description: "MOAB_ID variable is set by the CI/CD configuration",
+			Remediation: "Set MOAB_ID under the top-level variables: block of .gitlab-ci.yml, a file it includes, or the project's or a parent group's CI/CD variables.",
+			Severity:    SeverityMedium,
+			Files:       []string{CIConfigPath},
+			Captures:    "MOAB_ID",
+		}, ciVariable(MoabIDSet, "MOAB_ID")),
+		Func(Definition{
+			ID:          CodeownersExists,
+			Category:    CategoryPresence,
+			Description: "CODEOWNERS file exists",
+			Remediation: "Commit a CODEOWNERS file to the repository root, docs/ or .gitlab/, with as many [Section] headers as the project's profile requires.",
+			Severity:    SeverityHigh,
+			Files:       CodeownersPaths,
+			Unit:        "sections",
+		}, evaluateCodeowners),
+		Func(Definition{
+			ID:          CodeownersValid,
+			Category:    CategoryCodeOwners,
+			Description: "CODEOWNERS parses and names only members and groups with access",
+			Remediation: "Fix the lines GitLab cannot parse, and replace owners who are not project members with members or groups the project is shared with.",
+			Severity:    SeverityMedium,
+			Files:       CodeownersPaths,
+		}, evaluateCodeownersValid),
+		Func(Definition{
+			ID:          CodeownersCatchAll,
+			Category:    CategoryCodeOwners,
+			Description: "CODEOWNERS gives every file an owner whose approval is required",
+			Remediation: "Add a * rule with owners to CODEOWNERS, outside any ^[optional] section.",
+			Severity:    SeverityLow,
+			Files:       CodeownersPaths,
+		}, evaluateCodeownersCatchAll),
+		Func(Definition{
+			ID:          BranchProtectionEnabled,
+			Category:    CategoryBranchProtection,
+			Description: "Default branch and the profile's protected branches are protected",
+			Remediation: "Protect the default branch, and the branches the project's profile lists, under Settings > Repository > Protected branches.",
+			Severity:    SeverityCritical,
+		}, branchProtection(func(pb *gitlab.ProtectedBranch) Outcome {
+			return Pass(pb.Name+" is protected", nil)
+		})),
+		Func(Definition{
+			ID:          CodeownerApprovalRequired,
+			Category:    CategoryBranchProtection,
+			Description: "Code owner approval is required on the default branch",
+			Remediation: "Enable \"Require approval from code owners\" on the default branch's protection.",
+			Severity:    SeverityHigh,
+		}, defaultBranchProtection(func(pb *gitlab.ProtectedBranch) Outcome {
+			return Check(pb.CodeOwnerApprovalRequired,
+				"Code owner approval is required on "+pb.Name,
+				"Code owner approval is not required on "+pb.Name, pb)
+		})),
+		Func(Definition{
+			ID:          PushMergeRestricted,
+			Category:    CategoryBranchProtection,
+			Description: "Push and merge are restricted to maintainers",
+			Remediation: "Set \"Allowed to push\" and \"Allowed to merge\" to Maintainers or No one on the default branch and the branches the project's profile lists.",
+			Severity:    SeverityHigh,
+		}, branchProtection(func(pb *gitlab.ProtectedBranch) Outcome {
+			switch {
+			case !restricted(pb.PushAccessLevels):
+				return Fail("Roles below Maintainer can push to "+pb.Name, nil)
+			case !restricted(pb.MergeAccessLevels):
+				return Fail("Roles below Maintainer can merge into "+pb.Name, nil)
+			default:
+				return Pass("Only Maintainers can push to and merge into "+pb.Name, nil)
+			}
+		})),
+		Func(Definition{
+			ID:          ForcePushDisabled,
+			Category:    CategoryBranchProtection,
+			Description: "Force push is disabled on the default branch and the profile's protected branches",
+			Remediation: "Turn off \"Allowed to force push\" on the protection of the default branch and the branches the project's profile lists.",
+			Severity:    SeverityHigh,
+		}, branchProtection(func(pb *gitlab.ProtectedBranch) Outcome {
+			return Check(!pb.AllowForcePush,
+				"Force push is disabled on "+pb.Name,
+				"Force push is allowed on "+pb.Name, nil)
+		})),
+		Func(Definition{
+			ID:          ReleaseTagsProtected,
+			Category:    CategoryBranchProtection,
+			Description: "Release tags are protected and created only by maintainers",
+			Remediation: "Protect the tags releases are cut as, v* unless the project's profile lists others, under Settings > Repository > Protected tags, allowing only Maintainers or No one to create them.",
+			Severity:    SeverityMedium,
+		}, evaluateReleaseTags),
+		Func(Definition{
+			ID:          PushRulesEnabled,
+			Category:    CategoryMergeRequest,
+			Description: "Commit message push rules are enabled",
+			Remediation: "Set a commit message regular expression under Settings > Repository > Push rules.",
+			Severity:    SeverityLow,
+		}, func(ctx context.Context, t *Target) (Outcome, error) {
+			rule, err := t.PushRule(ctx)
+			switch {
+			case err != nil:
+				return Outcome{}, err
+			case rule == nil:
+				return Fail("No push rules are configured", nil), nil
+			case rule.CommitMessageRegex == "":
+				return Fail("Push rules do not restrict commit messages", rule), nil
+			default:
+				return Pass("Commit messages must match "+rule.CommitMessageRegex, rule), nil
+			}
+		}),
+		Func(Definition{
+			ID:          SignedCommitsRequired,
+			Category:    CategoryMergeRequest,
+			Description: "Push rules reject unsigned commits",
+			Remediation: "Enable \"Reject unsigned commits\" under Settings > Repository > Push rules.",
+			Severity:    SeverityMedium,
+		}, func(ctx context.Context, t *Target) (Outcome, error) {
+			rule, err := t.PushRule(ctx)
+			switch {
+			case err != nil:
+				return Outcome{}, err
+			case rule == nil:
+				return Fail("No push rules are configured", nil), nil
+			default:
+				return Check(rule.RejectUnsignedCommits,
+					"Push rules reject unsigned commits",
+					"Push rules accept unsigned commits", rule), nil
+			}
+		}),
+		Func(Definition{
+			ID:          MinApprovalsRequired,
+			Category:    CategoryMergeRequest,
+			Description: "Merge requests require a minimum number of approvals",
+			Remediation: "Add an approval rule requiring as many approvals as the project's profile needs under Settings > Merge requests.",
+			Severity:    SeverityHigh,
+			Unit:        "approvals",
+			Threshold:   &Threshold{Op: ">=", Value: 1},
+		}, evaluateMinApprovals),
+		Func(Definition{
+			ID:          AuthorApprovalPrevented,
+			Category:    CategoryMergeRequest,
+			Description: "Authors cannot approve their own merge requests",
+			Remediation: "Enable \"Prevent approval by author\" under Settings > Merge requests > Approval settings.",
+			Severity:    SeverityHigh,
+		}, approvalSetting(func(cfg *gitlab.ApprovalConfig) Outcome {
+			return Check(!cfg.MergeRequestsAuthorApproval,
+				"Authors cannot approve their own merge requests",
+				"Authors can approve their own merge requests", cfg)
+		})),
+		Func(Definition{
+			ID:          CommitterApprovalPrevented,
+			Category:    CategoryMergeRequest,
+			Description: "Committers cannot approve merge requests they contributed to",
+			Remediation: "Enable \"Prevent approvals by users who add commits\" under Settings > Merge requests > Approval settings.",
+			Severity:    SeverityMedium,
+		}, approvalSetting(func(cfg *gitlab.ApprovalConfig) Outcome {
+			return Check(cfg.MergeRequestsDisableCommittersApproval,
+				"Committers cannot approve merge requests they contributed to",
+				"Committers can approve merge requests they contributed to", cfg)
+		})),
+		Func(Definition{
+			ID:          ApprovalsRemovedOnCommit,
+			Category:    CategoryMergeRequest,
+			Description: "Approvals are reset when new commits are pushed",
+			Remediation: "Enable \"Remove all approvals when commits are added\" under Settings > Merge requests > Approval settings.",
+			Severity:    SeverityMedium,
+		}, approvalSetting(func(cfg *gitlab.ApprovalConfig) Outcome {
+			return Check(cfg.ResetApprovalsOnPush,
+				"Approvals are reset when commits are pushed",
+				"Approvals are kept when commits are pushed", cfg)
+		})),
+		Func(Definition{
+			ID:          PipelineSuccessRequired,
+			Category:    CategoryMergeRequest,
+			Description: "Merge requests can only be merged when their pipeline succeeds",
+			Remediation: "Enable \"Pipelines must succeed\" under Settings > Merge requests > Merge checks.",
+			Severity:    SeverityHigh,
+		}, func(_ context.Context, t *Target) (Outcome, error) {
+			evidence := newMergeChecksEvidence(t.Project)
+			if !evidence.PipelineMustSucceed {
+				return Fail("Merge requests can be merged without a successful pipeline", evidence), nil
+			}
+			if evidence.SkippedPipelineAllowed {
+				return Pass("Merge requests can only be merged when their pipeline succeeds or is skipped", evidence), nil
+			}
+			return Pass("Merge requests can only be merged when their pipeline succeeds", evidence), nil
+		}),
+		Func(Definition{
+			ID:          ThreadsResolvedRequired,
+			Category:    CategoryMergeRequest,
+			Description: "Merge requests can only be merged once all threads are resolved",
+			Remediation: "Enable \"All threads must be resolved\" under Settings > Merge requests > Merge checks.",
+			Severity:    SeverityLow,
+		}, func(_ context.Context, t *Target) (Outcome, error) {
+			evidence := newMergeChecksEvidence(t.Project)
+			return Check(evidence.ThreadsMustBeResolved,
+				"Merge requests can only be merged once all threads are resolved",
+				"Merge requests can be merged with unresolved threads", evidence), nil
+		}),
+		Func(Definition{
+			ID:          VisibilityNotPublic,
+			Category:    CategoryMergeRequest,
+			Description: "Project is not public",
+			Remediation: "Set the project's visibility to Private or Internal under Settings > General > Visibility, project features, permissions.",
+			Severity:    SeverityHigh,
+		}, func(_ context.Context, t *Target) (Outcome, error) {
+			evidence := visibilityEvidence{Visibility: t.Project.Visibility}
+			return Check(t.Project.Visibility != "public",
+				"Project is "+t.Project.Visibility,
+				"Project is public, so anyone can see it without signing in", evidence), nil
+		}),
+		Func(Definition{
+			ID:          CredentialsExpire,
+			Category:    CategoryMergeRequest,
+			Description: "Deploy keys and project access tokens expire",
+			Remediation: "Replace deploy keys and project access tokens that never expire with ones that do, under Settings > Repository > Deploy keys and Settings > Access tokens.",
+			Severity:    SeverityMedium,
+		}, evaluateCredentialsExpire),
+		Func(Definition{
+			ID:          OwnersLimited,
+			Category:    CategoryMemberAccess,
+			Description: "Few enough members have the Owner role",
+			Remediation: "Lower members who do not need the Owner role to Maintainer or below, on the project or the group they inherit it from.",
+			Severity:    SeverityMedium,
+			Unit:        "owners",
+			Threshold:   &Threshold{Op: "<=", Value: 3},
+		}, roleCount(OwnersLimited, "Owner", func(r *MemberReview) []string { return r.Owners })),
+		Func(Definition{
+			ID:          MaintainersLimited,
+			Category:    CategoryMemberAccess,
+			Description: "Few enough members have the Maintainer role",
+			Remediation: "Lower members who do not need the Maintainer role to Developer or below, on the project or the group they inherit it from.",
+			Severity:    SeverityLow,
+			Unit:        "maintainers",
+			Threshold:   &Threshold{Op: "<=", Value: 10},
+		}, roleCount(MaintainersLimited, "Maintainer", func(r *MemberReview) []string { return r.Maintainers })),
+		Func(Definition{
+			ID:          ExternalWritersAbsent,
+			Category:    CategoryMemberAccess,
+			Description: "No external user has write access",
+			Remediation: "Lower external users to Reporter or below, or remove them from the project and the groups they inherit access from.",
+			Severity:    SeverityHigh,
+		}, evaluateExternalWriters),
+		Func(Definition{
+			ID:          BlockedMembersAbsent,
+			Category:    CategoryMemberAccess,
+			Description: "No blocked or deactivated user is a member",
+			Remediation: "Remove blocked, banned and deactivated users from the project and the groups they inherit access from.",
+			Severity:    SeverityLow,
+		}, evaluateBlockedMembers),
+		securityScan(Definition{
+			ID:          SASTEnabled,
+			Description: "SAST runs on the default branch",
+			Severity:    SeverityHigh,
+		}, securityScanner{Name: "SAST", Report: "sast", Template: "Jobs/SAST.gitlab-ci.yml", Disabled: "SAST_DISABLED"}),
+		securityScan(Definition{
+			ID:          SecretDetectionEnabled,
+			Description: "Secret detection runs on the default branch",
+			Severity:    SeverityHigh,
+		}, securityScanner{Name: "Secret detection", Report: "secret_detection", Template: "Jobs/Secret-Detection.gitlab-ci.yml", Disabled: "SECRET_DETECTION_DISABLED"}),
+		securityScan(Definition{
+			ID:          DependencyScanningEnabled,
+			Description: "Dependency scanning runs on the default branch",
+			Severity:    SeverityMedium,
+		}, securityScanner{Name: "Dependency scanning", Report: "dependency_scanning", Template: "Jobs/Dependency-Scanning.gitlab-ci.yml", Disabled: "DEPENDENCY_SCANNING_DISABLED"}),
+		securityScan(Definition{
+			ID:          ContainerScanningEnabled,
+			Description: "Container scanning runs on the default branch",
+			Severity:    SeverityMedium,
+		}, securityScanner{Name: "Container scanning", Report: "container_scanning", Template: "Jobs/Container-Scanning.gitlab-ci.yml", Disabled: "CONTAINER_SCANNING_DISABLED"}),
+	} {
+		Register(c)
+	}
+}
+
+// fileEvidence identifies the file a check read
+type fileEvidence struct {
+	Path string `json:"path"`
+	Ref  string `json:"ref"`
+}
+
+// ciVariableEvidence shows where a CI variable was defined and which
+// configuration was read to find it
+type ciVariableEvidence struct {
+	Variable   *ciconfig.Variable    `json:"variable,omitempty"`
+	Files      []ciconfig.Source     `json:"files"`
+	Unresolved []ciconfig.Unresolved `json:"unresolved,omitempty"`
+}
+
+// CIConfigFiles returns the project's own files its CI/CD configuration is
+// read from, given the evidence of its project_present and CI variable
+// evaluations: the configured path, CIConfigPath unless the project sets
+// another, and the files the configuration was last found to include.
+// Either evidence may be empty.
+func CIConfigFiles(present, variable json.RawMessage) []string {
+	var project gitlab.Project
+	var evidence ciVariableEvidence
+	// Evidence that does not decode, like none, names no files
+	json.Unmarshal(present, &project)
+	json.Unmarshal(variable, &evidence)
+
+	var files []string
+	for _, src := range append([]ciconfig.Source{ciconfig.ParsePath(project.CIConfigPath)}, evidence.Files...) {
+		if src.Kind == ciconfig.SourceFile && src.Project == "" && !slices.Contains(files, src.Path) {
+			files = append(files, src.Path)
+		}
+	}
+	return files
+}
+
+// ciVariable passes when the project's CI/CD configuration, its includes or
+// its CI/CD settings give the variable a value in the format the profile
+// requires. The value is captured whatever the outcome.
+func ciVariable(id, name string) func(context.Context, *Target) (Outcome, error) {
+	return func(ctx context.Context, t *Target) (Outcome, error) {
+		cfg, err := t.CIConfig(ctx)
+		var parseErr *ciconfig.ParseError
+		switch {
+		case errors.Is(err, gitlab.ErrNotFound):
+			return Fail(ciconfig.ParsePath(t.Project.CIConfigPath).String()+" not found on the default branch", nil), nil
+		case errors.As(err, &parseErr):
+			return Fail("CI/CD configuration is invalid: "+parseErr.Error(), parseErr.Source), nil
+		case err != nil:
+			return Outcome{}, err
+		}
+
+		v := cfg.Lookup(name)
+		evidence := ciVariableEvidence{Variable: v, Files: cfg.Files, Unresolved: cfg.Unresolved}
+		if v == nil {
+			return Fail(name+" is not set in the CI/CD configuration, its includes or CI/CD settings", evidence), nil
+		}
+
+		var o Outcome
+		switch format := t.Format(id); {
+		case v.Value == "":
+			o = Fail(fmt.Sprintf("%s is empty in %s", name, v.Source), evidence)
+		case format != nil && !format.MatchString(v.Value):
+			o = Fail(fmt.Sprintf("%s %q does not match %s", name, v.Value, format), evidence)
+		default:
+			o = Pass(fmt.Sprintf("%s is %q in %s", name, v.Value, v.Source), evidence)
+		}
+		o.Captured = v.Value
+		return o, nil
+	}
+}
+
+// codeownersEvidence identifies the CODEOWNERS file found and its sections
+type codeownersEvidence struct {
+	fileEvidence
+	Sections         int      `json:"sections"`
+	OptionalSections []string `json:"optional_sections,omitempty"`
+	Ignored          []string `json:"ignored,omitempty"` // CODEOWNERS files GitLab does not read
+}
+
+// evaluateCodeowners passes when a CODEOWNERS file names owners in the
+// sections the profile asks for, if it asks for any
+func evaluateCodeowners(ctx context.Context, t *Target) (Outcome, error) {
+	co, err := t.Codeowners(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if co == nil {
+		return Fail("No CODEOWNERS file at "+strings.Join(CodeownersPaths, ", "), nil), nil
+	}
+
+	sections := co.NamedSections()
+	evidence := codeownersEvidence{
+		fileEvidence:     fileEvidence{Path: co.Path, Ref: t.Project.DefaultBranch},
+		Sections:         sections,
+		OptionalSections: co.OptionalSections(),
+		Ignored:          co.Ignored,
+	}
+	if len(co.Owners()) == 0 {
+		return Fail("CODEOWNERS at "+co.Path+" names no owners", evidence), nil
+	}
+	return Measure(sections, t.Threshold(CodeownersExists), fmt.Sprintf("CODEOWNERS found at %s with %s", co.Path, plural(sections, "section")), evidence), nil
+}
+
+// codeownersValidityEvidence lists the problems found in a CODEOWNERS file
+type codeownersValidityEvidence struct {
+	fileEvidence
+	Errors           []codeowners.SyntaxError `json:"errors,omitempty"`
+	UnknownOwners    []string                 `json:"unknown_owners,omitempty"`    // Neither members nor groups with access
+	UnverifiedOwners []string                 `json:"unverified_owners,omitempty"` // Email addresses, which the API cannot match to members
+}
+
+// evaluateCodeownersValid passes when CODEOWNERS has no syntax errors and
+// each owner is a project member, a group the project belongs to or is
+// shared with, or a role
+func evaluateCodeownersValid(ctx context.Context, t *Target) (Outcome, error) {
+	co, err := t.Codeowners(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if co == nil {
+		return NotApplicable("No CODEOWNERS file"), nil
+	}
+	members, err := t.Members(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	usernames := make(map[string]bool, len(members))
+	for _, m := range members {
+		if m.State != "blocked" {
+			usernames[strings.ToLower(m.Username)] = true
+		}
+	}
+	groups := make(map[string]bool)
+	if ns := t.Project.Namespace; ns.Kind == "group" {
+		parts := strings.Split(strings.ToLower(ns.FullPath), "/")
+		for i := range parts {
+			groups[strings.Join(parts[:i+1], "/")] = true
+		}
+	}
+	for _, g := range t.Project.SharedWithGroups {
+		groups[strings.ToLower(g.GroupFullPath)] = true
+	}
+
+	evidence := codeownersValidityEvidence{fileEvidence: fileEvidence{Path: co.Path, Ref: t.Project.DefaultBranch}, Errors: co.Errors}
+	for _, owner := range co.Owners() {
+		name := strings.ToLower(strings.TrimPrefix(owner, "@"))
+		switch codeowners.OwnerKind(owner) {
+		case codeowners.OwnerUser:
+			if !usernames[name] && !groups[name] {
+				evidence.UnknownOwners = append(evidence.UnknownOwners, owner)
+			}
+		case codeowners.OwnerGroup:
+			if !groups[name] {
+				evidence.UnknownOwners = append(evidence.UnknownOwners, owner)
+			}
+		case codeowners.OwnerEmail:
+			evidence.UnverifiedOwners = append(evidence.UnverifiedOwners, owner)
+		}
+	}
+
+	var problems []string
+	if n := len(co.Errors); n > 0 {
+		problems = append(problems, plural(n, "syntax error"))
+	}
+	if n := len(evidence.UnknownOwners); n > 0 {
+		problems = append(problems, fmt.Sprintf("%s without access (%s)", plural(n, "owner"), strings.Join(evidence.UnknownOwners, ", ")))
+	}
+	if len(problems) > 0 {
+		return Fail(fmt.Sprintf("CODEOWNERS at %s has %s", co.Path, strings.Join(problems, " and ")), evidence), nil
+	}
+	return Pass(fmt.Sprintf("CODEOWNERS at %s parses and its %s have access", co.Path, plural(len(co.Owners()), "owner")), evidence), nil
+}
+
+// codeownersCatchAllEvidence shows the rule giving every file an owner
+type codeownersCatchAllEvidence struct {
+	fileEvidence
+	Rule             *codeowners.Rule `json:"rule,omitempty"`
+	OptionalSections []string         `json:"optional_sections,omitempty"`
+}
+
+// evaluateCodeownersCatchAll passes when a * rule with owners sits outside
+// the optional sections, so every change needs a code owner's approval
+func evaluateCodeownersCatchAll(ctx context.Context, t *Target) (Outcome, error) {
+	co, err := t.Codeowners(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if co == nil {
+		return NotApplicable("No CODEOWNERS file"), nil
+	}
+
+	evidence := codeownersCatchAllEvidence{
+		fileEvidence:     fileEvidence{Path: co.Path, Ref: t.Project.DefaultBranch},
+		Rule:             co.CatchAll(),
+		OptionalSections: co.OptionalSections(),
+	}
+	if r := evidence.Rule; r != nil {
+		return Pass(fmt.Sprintf("%s is owned by %s on line %d of %s", r.Pattern, strings.Join(r.Owners, ", "), r.Line, co.Path), evidence), nil
+	}
+	for _, s := range co.Sections {
+		if s.Optional && slices.ContainsFunc(s.Rules, codeowners.Rule.CatchAll) {
+			return Fail(fmt.Sprintf("The only catch-all rule in %s is in optional section %s", co.Path, s.Name), evidence), nil
+		}
+	}
+	return Fail("CODEOWNERS at "+co.Path+" has no * rule, so files no rule matches need no code owner approval", evidence), nil
+}
+
+// securityScanner is a GitLab security scanner and how its jobs are found
+type securityScanner struct {
+	Name     string // As GitLab names it, capitalized
+	Report   string // The artifacts:reports type its jobs upload
+	Template string // The CI/CD template adding its jobs
+	Disabled string // The variable turning its jobs off
+}
+
+// securityScan returns a check, in the security scanning category, that
+// the scanner reported in the latest finished default-branch pipeline
+func securityScan(def Definition, s securityScanner) Checker {
+	def.Category = CategorySecurityScanning
+	def.Remediation = fmt.Sprintf("Include the %s template in %s, or add a job uploading a %s report, and run a pipeline on the default branch.", s.Template, CIConfigPath, s.Report)
+	def.Files = []string{CIConfigPath}
+	def.Pipeline = true
+	return Func(def, s.evaluate)
+}
+
+// securityScanEvidence shows the jobs configured to run a scanner and those
+// that reported in the latest pipeline
+type securityScanEvidence struct {
+	Jobs        []string              `json:"jobs,omitempty"`        // Configured jobs uploading the report
+	DisabledBy  *ciconfig.Variable    `json:"disabled_by,omitempty"` // The variable turning the scanner off
+	Unresolved  []ciconfig.Unresolved `json:"unresolved,omitempty"`
+	Pipeline    *gitlab.Pipeline      `json:"pipeline,omitempty"`
+	ReportedBy  []string              `json:"reported_by,omitempty"` // Jobs of the pipeline that uploaded the report
+	ConfigError string                `json:"config_error,omitempty"`
+}
+
+// evaluate passes when a job of the latest finished default-branch pipeline
+// uploaded the scanner's report. Otherwise the CI/CD configuration explains why not:
+// the scanner is turned off, configured but did not run, or missing.
+func (s securityScanner) evaluate(ctx context.Context, t *Target) (Outcome, error) {
+	var evidence securityScanEvidence
+	cfg, err := t.CIConfig(ctx)
+	var parseErr *ciconfig.ParseError
+	switch {
+	case errors.Is(err, gitlab.ErrNotFound):
+		// Auto DevOps runs pipelines without a configuration file
+	case errors.As(err, &parseErr):
+		evidence.ConfigError = parseErr.Error()
+	case err != nil:
+		return Outcome{}, err
+	default:
+		for _, job := range cfg.ReportJobs(s.Report) {
+			evidence.Jobs = append(evidence.Jobs, job.Name)
+		}
+		if v := cfg.Lookup(s.Disabled); v != nil && (v.Value == "true" || v.Value == "1") {
+			evidence.DisabledBy = v
+		}
+		evidence.Unresolved = cfg.Unresolved
+	}
+
+	pipeline, err := t.LatestPipeline(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	jobs, err := t.PipelineJobs(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	evidence.Pipeline = pipeline
+	for _, job := range jobs {
+		if slices.ContainsFunc(job.Artifacts, func(a gitlab.JobArtifact) bool { return a.FileType == s.Report }) {
+			evidence.ReportedBy = append(evidence.ReportedBy, job.Name)
+		}
+	}
+
+	switch {
+	case len(evidence.ReportedBy) > 0:
+		return Pass(fmt.Sprintf("%s reported in pipeline #%d by %s", s.Name, pipeline.ID, strings.Join(evidence.ReportedBy, ", ")), evidence), nil
+	case evidence.DisabledBy != nil:
+		return Fail(fmt.Sprintf("%s is turned off by %s in %s", s.Name, s.Disabled, evidence.DisabledBy.Source), evidence), nil
+	case pipeline == nil && len(evidence.Jobs) > 0:
+		return Fail(fmt.Sprintf("%s is configured in %s, but no pipeline has finished on the default branch", s.Name, strings.Join(evidence.Jobs, ", ")), evidence), nil
+	case pipeline == nil:
+		return Fail(fmt.Sprintf("%s has no job, and no pipeline has finished on the default branch", s.Name), evidence), nil
+	case len(evidence.Jobs) > 0:
+		return Fail(fmt.Sprintf("%s is configured in %s, but pipeline #%d uploaded no %s report", s.Name, strings.Join(evidence.Jobs, ", "), pipeline.ID, s.Report), evidence), nil
+	default:
+		return Fail(fmt.Sprintf("%s has no job, and pipeline #%d uploaded no %s report", s.Name, pipeline.ID, s.Report), evidence), nil
+	}
+}
+
+// plural formats a count of things, adding an s when there is not one
+func plural(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
+
+// branchEvidence is the outcome of a branch protection check on one branch
+type branchEvidence struct {
+	Branch     string                  `json:"branch"` // The default branch or a name or pattern the profile lists
+	Default    bool                    `json:"default,omitempty"`
+	Status     Status                  `json:"status"`
+	Reason     string                  `json:"reason"`
+	Rules      []string                `json:"rules,omitempty"`      // The protected branch rules applying to it
+	Protection *gitlab.ProtectedBranch `json:"protection,omitempty"` // Combined from the rules
+}
+
+// branchProtectionEvidence holds the outcome on each branch checked
+type branchProtectionEvidence struct {
+	Branches []branchEvidence `json:"branches"`
+}
+
+// branchProtection evaluates with decide the protection of the default
+// branch and of each branch the profile lists, failing for any branch that
+// is unprotected. It passes when every branch does.
+func branchProtection(decide func(*gitlab.ProtectedBranch) Outcome) func(context.Context, *Target) (Outcome, error) {
+	onDefault := defaultBranchProtection(decide)
+	return func(ctx context.Context, t *Target) (Outcome, error) {
+		o, err := onDefault(ctx, t)
+		if err != nil {
+			return Outcome{}, err
+		}
+		pb, err := t.DefaultBranchProtection(ctx)
+		if err != nil {
+			return Outcome{}, err
+		}
+		branches := []branchEvidence{{Branch: t.Project.DefaultBranch, Default: true, Status: o.Status, Reason: o.Reason, Protection: pb}}
+		if pb != nil {
+			branches[0].Rules = []string{pb.Name}
+		}
+
+		var patterns []string
+		for _, pattern := range t.Profile.RequiredBranches() {
+			if pattern == t.Project.DefaultBranch {
+				continue
+			}
+			patterns = append(patterns, pattern)
+			pb, rules, err := t.PatternProtection(ctx, pattern)
+			if err != nil {
+				return Outcome{}, err
+			}
+			b := branchEvidence{Branch: pattern, Rules: rules, Protection: pb}
+			if pb == nil {
+				b.Status, b.Reason = StatusFail, pattern+" is not protected"
+			} else {
+				o := decide(pb)
+				b.Status, b.Reason = o.Status, o.Reason
+			}
+			branches = append(branches, b)
+		}
+
+		evidence := branchProtectionEvidence{Branches: branches}
+		if len(branches) == 1 {
+			return Outcome{Status: o.Status, Reason: o.Reason, Evidence: marshalEvidence(evidence)}, nil
+		}
+		var failing []string
+		for _, b := range branches {
+			if !b.Status.Passing() {
+				failing = append(failing, b.Reason)
+			}
+		}
+		if len(failing) > 0 {
+			return Fail(strings.Join(failing, "; "), evidence), nil
+		}
+		return Pass(o.Reason+" (also "+strings.Join(patterns, ", ")+")", evidence), nil
+	}
+}
+
+// defaultBranchProtection evaluates the default branch's protection with
+// decide, failing when the branch is unprotected
+func defaultBranchProtection(decide func(*gitlab.ProtectedBranch) Outcome) func(context.Context, *Target) (Outcome, error) {
+	return func(ctx context.Context, t *Target) (Outcome, error) {
+		pb, err := t.DefaultBranchProtection(ctx)
+		switch {
+		case err != nil:
+			return Outcome{}, err
+		case t.Project.DefaultBranch == "":
+			return Fail("Repository is empty, so it has no default branch to protect", nil), nil
+		case pb == nil:
+			return Fail("Default branch "+t.Project.DefaultBranch+" is not protected", nil), nil
+		default:
+			return decide(pb), nil
+		}
+	}
+}
+
+// restricted reports whether no access level grants push or merge below
+// Maintainer. Levels scoped to a specific user or group are allowed.
+func restricted(levels []gitlab.AccessLevel) bool {
+	if len(levels) == 0 {
+		return false
+	}
+	for _, l := range levels {
+		if l.UserID != nil || l.GroupID != nil {
+			continue
+		}
+		if l.AccessLevel != gitlab.AccessNoOne && l.AccessLevel < gitlab.AccessMaintainer {
+			return false
+		}
+	}
+	return true
+}
+
+// tagEvidence is the outcome of the release tag check on one tag pattern
+type tagEvidence struct {
+	Tag    string   `json:"tag"`
+	Status Status   `json:"status"`
+	Reason string   `json:"reason"`
+	Rules  []string `json:"rules,omitempty"` // The protected tag rules applying to it
+}
+
+// tagProtectionEvidence holds the outcome on each release tag checked
+type tagProtectionEvidence struct {
+	Tags []tagEvidence `json:"tags"`
+}
+
+// evaluateReleaseTags passes when every release tag the profile lists is
+// protected and, as with branches, the most permissive rule covering it
+// lets no role below Maintainer create it
+func evaluateReleaseTags(ctx context.Context, t *Target) (Outcome, error) {
+	patterns := t.Profile.ReleaseTags()
+	evidence := tagProtectionEvidence{Tags: []tagEvidence{}}
+	var failing []string
+	for _, pattern := range patterns {
+		rules, err := t.TagProtection(ctx, pattern)
+		if err != nil {
+			return Outcome{}, err
+		}
+		tag := tagEvidence{Tag: pattern, Status: StatusPass, Reason: "Only Maintainers can create tags matching " + pattern}
+		var levels []gitlab.AccessLevel
+		for _, rule := range rules {
+			tag.Rules = append(tag.Rules, rule.Name)
+			levels = append(levels, rule.CreateAccessLevels...)
+		}
+		switch {
+		case len(rules) == 0:
+			tag.Status, tag.Reason = StatusFail, "Tags matching "+pattern+" are not protected"
+		case !restricted(levels):
+			tag.Status, tag.Reason = StatusFail, "Roles below Maintainer can create tags matching "+pattern
+		}
+		if tag.Status == StatusFail {
+			failing = append(failing, tag.Reason)
+		}
+		evidence.Tags = append(evidence.Tags, tag)
+	}
+	if len(failing) > 0 {
+		return Fail(strings.Join(failing, "; "), evidence), nil
+	}
+	return Pass("Only Maintainers can create tags matching "+strings.Join(patterns, ", "), evidence), nil
+}
+
+// mergeChecksEvidence is the project's merge check settings
+type mergeChecksEvidence struct {
+	PipelineMustSucceed    bool `json:"only_allow_merge_if_pipeline_succeeds"`
+	SkippedPipelineAllowed bool `json:"allow_merge_on_skipped_pipeline"`
+	ThreadsMustBeResolved  bool `json:"only_allow_merge_if_all_discussions_are_resolved"`
+}
+
+func newMergeChecksEvidence(p *gitlab.Project) mergeChecksEvidence {
+	return mergeChecksEvidence{
+		PipelineMustSucceed:    p.OnlyAllowMergeIfPipelineSucceeds,
+		SkippedPipelineAllowed: p.AllowMergeOnSkippedPipeline,
+		ThreadsMustBeResolved:  p.OnlyAllowMergeIfAllDiscussionsAreResolved,
+	}
+}
+
+// visibilityEvidence is the project's visibility level
+type visibilityEvidence struct {
+	Visibility string `json:"visibility"`
+}
+
+// credentialsEvidence lists the project's deploy keys and active access
+// tokens, and those of them that never expire
+type credentialsEvidence struct {
+	DeployKeys   []gitlab.DeployKey   `json:"deploy_keys"`
+	AccessTokens []gitlab.AccessToken `json:"access_tokens"`
+	NeverExpire  []string             `json:"never_expire"`
+}
+
+// evaluateCredentialsExpire fails for deploy keys and active project access
+// tokens without an expiry date
+func evaluateCredentialsExpire(ctx context.Context, t *Target) (Outcome, error) {
+	keys, err := t.DeployKeys(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	tokens, err := t.AccessTokens(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	evidence := credentialsEvidence{DeployKeys: []gitlab.DeployKey{}, AccessTokens: []gitlab.AccessToken{}, NeverExpire: []string{}}
+	for _, key := range keys {
+		evidence.DeployKeys = append(evidence.DeployKeys, key)
+		if key.ExpiresAt == "" {
+			evidence.NeverExpire = append(evidence.NeverExpire, fmt.Sprintf("deploy key %q", key.Title))
+		}
+	}
+	for _, token := range tokens {
+		if !token.Active || token.Revoked {
+			continue
+		}
+		evidence.AccessTokens = append(evidence.AccessTokens, token)
+		if token.ExpiresAt == "" {
+			evidence.NeverExpire = append(evidence.NeverExpire, fmt.Sprintf("access token %q", token.Name))
+		}
+	}
+
+	switch {
+	case len(evidence.NeverExpire) > 0:
+		return Fail("No expiry is set on "+strings.Join(evidence.NeverExpire, ", "), evidence), nil
+	case len(evidence.DeployKeys) == 0 && len(evidence.AccessTokens) == 0:
+		return Pass("Project has no deploy keys or active access tokens", evidence), nil
+	default:
+		return Pass("Every deploy key and active access token has an expiry date", evidence), nil
+	}
+}
+
+// approvalSetting evaluates the project's approval settings with decide
+func approvalSetting(decide func(*gitlab.ApprovalConfig) Outcome) func(context.Context, *Target) (Outcome, error) {
+	return func(ctx context.Context, t *Target) (Outcome, error) {
+		cfg, err := t.ApprovalConfig(ctx)
+		if err != nil {
+			return Outcome{}, err
+		}
+		return decide(cfg), nil
+	}
+}
+
+// approvalsEvidence is what the minimum approvals check read
+type approvalsEvidence struct {
+	ApprovalsBeforeMerge int                   `json:"approvals_before_merge"`
+	ApproverRules        []string              `json:"approver_rules"` // Rules requiring at least one approval
+	Rules                []gitlab.ApprovalRule `json:"approval_rules"`
+}
+
+// evaluateMinApprovals measures the approvals merge requests need: the most
+// that the project-level setting or any single approval rule requires
+func evaluateMinApprovals(ctx context.Context, t *Target) (Outcome, error) {
+	cfg, err := t.ApprovalConfig(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	rules, err := t.ApprovalRules(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	evidence := approvalsEvidence{ApprovalsBeforeMerge: cfg.ApprovalsBeforeMerge, ApproverRules: []string{}, Rules: rules}
+	required, source := cfg.ApprovalsBeforeMerge, "the project setting"
+	for _, rule := range rules {
+		if rule.ApprovalsRequired <= 0 {
+			continue
+		}
+		evidence.ApproverRules = append(evidence.ApproverRules, rule.Name)
+		if rule.ApprovalsRequired > required {
+			required, source = rule.ApprovalsRequired, fmt.Sprintf("approval rule %q", rule.Name)
+		}
+	}
+
+	reason := "No approval setting or rule requires an approval"
+	if required > 0 {
+		reason = fmt.Sprintf("Merge requests need %s, set by %s", plural(required, "approval"), source)
+	}
+	return Measure(required, t.Threshold(MinApprovalsRequired), reason, evidence), nil
+}
@@ -0,0 +1,260 @@
+// Package checks is the registry of readiness checks. A check describes
+// itself and evaluates a project against GitLab; registering it is all it
+// takes for the check to be scanned, stored, filtered on, exported and
+// listed by GET /api/v1/checks.
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Check categories used to group readiness checks
+const (
+	CategoryPresence         = "gitlab_presence"
+	CategoryBranchProtection = "branch_protection"
+	CategoryMergeRequest     = "merge_request"
+	CategoryCodeOwners       = "code_owners"
+	CategoryMemberAccess     = "member_access"
+	CategorySecurityScanning = "security_scanning"
+)
+
+// Severity ranks how much a failing check matters
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Status is the outcome of evaluating a check
+type Status string
+
+const (
+	StatusPass          Status = "pass"
+	StatusFail          Status = "fail"
+	StatusError         Status = "error"   // GitLab refused or failed a request the check needed
+	StatusUnknown       Status = "unknown" // Never evaluated
+	StatusNotApplicable Status = "not_applicable"
+)
+
+// Passing reports whether the status counts as passed for readiness
+func (s Status) Passing() bool {
+	return s == StatusPass || s == StatusNotApplicable
+}
+
+// Outcome is what evaluating a check found
+type Outcome struct {
+	Status   Status
+	Reason   string          // Why, in a sentence
+	Evidence json.RawMessage // The GitLab data the outcome was decided on, if any
+	Value    *int            // What a measured check counted, in its definition's unit
+	Captured string          // The value a capturing check read, if it found one
+}
+
+// Pass returns a passing outcome. Evidence is marshalled to JSON; nil
+// records none.
+func Pass(reason string, evidence any) Outcome {
+	return Outcome{Status: StatusPass, Reason: reason, Evidence: marshalEvidence(evidence)}
+}
+
+// Fail returns a failing outcome. Evidence is marshalled to JSON; nil
+// records none.
+func Fail(reason string, evidence any) Outcome {
+	return Outcome{Status: StatusFail, Reason: reason, Evidence: marshalEvidence(evidence)}
+}
+
+// NotApplicable returns the outcome of a check that does not apply to the
+// project, which counts as passed
+func NotApplicable(reason string) Outcome {
+	return Outcome{Status: StatusNotApplicable, Reason: reason}
+}
+
+// Measure returns the outcome of a measured check: whether value meets the
+// threshold, or a pass when there is none. The threshold is appended to the
+// reason, which should describe the value.
+func Measure(value int, threshold *Threshold, reason string, evidence any) Outcome {
+	o := Pass(reason, evidence)
+	if threshold != nil {
+		o.Reason += ", " + threshold.String() + " required"
+		if !threshold.Met(value) {
+			o.Status = StatusFail
+		}
+	}
+	o.Value = &value
+	return o
+}
+
+// Check returns Pass or Fail with the matching reason
+func Check(passed bool, pass, fail string, evidence any) Outcome {
+	if passed {
+		return Pass(pass, evidence)
+	}
+	return Fail(fail, evidence)
+}
+
+func marshalEvidence(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// Definition describes a readiness check. ID names the check everywhere:
+// in project JSON, filters, exports, exemptions and events.
+type Definition struct {
+	ID          string   `json:"id" example:"codeowners_exists"`
+	Category    string   `json:"category" example:"gitlab_presence"`
+	Description string   `json:"description" example:"CODEOWNERS file exists"`
+	Remediation string   `json:"remediation"` // How to make the check pass
+	Severity    Severity `json:"severity" enums:"low,medium,high,critical" example:"high"`
+
+	// Files are the repository files the check reads from the default
+	// branch. Pushes changing one of them rescan the check.
+	Files []string `json:"files,omitempty"`
+
+	// Pipeline is set for checks reading the latest finished pipeline on
+	// the default branch. Pipelines finishing there rescan them.
+	Pipeline bool `json:"pipeline,omitempty"`
+
+	// Unit is what a measured check counts. Only measured checks take
+	// thresholds, starting from Threshold unless a profile sets one.
+	Unit      string     `json:"unit,omitempty" example:"approvals"`
+	Threshold *Threshold `json:"threshold,omitempty" swaggertype:"string" example:">= 1"`
+
+	// Captures names the value a capturing check reads, such as a CI
+	// variable. Profiles may give the format it must have.
+	Captures string `json:"captures,omitempty" example:"MOAB_ID"`
+}
+
+// Checker is a readiness check
+type Checker interface {
+	Definition() Definition
+
+	// Evaluate decides the project's outcome. It is only called for
+	// projects GitLab knows about. Errors GitLab responded with are recorded
+	// as StatusError; any other error ends the scan.
+	Evaluate(ctx context.Context, t *Target) (Outcome, error)
+}
+
+// Func returns a Checker that evaluates projects with fn
+func Func(def Definition, fn func(ctx context.Context, t *Target) (Outcome, error)) Checker {
+	return funcChecker{def: def, fn: fn}
+}
+
+type funcChecker struct {
+	def Definition
+	fn  func(ctx context.Context, t *Target) (Outcome, error)
+}
+
+func (c funcChecker) Definition() Definition { return c.def }
+
+func (c funcChecker) Evaluate(ctx context.Context, t *Target) (Outcome, error) {
+	return c.fn(ctx, t)
+}
+
+var (
+	mu       sync.RWMutex
+	registry []Checker
+)
+
+// Register adds a check after those already registered. Checks are
+// registered at init, so an empty or duplicate ID panics.
+func Register(c Checker) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	id := c.Definition().ID
+	if id == "" {
+		panic("checks: Register called with an empty ID")
+	}
+	if slices.ContainsFunc(registry, func(r Checker) bool { return r.Definition().ID == id }) {
+		panic(fmt.Sprintf("checks: Register called twice for %s", id))
+	}
+	registry = append(registry, c)
+}
+
+// All returns the registered checks in display order
+func All() []Checker {
+	mu.RLock()
+	defer mu.RUnlock()
+	return slices.Clone(registry)
+}
+
+// Definitions returns the definitions of the registered checks in display
+// order
+func Definitions() []Definition {
+	all := All()
+	defs := make([]Definition, len(all))
+	for i, c := range all {
+		defs[i] = c.Definition()
+	}
+	return defs
+}
+
+// IDs returns the IDs of the registered checks in display order
+func IDs() []string {
+	all := All()
+	ids := make([]string, len(all))
+	for i, c := range all {
+		ids[i] = c.Definition().ID
+	}
+	return ids
+}
+
+// Lookup returns the registered check with the ID
+func Lookup(id string) (Checker, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, c := range registry {
+		if c.Definition().ID == id {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// ReadingFile returns the IDs of the checks that read the repository file,
+// in display order
+func ReadingFile(path string) []string {
+	var ids []string
+	for _, def := range Definitions() {
+		if slices.Contains(def.Files, path) {
+			ids = append(ids, def.ID)
+		}
+	}
+	return ids
+}
+
+// PipelineChecks returns the IDs of every check reading the latest
+// pipeline, in display order
+func PipelineChecks() []string {
+	var ids []string
+	for _, def := range Definitions() {
+		if def.Pipeline {
+			ids = append(ids, def.ID)
+		}
+	}
+	return ids
+}
+
+// FileChecks returns the IDs of every check reading repository files, in
+// display order
+func FileChecks() []string {
+	var ids []string
+	for _, def := range Definitions() {
+		if len(def.Files) > 0 {
+			ids = append(ids, def.ID)
+		}
+	}
+	return ids
+}
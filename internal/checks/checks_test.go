@@ -0,0 +1,112 @@
+package checks
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestRegister_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Register() did not panic on a duplicate ID")
+		}
+	}()
+	Register(Func(Definition{ID: ProjectPresent}, func(context.Context, *Target) (Outcome, error) {
+		return Pass("", nil), nil
+	}))
+}
+
+func TestReadingFile(t *testing.T) {
+	if got := ReadingFile(CIConfigPath); !slices.Equal(got, []string{AppNameSet, MoabIDSet, SASTEnabled, SecretDetectionEnabled, DependencyScanningEnabled, ContainerScanningEnabled}) {
+		t.Errorf("ReadingFile(%q) = %v", CIConfigPath, got)
+	}
+	if got := ReadingFile("docs/CODEOWNERS"); !slices.Equal(got, []string{CodeownersExists, CodeownersValid, CodeownersCatchAll}) {
+		t.Errorf("ReadingFile(docs/CODEOWNERS) = %v", got)
+	}
+	if got := ReadingFile("README.md"); got != nil {
+		t.Errorf("ReadingFile(README.md) = %v, want none", got)
+	}
+}
+
+func TestParseThreshold(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		meets   int
+		misses  int
+		wantErr bool
+	}{
+		{in: ">= 2", want: ">= 2", meets: 2, misses: 1},
+		{in: ">2", want: "> 2", meets: 3, misses: 2},
+		{in: "<= 0", want: "<= 0", meets: 0, misses: 1},
+		{in: "== 3", want: "== 3", meets: 3, misses: 4},
+		{in: "1", want: ">= 1", meets: 1, misses: 0},
+		{in: ">= two", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseThreshold(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseThreshold(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseThreshold(%q) = %v, %v, want %s", tt.in, got, err, tt.want)
+			continue
+		}
+		if !got.Met(tt.meets) || got.Met(tt.misses) {
+			t.Errorf("%s: Met(%d) = %v, Met(%d) = %v", got, tt.meets, got.Met(tt.meets), tt.misses, got.Met(tt.misses))
+		}
+	}
+}
+
+func TestProfile_Validate(t *testing.T) {
+	valid := &Profile{Name: "tier-1", Thresholds: map[string]Threshold{MinApprovalsRequired: {Op: ">=", Value: 2}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	unmeasured := &Profile{Name: "tier-1", Thresholds: map[string]Threshold{ForcePushDisabled: {Op: ">=", Value: 1}}}
+	if err := unmeasured.Validate(); err == nil {
+		t.Error("Validate() accepted a threshold on a check that measures nothing")
+	}
+
+	uncaptured := &Profile{Name: "tier-1", Formats: map[string]Pattern{ForcePushDisabled: {Regexp: regexp.MustCompile(".")}}}
+	if err := uncaptured.Validate(); err == nil {
+		t.Error("Validate() accepted a format on a check that captures nothing")
+	}
+	repeated := &Profile{Name: "tier-1", ProtectedBranches: []string{"release/*", "release/*"}}
+	if err := repeated.Validate(); err == nil {
+		t.Error("Validate() accepted a protected branch listed twice")
+	}
+	emptyTag := &Profile{Name: "tier-1", ProtectedTags: []string{""}}
+	if err := emptyTag.Validate(); err == nil {
+		t.Error("Validate() accepted an empty protected tag")
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		rule, name string
+		want       bool
+	}{
+		{"main", "main", true},
+		{"main", "master", false},
+		{"release/*", "release/*", true},
+		{"release/*", "release/v1/rc", true},
+		{"release/v1", "release/*", false},
+		{"*", "hotfix/*", true},
+		{"*-stable", "15-0-stable", true},
+		{"*-stable", "stable", false},
+		{"a*b*b", "ab", false},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.rule, tt.name); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.rule, tt.name, got, tt.want)
+		}
+	}
+}
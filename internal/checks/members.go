@@ -0,0 +1,189 @@
+package checks
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/go-backend/internal/gitlab"
+)
+
+// ReviewedMember is a user with access to a project, as the access review
+// checks see them
+type ReviewedMember struct {
+	ID          int    `json:"id" example:"7"`
+	Username    string `json:"username" example:"jdoe"`
+	Name        string `json:"name" example:"Jane Doe"`
+	State       string `json:"state" example:"active"`
+	AccessLevel int    `json:"access_level" example:"40"`
+	Role        string `json:"role" example:"Maintainer"`
+	Inherited   bool   `json:"inherited"` // The role comes from a group, not a direct membership
+	Bot         bool   `json:"bot,omitempty"`
+	ExpiresAt   string `json:"expires_at,omitempty" example:"2027-01-31"`
+
+	// External is looked up for active members with write access, and
+	// only administrator tokens can see it. It is null where unknown.
+	External *bool `json:"external"`
+}
+
+// MemberReview is who has access to a project, directly or through its
+// groups, and the members the access review checks flag. Lists name
+// members by username.
+type MemberReview struct {
+	Members         []ReviewedMember `json:"members"`
+	Owners          []string         `json:"owners"`           // Active users with the Owner role
+	Maintainers     []string         `json:"maintainers"`      // Active users with the Maintainer role
+	ExternalWriters []string         `json:"external_writers"` // External users with Developer or above
+	Unverified      []string         `json:"unverified"`       // Writers whose external status is hidden
+	Blocked         []string         `json:"blocked"`          // Members whose account is not active
+}
+
+// MemberChecks returns the IDs of the checks reading the project's members,
+// in display order
+func MemberChecks() []string {
+	return []string{CodeownersValid, OwnersLimited, MaintainersLimited, ExternalWritersAbsent, BlockedMembersAbsent}
+}
+
+// MemberReview returns the project's members, highest role first, with
+// those the access review checks flag. Bots are listed but not counted as
+// owners, maintainers or external users.
+func (t *Target) MemberReview(ctx context.Context) (*MemberReview, error) {
+	return t.memberReview.get(func() (*MemberReview, error) {
+		members, err := t.Members(ctx)
+		if err != nil && !errors.Is(err, gitlab.ErrNotFound) {
+			return nil, err
+		}
+		direct, err := t.Client.ListProjectMembers(ctx, t.ProjectID)
+		if err != nil && !errors.Is(err, gitlab.ErrNotFound) {
+			return nil, err
+		}
+		directLevels := make(map[int]int, len(direct))
+		for _, m := range direct {
+			directLevels[m.ID] = m.AccessLevel
+		}
+
+		review := &MemberReview{
+			Members:         []ReviewedMember{},
+			Owners:          []string{},
+			Maintainers:     []string{},
+			ExternalWriters: []string{},
+			Unverified:      []string{},
+			Blocked:         []string{},
+		}
+		for _, m := range members {
+			level, ok := directLevels[m.ID]
+			rm := ReviewedMember{
+				ID:          m.ID,
+				Username:    m.Username,
+				Name:        m.Name,
+				State:       m.State,
+				AccessLevel: m.AccessLevel,
+				Role:        gitlab.RoleName(m.AccessLevel),
+				Inherited:   !ok || level < m.AccessLevel,
+				ExpiresAt:   m.ExpiresAt,
+			}
+			if m.State == "active" && m.AccessLevel >= gitlab.AccessDeveloper {
+				user, err := t.Client.GetUser(ctx, m.ID)
+				switch {
+				case errors.Is(err, gitlab.ErrNotFound):
+				case err != nil:
+					return nil, err
+				default:
+					rm.Bot, rm.External = user.Bot, user.External
+				}
+			}
+			review.Members = append(review.Members, rm)
+
+			switch {
+			case m.State != "active":
+				review.Blocked = append(review.Blocked, fmt.Sprintf("%s (%s)", m.Username, m.State))
+				continue
+			case rm.Bot:
+				continue
+			case m.AccessLevel >= gitlab.AccessOwner:
+				review.Owners = append(review.Owners, m.Username)
+			case m.AccessLevel >= gitlab.AccessMaintainer:
+				review.Maintainers = append(review.Maintainers, m.Username)
+			}
+			if m.AccessLevel >= gitlab.AccessDeveloper {
+				switch {
+				case rm.External == nil:
+					review.Unverified = append(review.Unverified, m.Username)
+				case *rm.External:
+					review.ExternalWriters = append(review.ExternalWriters, m.Username)
+				}
+			}
+		}
+		slices.SortStableFunc(review.Members, func(a, b ReviewedMember) int {
+			return cmp.Or(cmp.Compare(b.AccessLevel, a.AccessLevel), cmp.Compare(a.Username, b.Username))
+		})
+		return review, nil
+	})
+}
+
+// roleCountEvidence lists the members a role count counted
+type roleCountEvidence struct {
+	Role    string   `json:"role"`
+	Members []string `json:"members"`
+}
+
+// roleCount measures the active human members holding a role, listed by
+// pick from the target's member review
+func roleCount(id, role string, pick func(*MemberReview) []string) func(context.Context, *Target) (Outcome, error) {
+	return func(ctx context.Context, t *Target) (Outcome, error) {
+		review, err := t.MemberReview(ctx)
+		if err != nil {
+			return Outcome{}, err
+		}
+		members := pick(review)
+		reason := fmt.Sprintf("Project has %s", plural(len(members), strings.ToLower(role)))
+		return Measure(len(members), t.Threshold(id), reason, roleCountEvidence{Role: role, Members: members}), nil
+	}
+}
+
+// externalWritersEvidence lists the external users with write access and
+// the writers that could not be checked
+type externalWritersEvidence struct {
+	ExternalWriters []string `json:"external_writers"`
+	Unverified      []string `json:"unverified"`
+}
+
+// evaluateExternalWriters fails for external users with Developer access or
+// above. Members whose external status the token cannot see are listed as
+// unverified without failing the check.
+func evaluateExternalWriters(ctx context.Context, t *Target) (Outcome, error) {
+	review, err := t.MemberReview(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	evidence := externalWritersEvidence{ExternalWriters: review.ExternalWriters, Unverified: review.Unverified}
+	switch {
+	case len(review.ExternalWriters) > 0:
+		return Fail("External users have write access: "+strings.Join(review.ExternalWriters, ", "), evidence), nil
+	case len(review.Unverified) > 0:
+		return Pass(fmt.Sprintf("No external user is known to have write access; %s could not be checked", plural(len(review.Unverified), "member")), evidence), nil
+	default:
+		return Pass("No external user has write access", evidence), nil
+	}
+}
+
+// blockedMembersEvidence lists the members whose account is not active
+type blockedMembersEvidence struct {
+	Blocked []string `json:"blocked"`
+}
+
+// evaluateBlockedMembers fails for members whose account is blocked,
+// banned or deactivated
+func evaluateBlockedMembers(ctx context.Context, t *Target) (Outcome, error) {
+	review, err := t.MemberReview(ctx)
+	if err != nil {
+		return Outcome{}, err
+	}
+	return Check(len(review.Blocked) == 0,
+		"Every member's account is active",
+		"Members with inactive accounts keep their access: "+strings.Join(review.Blocked, ", "),
+		blockedMembersEvidence{Blocked: review.Blocked}), nil
+}
@@ -0,0 +1,190 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultProfile is the readiness profile of projects that do not name one
+const DefaultProfile = "default"
+
+// DefaultReleaseTags are the release tags that must be protected in
+// profiles that do not list their own
+var DefaultReleaseTags = []string{"v*"}
+
+// ErrUnknownProfile is returned for profile names missing from a registry
+var ErrUnknownProfile = errors.New("checks: unknown profile")
+
+// Profile is a named set of thresholds for measured checks, such as the
+// number of approvals tier-1 projects need, formats for the values
+// capturing checks read, the branches that must be protected besides the
+// default one, and the tags releases are cut as. Checks it leaves out keep
+// the threshold of their definition and accept any value.
+type Profile struct {
+	Name       string               `json:"name" example:"tier-1"`
+	Thresholds map[string]Threshold `json:"thresholds" swaggertype:"object,string" example:"min_approvals_required:>= 2"`
+	Formats    map[string]Pattern   `json:"formats,omitempty" swaggertype:"object,string" example:"moab_id_set:^MOAB-[0-9]{6}$"`
+
+	// ProtectedBranches are branch names or wildcard patterns, as GitLab
+	// writes them, that the branch protection checks hold to the same rules
+	// as the default branch
+	ProtectedBranches []string `json:"protected_branches,omitempty" example:"release/*,hotfix/*"`
+
+	// ProtectedTags are the tag names or wildcard patterns releases are
+	// tagged with, which must be protected. Profiles listing none use
+	// DefaultReleaseTags.
+	ProtectedTags []string `json:"protected_tags,omitempty" example:"v*"`
+}
+
+// Threshold returns the bound the profile puts on the check with the ID, or
+// nil if the check is not measured or has no bound. A nil profile applies
+// the definitions' thresholds.
+func (p *Profile) Threshold(id string) *Threshold {
+	if p != nil {
+		if t, ok := p.Thresholds[id]; ok {
+			return &t
+		}
+	}
+	c, ok := Lookup(id)
+	if !ok {
+		return nil
+	}
+	return c.Definition().Threshold
+}
+
+// Format returns the pattern the profile requires of the value the check
+// with the ID captures, or nil if any value will do
+func (p *Profile) Format(id string) *regexp.Regexp {
+	if p == nil {
+		return nil
+	}
+	return p.Formats[id].Regexp
+}
+
+// RequiredBranches returns the branch names and patterns the profile
+// requires protected besides the default branch. A nil profile requires
+// none.
+func (p *Profile) RequiredBranches() []string {
+	if p == nil {
+		return nil
+	}
+	return p.ProtectedBranches
+}
+
+// ReleaseTags returns the tag names and patterns the profile requires
+// protected
+func (p *Profile) ReleaseTags() []string {
+	if p == nil || len(p.ProtectedTags) == 0 {
+		return DefaultReleaseTags
+	}
+	return p.ProtectedTags
+}
+
+// Validate reports thresholds and formats on checks that are not registered
+// or do not measure or capture a value, and empty or repeated protected
+// branches and tags
+func (p *Profile) Validate() error {
+	for _, id := range slices.Sorted(maps.Keys(p.Thresholds)) {
+		c, ok := Lookup(id)
+		if !ok {
+			return fmt.Errorf("profile %q: unknown check %q", p.Name, id)
+		}
+		if c.Definition().Unit == "" {
+			return fmt.Errorf("profile %q: check %q does not measure a value", p.Name, id)
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(p.Formats)) {
+		c, ok := Lookup(id)
+		if !ok {
+			return fmt.Errorf("profile %q: unknown check %q", p.Name, id)
+		}
+		if c.Definition().Captures == "" {
+			return fmt.Errorf("profile %q: check %q does not capture a value", p.Name, id)
+		}
+	}
+	for i, branch := range p.ProtectedBranches {
+		if strings.TrimSpace(branch) == "" {
+			return fmt.Errorf("profile %q: empty protected branch", p.Name)
+		}
+		if slices.Contains(p.ProtectedBranches[:i], branch) {
+			return fmt.Errorf("profile %q: protected branch %q listed twice", p.Name, branch)
+		}
+	}
+	for i, tag := range p.ProtectedTags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("profile %q: empty protected tag", p.Name)
+		}
+		if slices.Contains(p.ProtectedTags[:i], tag) {
+			return fmt.Errorf("profile %q: protected tag %q listed twice", p.Name, tag)
+		}
+	}
+	return nil
+}
+
+// Profiles is a registry of readiness profiles by name
+type Profiles map[string]*Profile
+
+// NewProfiles returns a registry of the profiles
+func NewProfiles(profiles []Profile) Profiles {
+	registry := make(Profiles, len(profiles))
+	for _, profile := range profiles {
+		registry[profile.Name] = &profile
+	}
+	return registry
+}
+
+// Get returns the named profile. The default profile is always known,
+// applying the definitions' thresholds unless one was configured.
+func (p Profiles) Get(name string) (*Profile, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if profile, ok := p[name]; ok {
+		return profile, nil
+	}
+	if name == DefaultProfile {
+		return &Profile{Name: DefaultProfile}, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownProfile, name)
+}
+
+// Resolved returns a copy of the profile listing the threshold it puts on
+// every measured check and the release tags it requires protected,
+// including those it leaves to their defaults
+func (p *Profile) Resolved() *Profile {
+	resolved := &Profile{
+		Name:              p.Name,
+		Thresholds:        make(map[string]Threshold),
+		Formats:           maps.Clone(p.Formats),
+		ProtectedBranches: slices.Clone(p.ProtectedBranches),
+		ProtectedTags:     slices.Clone(p.ReleaseTags()),
+	}
+	for _, def := range Definitions() {
+		if def.Unit == "" {
+			continue
+		}
+		if t := p.Threshold(def.ID); t != nil {
+			resolved.Thresholds[def.ID] = *t
+		}
+	}
+	return resolved
+}
+
+// List returns every profile resolved, the default one first and the rest
+// by name
+func (p Profiles) List() []*Profile {
+	names := slices.Sorted(maps.Keys(p))
+	names = slices.DeleteFunc(names, func(name string) bool { return name == DefaultProfile })
+	names = append([]string{DefaultProfile}, names...)
+
+	list := make([]*Profile, len(names))
+	for i, name := range names {
+		profile, _ := p.Get(name)
+		list[i] = profile.Resolved()
+	}
+	return list
+}
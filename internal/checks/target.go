@@ -0,0 +1,384 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/user/go-backend/internal/ciconfig"
+	"github.com/user/go-backend/internal/codeowners"
+	"github.com/user/go-backend/internal/gitlab"
+)
+
+// Target is a project being scanned. Its GitLab responses are cached for the
+// length of the scan so checks reading the same data share one request.
+// A Target is not safe for concurrent use.
+type Target struct {
+	Client    *gitlab.Client
+	ProjectID string          // As stored, a numeric ID or full path
+	Project   *gitlab.Project // As GitLab reports it
+	Profile   *Profile        // Thresholds the project is held to
+
+	files             map[string]*cached[[]byte]
+	protectedBranch   cached[*gitlab.ProtectedBranch]
+	protectedBranches cached[[]gitlab.ProtectedBranch]
+	protectedTags     cached[[]gitlab.ProtectedTag]
+	pushRule          cached[*gitlab.PushRule]
+	approvalConfig    cached[*gitlab.ApprovalConfig]
+	approvalRules     cached[[]gitlab.ApprovalRule]
+	ciConfig          cached[*ciconfig.Config]
+	codeowners        cached[*Codeowners]
+	members           cached[[]gitlab.Member]
+	memberReview      cached[*MemberReview]
+	pipeline          cached[*gitlab.Pipeline]
+	pipelineJobs      cached[[]gitlab.Job]
+	deployKeys        cached[[]gitlab.DeployKey]
+	accessTokens      cached[[]gitlab.AccessToken]
+}
+
+// NewTarget returns the target for a project GitLab found, held to the
+// profile's thresholds
+func NewTarget(client *gitlab.Client, projectID string, project *gitlab.Project, profile *Profile) *Target {
+	return &Target{
+		Client:    client,
+		ProjectID: projectID,
+		Project:   project,
+		Profile:   profile,
+		files:     make(map[string]*cached[[]byte]),
+	}
+}
+
+// Threshold returns the bound the target's profile puts on the check with
+// the ID, or nil if there is none
+func (t *Target) Threshold(id string) *Threshold {
+	return t.Profile.Threshold(id)
+}
+
+// Format returns the pattern the target's profile requires of the value the
+// check with the ID captures, or nil if any value will do
+func (t *Target) Format(id string) *regexp.Regexp {
+	return t.Profile.Format(id)
+}
+
+// cached holds the outcome of one GitLab request
+type cached[T any] struct {
+	done  bool
+	value T
+	err   error
+}
+
+func (c *cached[T]) get(fetch func() (T, error)) (T, error) {
+	if !c.done {
+		c.value, c.err = fetch()
+		c.done = true
+	}
+	return c.value, c.err
+}
+
+// File returns a repository file from the default branch. Missing files
+// return an error matching gitlab.ErrNotFound.
+func (t *Target) File(ctx context.Context, path string) ([]byte, error) {
+	c, ok := t.files[path]
+	if !ok {
+		c = &cached[[]byte]{}
+		t.files[path] = c
+	}
+	return c.get(func() ([]byte, error) {
+		return t.Client.GetRawFile(ctx, t.ProjectID, path, t.Project.DefaultBranch)
+	})
+}
+
+// DefaultBranchProtection returns the protection of the default branch, or
+// nil if it is unprotected or the repository is empty
+func (t *Target) DefaultBranchProtection(ctx context.Context) (*gitlab.ProtectedBranch, error) {
+	return t.protectedBranch.get(func() (*gitlab.ProtectedBranch, error) {
+		if t.Project.DefaultBranch == "" {
+			return nil, nil // Empty repository
+		}
+		pb, err := t.Client.GetProtectedBranch(ctx, t.ProjectID, t.Project.DefaultBranch)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return pb, err
+	})
+}
+
+// ProtectedBranches returns the project's protected branch rules
+func (t *Target) ProtectedBranches(ctx context.Context) ([]gitlab.ProtectedBranch, error) {
+	return t.protectedBranches.get(func() ([]gitlab.ProtectedBranch, error) {
+		return t.Client.ListProtectedBranches(ctx, t.ProjectID)
+	})
+}
+
+// PatternProtection returns the protection GitLab gives every branch the
+// pattern matches, combined from the rules covering the whole pattern, and
+// the names of those rules. It returns nil if no rule covers the pattern.
+//
+// As in GitLab, the most permissive rule decides who may push and merge,
+// while force push and code owner approval follow the strictest rule.
+func (t *Target) PatternProtection(ctx context.Context, pattern string) (*gitlab.ProtectedBranch, []string, error) {
+	rules, err := t.ProtectedBranches(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var combined *gitlab.ProtectedBranch
+	var names []string
+	for _, rule := range rules {
+		if !wildcardMatch(rule.Name, pattern) {
+			continue
+		}
+		names = append(names, rule.Name)
+		if combined == nil {
+			combined = &gitlab.ProtectedBranch{Name: pattern, AllowForcePush: true}
+		}
+		combined.PushAccessLevels = append(combined.PushAccessLevels, rule.PushAccessLevels...)
+		combined.MergeAccessLevels = append(combined.MergeAccessLevels, rule.MergeAccessLevels...)
+		combined.AllowForcePush = combined.AllowForcePush && rule.AllowForcePush
+		combined.CodeOwnerApprovalRequired = combined.CodeOwnerApprovalRequired || rule.CodeOwnerApprovalRequired
+	}
+	return combined, names, nil
+}
+
+// wildcardMatch reports whether a protected branch name, in which * matches
+// any run of characters including slashes, matches name. A pattern given
+// as name is matched literally, so release/* matches release/* and * but
+// not release/v1.
+func wildcardMatch(rule, name string) bool {
+	parts := strings.Split(rule, "*")
+	if len(parts) == 1 {
+		return rule == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return strings.HasSuffix(name, parts[len(parts)-1])
+}
+
+// ProtectedTags returns the project's protected tag rules, or none where
+// GitLab answers 404
+func (t *Target) ProtectedTags(ctx context.Context) ([]gitlab.ProtectedTag, error) {
+	return t.protectedTags.get(func() ([]gitlab.ProtectedTag, error) {
+		rules, err := t.Client.ListProtectedTags(ctx, t.ProjectID)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return rules, err
+	})
+}
+
+// TagProtection returns the rules covering every tag the pattern matches,
+// or none if it is unprotected. Like branch patterns, tag patterns are
+// matched as GitLab writes them.
+func (t *Target) TagProtection(ctx context.Context, pattern string) ([]gitlab.ProtectedTag, error) {
+	rules, err := t.ProtectedTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var covering []gitlab.ProtectedTag
+	for _, rule := range rules {
+		if wildcardMatch(rule.Name, pattern) {
+			covering = append(covering, rule)
+		}
+	}
+	return covering, nil
+}
+
+// PushRule returns the project's push rule, or nil if it has none
+func (t *Target) PushRule(ctx context.Context) (*gitlab.PushRule, error) {
+	return t.pushRule.get(func() (*gitlab.PushRule, error) {
+		return t.Client.GetPushRule(ctx, t.ProjectID)
+	})
+}
+
+// ApprovalConfig returns the project's merge request approval settings
+func (t *Target) ApprovalConfig(ctx context.Context) (*gitlab.ApprovalConfig, error) {
+	return t.approvalConfig.get(func() (*gitlab.ApprovalConfig, error) {
+		return t.Client.GetApprovalConfig(ctx, t.ProjectID)
+	})
+}
+
+// ApprovalRules returns the project's approval rules, or none where GitLab
+// does not offer them
+func (t *Target) ApprovalRules(ctx context.Context) ([]gitlab.ApprovalRule, error) {
+	return t.approvalRules.get(func() ([]gitlab.ApprovalRule, error) {
+		rules, err := t.Client.ListApprovalRules(ctx, t.ProjectID)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return rules, err
+	})
+}
+
+// CIConfig returns the project's CI/CD configuration on the default branch,
+// with its includes followed and the CI/CD variables of the project and its
+// groups applied. Settings the token may not read are listed as unresolved.
+// A missing configuration returns an error matching gitlab.ErrNotFound.
+func (t *Target) CIConfig(ctx context.Context) (*ciconfig.Config, error) {
+	return t.ciConfig.get(func() (*ciconfig.Config, error) {
+		cfg, err := ciconfig.Load(ctx, targetFiles{t}, t.ProjectID, t.Project.DefaultBranch, ciconfig.ParsePath(t.Project.CIConfigPath))
+		if err != nil {
+			return nil, err
+		}
+
+		// Settings take precedence over files, and closer groups over
+		// their parents
+		if ns := t.Project.Namespace; ns.Kind == "group" {
+			parts := strings.Split(ns.FullPath, "/")
+			for i := range parts {
+				group := strings.Join(parts[:i+1], "/")
+				src := ciconfig.Source{Kind: ciconfig.SourceGroupSettings, Group: group}
+				vars, err := t.Client.ListGroupVariables(ctx, group)
+				if err := applySettings(cfg, src, vars, err); err != nil {
+					return nil, err
+				}
+			}
+		}
+		vars, err := t.Client.ListProjectVariables(ctx, t.ProjectID)
+		if err := applySettings(cfg, ciconfig.Source{Kind: ciconfig.SourceProjectSettings}, vars, err); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	})
+}
+
+// applySettings applies settings variables read with err, recording those
+// GitLab hides from the token as unresolved
+func applySettings(cfg *ciconfig.Config, src ciconfig.Source, vars []gitlab.Variable, err error) error {
+	var apiErr *gitlab.APIError
+	switch {
+	case errors.Is(err, gitlab.ErrNotFound):
+		cfg.Unresolved = append(cfg.Unresolved, ciconfig.Unresolved{Source: src, Reason: "not found"})
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		cfg.Unresolved = append(cfg.Unresolved, ciconfig.Unresolved{Source: src, Reason: fmt.Sprintf("GitLab returned %d", apiErr.StatusCode)})
+	case err != nil:
+		return err
+	default:
+		cfg.ApplySettings(src, vars)
+	}
+	return nil
+}
+
+// targetFiles reads configuration files for ciconfig, sharing the target's
+// cache for files on its default branch
+type targetFiles struct {
+	t *Target
+}
+
+func (f targetFiles) GetRawFile(ctx context.Context, projectID, filePath, ref string) ([]byte, error) {
+	if projectID == f.t.ProjectID && ref == f.t.Project.DefaultBranch {
+		return f.t.File(ctx, filePath)
+	}
+	return f.t.Client.GetRawFile(ctx, projectID, filePath, ref)
+}
+
+func (f targetFiles) GetCITemplate(ctx context.Context, name string) ([]byte, error) {
+	return f.t.Client.GetCITemplate(ctx, name)
+}
+
+// Codeowners is the CODEOWNERS file GitLab uses for a project
+type Codeowners struct {
+	*codeowners.File
+	Path    string
+	Ignored []string // Paths of other CODEOWNERS files, which GitLab ignores
+}
+
+// Codeowners returns the project's CODEOWNERS file from the first of
+// CodeownersPaths holding one, or nil if there is none
+func (t *Target) Codeowners(ctx context.Context) (*Codeowners, error) {
+	return t.codeowners.get(func() (*Codeowners, error) {
+		var found *Codeowners
+		for _, path := range CodeownersPaths {
+			content, err := t.File(ctx, path)
+			if errors.Is(err, gitlab.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if found == nil {
+				found = &Codeowners{File: codeowners.Parse(content), Path: path}
+			} else {
+				found.Ignored = append(found.Ignored, path)
+			}
+		}
+		return found, nil
+	})
+}
+
+// ScannedCodeowners returns the CODEOWNERS file if a check read it during
+// the scan, which may be nil if there is none, and whether one did
+func (t *Target) ScannedCodeowners() (*Codeowners, bool) {
+	c := t.codeowners
+	return c.value, c.done && c.err == nil
+}
+
+// Members returns the project's members, including those with access
+// through its groups
+func (t *Target) Members(ctx context.Context) ([]gitlab.Member, error) {
+	return t.members.get(func() ([]gitlab.Member, error) {
+		return t.Client.ListAllProjectMembers(ctx, t.ProjectID)
+	})
+}
+
+// LatestPipeline returns the most recent finished pipeline on the default
+// branch, or nil if none has finished
+func (t *Target) LatestPipeline(ctx context.Context) (*gitlab.Pipeline, error) {
+	return t.pipeline.get(func() (*gitlab.Pipeline, error) {
+		if t.Project.DefaultBranch == "" {
+			return nil, nil // Empty repository
+		}
+		p, err := t.Client.GetLatestPipeline(ctx, t.ProjectID, t.Project.DefaultBranch)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return p, err
+	})
+}
+
+// PipelineJobs returns the jobs of the latest finished pipeline on the
+// default branch, or none if no pipeline has finished
+func (t *Target) PipelineJobs(ctx context.Context) ([]gitlab.Job, error) {
+	return t.pipelineJobs.get(func() ([]gitlab.Job, error) {
+		p, err := t.LatestPipeline(ctx)
+		if err != nil || p == nil {
+			return nil, err
+		}
+		return t.Client.ListPipelineJobs(ctx, t.ProjectID, p.ID)
+	})
+}
+
+// DeployKeys returns the deploy keys enabled for the project, or none where
+// GitLab answers 404
+func (t *Target) DeployKeys(ctx context.Context) ([]gitlab.DeployKey, error) {
+	return t.deployKeys.get(func() ([]gitlab.DeployKey, error) {
+		keys, err := t.Client.ListDeployKeys(ctx, t.ProjectID)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return keys, err
+	})
+}
+
+// AccessTokens returns the project's access tokens, or none where GitLab
+// does not offer them
+func (t *Target) AccessTokens(ctx context.Context) ([]gitlab.AccessToken, error) {
+	return t.accessTokens.get(func() ([]gitlab.AccessToken, error) {
+		tokens, err := t.Client.ListAccessTokens(ctx, t.ProjectID)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			return nil, nil
+		}
+		return tokens, err
+	})
+}
@@ -0,0 +1,90 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Threshold is a bound on the value a check measures, written as a
+// comparison such as ">= 2"
+type Threshold struct {
+	Op    string // One of >=, >, <=, < and ==
+	Value int
+}
+
+// thresholdOps lists the comparisons, longest first so ">=" is not read as ">"
+var thresholdOps = []string{">=", "<=", "==", ">", "<"}
+
+// ParseThreshold reads a threshold such as ">= 2". A bare number means at
+// least that many.
+func ParseThreshold(s string) (Threshold, error) {
+	rest := strings.TrimSpace(s)
+	op := ">="
+	for _, candidate := range thresholdOps {
+		if strings.HasPrefix(rest, candidate) {
+			op, rest = candidate, strings.TrimSpace(strings.TrimPrefix(rest, candidate))
+			break
+		}
+	}
+	value, err := strconv.Atoi(rest)
+	if err != nil {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: want a comparison such as >= 2", s)
+	}
+	return Threshold{Op: op, Value: value}, nil
+}
+
+// Met reports whether a measured value satisfies the threshold
+func (t Threshold) Met(v int) bool {
+	switch t.Op {
+	case ">":
+		return v > t.Value
+	case "<=":
+		return v <= t.Value
+	case "<":
+		return v < t.Value
+	case "==":
+		return v == t.Value
+	default:
+		return v >= t.Value
+	}
+}
+
+func (t Threshold) String() string {
+	return t.Op + " " + strconv.Itoa(t.Value)
+}
+
+func (t Threshold) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Threshold) UnmarshalText(text []byte) error {
+	parsed, err := ParseThreshold(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Pattern is a format a captured value must have, as a regular expression
+type Pattern struct {
+	*regexp.Regexp
+}
+
+func (p Pattern) MarshalText() ([]byte, error) {
+	if p.Regexp == nil {
+		return nil, nil
+	}
+	return []byte(p.String()), nil
+}
+
+func (p *Pattern) UnmarshalText(text []byte) error {
+	re, err := regexp.Compile(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", text, err)
+	}
+	p.Regexp = re
+	return nil
+}
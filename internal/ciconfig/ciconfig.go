@@ -0,0 +1,592 @@
+// Package ciconfig reads a project's GitLab CI/CD configuration the way
+// GitLab assembles it: following includes, merging variables blocks, and
+// resolving extends, while recording where each variable was defined.
+package ciconfig
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/go-backend/internal/gitlab"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is where GitLab looks for a project's configuration unless the
+// project sets another
+const DefaultPath = ".gitlab-ci.yml"
+
+// MaxIncludes bounds the files one configuration may include, as GitLab does
+const MaxIncludes = 150
+
+// Fetcher reads the files a configuration is assembled from. *gitlab.Client
+// implements it. Missing files return an error matching gitlab.ErrNotFound.
+type Fetcher interface {
+	GetRawFile(ctx context.Context, projectID, filePath, ref string) ([]byte, error)
+	GetCITemplate(ctx context.Context, name string) ([]byte, error)
+}
+
+// Source kinds
+const (
+	SourceFile            = "file"             // A repository file
+	SourceTemplate        = "template"         // A template shipped with GitLab
+	SourceRemote          = "remote"           // A file included by URL
+	SourceProjectSettings = "project_settings" // The project's CI/CD variables
+	SourceGroupSettings   = "group_settings"   // A group's CI/CD variables
+)
+
+// Source says where a variable was defined. Files of the scanned project at
+// the ref being read leave Project and Ref empty.
+type Source struct {
+	Kind    string `json:"kind" enums:"file,template,remote,project_settings,group_settings"`
+	Project string `json:"project,omitempty"` // Project of a file from another project
+	Path    string `json:"path,omitempty"`    // File path, template name or URL
+	Ref     string `json:"ref,omitempty"`     // Ref of a file from another project
+	Group   string `json:"group,omitempty"`   // Group whose settings define the variable
+	Job     string `json:"job,omitempty"`     // Job whose variables define it, if not global
+}
+
+func (s Source) String() string {
+	var where string
+	switch s.Kind {
+	case SourceTemplate:
+		where = "template " + s.Path
+	case SourceProjectSettings:
+		return "the project's CI/CD settings"
+	case SourceGroupSettings:
+		return "the CI/CD settings of group " + s.Group
+	default:
+		where = s.Path
+		if s.Project != "" {
+			where += " in " + s.Project
+			if s.Ref != "" {
+				where += "@" + s.Ref
+			}
+		}
+	}
+	if s.Job != "" {
+		return "job " + s.Job + " in " + where
+	}
+	return where
+}
+
+// Variable is a variable's definition and the lower precedence definitions
+// it takes the place of, highest first
+type Variable struct {
+	Name      string   `json:"name" example:"APP_NAME"`
+	Value     string   `json:"value" example:"payments"`
+	Source    Source   `json:"source"`
+	Overrides []Source `json:"overrides,omitempty"`
+}
+
+// override returns v defined again by src
+func (v *Variable) override(value string, src Source) *Variable {
+	return &Variable{
+		Name:      v.Name,
+		Value:     value,
+		Source:    src,
+		Overrides: append([]Source{v.Source}, v.Overrides...),
+	}
+}
+
+// Job is a job or hidden job, with the variables and reports it ends up
+// with once extends is resolved
+type Job struct {
+	Name      string               `json:"name"`
+	Source    Source               `json:"source"` // The last file defining the job
+	Extends   []string             `json:"extends,omitempty"`
+	Variables map[string]*Variable `json:"variables,omitempty"`
+	Reports   []string             `json:"reports,omitempty" example:"sast"` // Report types under artifacts:reports
+}
+
+// Hidden reports whether the job only serves as a template for others
+func (j *Job) Hidden() bool {
+	return strings.HasPrefix(j.Name, ".")
+}
+
+// Unresolved is an include or settings source that could not be read
+type Unresolved struct {
+	Source Source `json:"source"`
+	Reason string `json:"reason" example:"not found"`
+}
+
+// Config is an assembled CI/CD configuration
+type Config struct {
+	Files      []Source             `json:"files"` // Every file read, in the order GitLab merges them
+	Unresolved []Unresolved         `json:"unresolved,omitempty"`
+	Variables  map[string]*Variable `json:"variables"` // Global variables, including settings once applied
+	Jobs       map[string]*Job      `json:"jobs"`
+}
+
+// Lookup returns the definition of the named variable the pipeline sees: a
+// settings or global variable, else the first visible job setting it, or
+// nil if nothing does
+func (c *Config) Lookup(name string) *Variable {
+	if v, ok := c.Variables[name]; ok {
+		return v
+	}
+	for _, job := range c.sortedJobs() {
+		if v, ok := job.Variables[name]; ok && !job.Hidden() {
+			return v
+		}
+	}
+	return nil
+}
+
+func (c *Config) sortedJobs() []*Job {
+	jobs := make([]*Job, 0, len(c.Jobs))
+	for _, job := range c.Jobs {
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b *Job) int { return cmp.Compare(a.Name, b.Name) })
+	return jobs
+}
+
+// ApplySettings layers CI/CD variables set in project or group settings over
+// the variables of the files, which they take precedence over. Apply group
+// settings from the top-level group down, then the project's. Variables
+// scoped to particular environments are left out.
+func (c *Config) ApplySettings(src Source, vars []gitlab.Variable) {
+	for _, v := range vars {
+		if v.EnvironmentScope != "" && v.EnvironmentScope != "*" {
+			continue
+		}
+		c.setVariable(c.Variables, v.Key, v.Value, src)
+	}
+}
+
+func (c *Config) setVariable(vars map[string]*Variable, name, value string, src Source) {
+	if prev, ok := vars[name]; ok {
+		vars[name] = prev.override(value, src)
+		return
+	}
+	vars[name] = &Variable{Name: name, Value: value, Source: src}
+}
+
+// ParseError reports a file that is not valid YAML
+type ParseError struct {
+	Source Source
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Source, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParsePath splits a project's CI/CD configuration path setting, which may
+// name a file in another project as path@group/project or
+// path@group/project:ref. An empty setting means DefaultPath.
+func ParsePath(setting string) Source {
+	src := Source{Kind: SourceFile, Path: cmp.Or(setting, DefaultPath)}
+	if strings.Contains(src.Path, "://") {
+		src.Kind = SourceRemote
+		return src
+	}
+	if path, project, ok := strings.Cut(src.Path, "@"); ok {
+		src.Path, src.Project = path, project
+		if project, ref, ok := strings.Cut(project, ":"); ok {
+			src.Project, src.Ref = project, ref
+		}
+	}
+	return src
+}
+
+// Load reads the configuration at main, a file of projectID at ref unless it
+// names another project, and everything it includes. Includes that cannot be
+// followed are listed as unresolved; a missing main file returns an error
+// matching gitlab.ErrNotFound.
+func Load(ctx context.Context, f Fetcher, projectID, ref string, main Source) (*Config, error) {
+	l := &loader{
+		fetcher:   f,
+		projectID: projectID,
+		ref:       ref,
+		seen:      make(map[Source]bool),
+	}
+
+	if main.Kind == SourceRemote {
+		return nil, fmt.Errorf("%s: %w", main.Path, gitlab.ErrNotFound)
+	}
+	l.seen[main] = true
+	if err := l.load(ctx, main); err != nil {
+		return nil, err
+	}
+	return l.assemble(), nil
+}
+
+// document is one file's parsed content
+type document struct {
+	source Source
+	root   *yaml.Node // A mapping, or nil for an empty file
+}
+
+type loader struct {
+	fetcher   Fetcher
+	projectID string
+	ref       string
+
+	seen       map[Source]bool
+	docs       []document // In merge order: each file after its includes
+	unresolved []Unresolved
+}
+
+func (l *loader) fetch(ctx context.Context, src Source) ([]byte, error) {
+	if src.Kind == SourceTemplate {
+		return l.fetcher.GetCITemplate(ctx, src.Path)
+	}
+	if src.Project == "" {
+		return l.fetcher.GetRawFile(ctx, l.projectID, src.Path, l.ref)
+	}
+	return l.fetcher.GetRawFile(ctx, src.Project, src.Path, src.Ref)
+}
+
+func (l *loader) load(ctx context.Context, src Source) error {
+	content, err := l.fetch(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return &ParseError{Source: src, Err: err}
+	}
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = resolve(doc.Content[0])
+		if root.Kind != yaml.MappingNode {
+			return &ParseError{Source: src, Err: errors.New("configuration is not a mapping")}
+		}
+	}
+
+	// Included files are merged first, so the including file overrides them
+	for _, include := range l.includes(src, lookup(root, "include")) {
+		if l.seen[include] {
+			continue
+		}
+		if len(l.seen) > MaxIncludes {
+			l.unresolved = append(l.unresolved, Unresolved{Source: include, Reason: fmt.Sprintf("more than %d includes", MaxIncludes)})
+			continue
+		}
+		l.seen[include] = true
+
+		err := l.load(ctx, include)
+		if errors.Is(err, gitlab.ErrNotFound) {
+			l.unresolved = append(l.unresolved, Unresolved{Source: include, Reason: "not found"})
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	l.docs = append(l.docs, document{source: src, root: root})
+	return nil
+}
+
+// includes returns the files an include keyword names. Those that cannot be
+// fetched are recorded as unresolved.
+func (l *loader) includes(from Source, node *yaml.Node) []Source {
+	if node == nil {
+		return nil
+	}
+
+	var entries []*yaml.Node
+	if node.Kind == yaml.SequenceNode {
+		for _, n := range node.Content {
+			entries = append(entries, resolve(n))
+		}
+	} else {
+		entries = append(entries, node)
+	}
+
+	var sources []Source
+	for _, entry := range entries {
+		if entry.Kind == yaml.ScalarNode {
+			if strings.Contains(entry.Value, "://") {
+				sources = append(sources, Source{Kind: SourceRemote, Path: entry.Value})
+			} else {
+				sources = append(sources, local(from, entry.Value))
+			}
+			continue
+		}
+
+		switch {
+		case lookup(entry, "local") != nil:
+			sources = append(sources, local(from, scalar(lookup(entry, "local"))))
+		case lookup(entry, "project") != nil:
+			project, ref := scalar(lookup(entry, "project")), scalar(lookup(entry, "ref"))
+			for _, path := range scalars(lookup(entry, "file")) {
+				sources = append(sources, Source{Kind: SourceFile, Project: project, Path: strings.TrimPrefix(path, "/"), Ref: ref})
+			}
+		case lookup(entry, "template") != nil:
+			sources = append(sources, Source{Kind: SourceTemplate, Path: scalar(lookup(entry, "template"))})
+		case lookup(entry, "remote") != nil:
+			sources = append(sources, Source{Kind: SourceRemote, Path: scalar(lookup(entry, "remote"))})
+		default:
+			l.unresolved = append(l.unresolved, Unresolved{Source: from, Reason: "unsupported include"})
+		}
+	}
+
+	// Only repository files and templates can be fetched through the API
+	fetchable := sources[:0]
+	for _, src := range sources {
+		switch {
+		case src.Kind == SourceRemote:
+			l.unresolved = append(l.unresolved, Unresolved{Source: src, Reason: "remote includes are not fetched"})
+		case strings.Contains(src.Path, "*"):
+			l.unresolved = append(l.unresolved, Unresolved{Source: src, Reason: "wildcard includes are not expanded"})
+		case src.Kind == SourceFile && src.Path == "":
+			l.unresolved = append(l.unresolved, Unresolved{Source: from, Reason: "include names no file"})
+		default:
+			fetchable = append(fetchable, src)
+		}
+	}
+	return fetchable
+}
+
+// local returns a file of the project from is in
+func local(from Source, path string) Source {
+	return Source{Kind: SourceFile, Project: from.Project, Path: strings.TrimPrefix(path, "/"), Ref: from.Ref}
+}
+
+// globalKeywords are the top-level keys that are not jobs
+var globalKeywords = map[string]bool{
+	"default":       true,
+	"include":       true,
+	"stages":        true,
+	"variables":     true,
+	"workflow":      true,
+	"image":         true,
+	"services":      true,
+	"cache":         true,
+	"before_script": true,
+	"after_script":  true,
+	"types":         true,
+}
+
+// assemble merges the documents' variables and jobs in order and resolves
+// extends
+func (l *loader) assemble() *Config {
+	c := &Config{
+		Unresolved: l.unresolved,
+		Variables:  make(map[string]*Variable),
+		Jobs:       make(map[string]*Job),
+	}
+
+	// Variables and reports defined by each job itself, before extends
+	own := make(map[string]map[string]*Variable)
+	ownReports := make(map[string][]string)
+
+	for _, doc := range l.docs {
+		c.Files = append(c.Files, doc.source)
+
+		for _, pair := range pairs(doc.root) {
+			key, value := pair[0].Value, pair[1]
+			if key == "variables" {
+				for name, v := range variables(value) {
+					c.setVariable(c.Variables, name, v, doc.source)
+				}
+				continue
+			}
+			if globalKeywords[key] || value.Kind != yaml.MappingNode {
+				continue
+			}
+
+			job, ok := c.Jobs[key]
+			if !ok {
+				job = &Job{Name: key}
+				c.Jobs[key] = job
+				own[key] = make(map[string]*Variable)
+			}
+			job.Source = doc.source
+			if extends := lookup(value, "extends"); extends != nil {
+				job.Extends = scalars(extends)
+			}
+			src := doc.source
+			src.Job = key
+			for name, v := range variables(lookup(value, "variables")) {
+				c.setVariable(own[key], name, v, src)
+			}
+			for _, pair := range pairs(lookup(lookup(value, "artifacts"), "reports")) {
+				ownReports[key] = append(ownReports[key], pair[0].Value)
+			}
+		}
+	}
+
+	resolved := make(map[string]map[string]*Variable)
+	for name, job := range c.Jobs {
+		job.Variables = c.resolveJob(name, own, resolved, nil)
+		job.Reports = c.jobReports(name, ownReports, nil)
+	}
+	return c
+}
+
+// jobReports returns the report types a job uploads, its own and those of
+// the jobs it extends, sorted. Unknown and circular parents are ignored.
+func (c *Config) jobReports(name string, own map[string][]string, chain []string) []string {
+	reports := slices.Clone(own[name])
+	if len(chain) < maxExtendsDepth {
+		for _, parent := range c.Jobs[name].Extends {
+			if _, ok := c.Jobs[parent]; !ok || slices.Contains(chain, parent) || parent == name {
+				continue
+			}
+			reports = append(reports, c.jobReports(parent, own, append(chain, name))...)
+		}
+	}
+	slices.Sort(reports)
+	return slices.Compact(reports)
+}
+
+// ReportJobs returns the visible jobs uploading the report type, by name
+func (c *Config) ReportJobs(report string) []*Job {
+	var jobs []*Job
+	for _, job := range c.sortedJobs() {
+		if !job.Hidden() && slices.Contains(job.Reports, report) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// maxExtendsDepth bounds extends chains, as GitLab does
+const maxExtendsDepth = 11
+
+// resolveJob returns a job's variables with those of the jobs it extends
+// merged beneath its own. Unknown and circular parents are ignored.
+func (c *Config) resolveJob(name string, own, resolved map[string]map[string]*Variable, chain []string) map[string]*Variable {
+	if vars, ok := resolved[name]; ok {
+		return vars
+	}
+
+	vars := make(map[string]*Variable)
+	if len(chain) < maxExtendsDepth {
+		for _, parent := range c.Jobs[name].Extends {
+			if _, ok := c.Jobs[parent]; !ok || slices.Contains(chain, parent) || parent == name {
+				continue
+			}
+			for varName, v := range c.resolveJob(parent, own, resolved, append(chain, name)) {
+				if prev, ok := vars[varName]; ok {
+					vars[varName] = prev.override(v.Value, v.Source)
+				} else {
+					vars[varName] = v
+				}
+			}
+		}
+	}
+	for varName, v := range own[name] {
+		if prev, ok := vars[varName]; ok {
+			vars[varName] = prev.override(v.Value, v.Source)
+		} else {
+			vars[varName] = v
+		}
+	}
+
+	resolved[name] = vars
+	return vars
+}
+
+// variables returns the values of a variables block. A variable is a scalar
+// or a mapping with a value key; anything else, such as a !reference, is
+// skipped.
+func variables(node *yaml.Node) map[string]string {
+	vars := make(map[string]string)
+	for _, pair := range pairs(node) {
+		value := pair[1]
+		if value.Kind == yaml.MappingNode {
+			value = lookup(value, "value")
+		}
+		if value != nil && value.Kind == yaml.ScalarNode && value.Tag != "!reference" {
+			vars[pair[0].Value] = nullString(value)
+		}
+	}
+	return vars
+}
+
+// nullString returns a scalar's value, with YAML's null as an empty string
+func nullString(n *yaml.Node) string {
+	if n.Tag == "!!null" {
+		return ""
+	}
+	return n.Value
+}
+
+// resolve follows an alias to the node it refers to
+func resolve(n *yaml.Node) *yaml.Node {
+	for n != nil && n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	return n
+}
+
+// pairs returns a mapping's key and value nodes in order, expanding merge
+// keys (<<: *anchor) so explicit keys override merged ones
+func pairs(n *yaml.Node) [][2]*yaml.Node {
+	n = resolve(n)
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var merged, explicit [][2]*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], resolve(n.Content[i+1])
+		if key.ShortTag() != "!!merge" {
+			explicit = append(explicit, [2]*yaml.Node{key, value})
+			continue
+		}
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, src := range sources {
+			merged = append(merged, pairs(src)...)
+		}
+	}
+
+	result := make([][2]*yaml.Node, 0, len(merged)+len(explicit))
+	for _, pair := range merged {
+		if !slices.ContainsFunc(explicit, func(e [2]*yaml.Node) bool { return e[0].Value == pair[0].Value }) {
+			result = append(result, pair)
+		}
+	}
+	return append(result, explicit...)
+}
+
+// lookup returns the value of a mapping's key, or nil
+func lookup(n *yaml.Node, key string) *yaml.Node {
+	var value *yaml.Node
+	for _, pair := range pairs(n) {
+		if pair[0].Value == key {
+			value = pair[1]
+		}
+	}
+	return value
+}
+
+// scalar returns a scalar node's value, or "" for any other node
+func scalar(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return nullString(n)
+}
+
+// scalars returns a scalar or a sequence of scalars as a list
+func scalars(n *yaml.Node) []string {
+	if n == nil {
+		return nil
+	}
+	if n.Kind != yaml.SequenceNode {
+		return []string{scalar(n)}
+	}
+	values := make([]string, 0, len(n.Content))
+	for _, item := range n.Content {
+		values = append(values, scalar(resolve(item)))
+	}
+	return values
+}
@@ -0,0 +1,180 @@
+package ciconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/user/go-backend/internal/gitlab"
+)
+
+// stubFetcher serves files keyed by project:path@ref and templates by name
+type stubFetcher struct {
+	files     map[string]string
+	templates map[string]string
+}
+
+func (f stubFetcher) GetRawFile(ctx context.Context, projectID, filePath, ref string) ([]byte, error) {
+	content, ok := f.files[projectID+":"+filePath+"@"+ref]
+	if !ok {
+		return nil, gitlab.ErrNotFound
+	}
+	return []byte(content), nil
+}
+
+func (f stubFetcher) GetCITemplate(ctx context.Context, name string) ([]byte, error) {
+	content, ok := f.templates[name]
+	if !ok {
+		return nil, gitlab.ErrNotFound
+	}
+	return []byte(content), nil
+}
+
+func TestLoad(t *testing.T) {
+	f := stubFetcher{
+		files: map[string]string{
+			"42:.gitlab-ci.yml@main": `
+include:
+  - local: /ci/base.yml
+  - project: platform/ci-templates
+    ref: v2
+    file: [app.yml]
+  - template: Security/SAST.gitlab-ci.yml
+  - remote: https://example.com/ci.yml
+  - ci/missing.yml
+variables:
+  APP_NAME: payments
+build:
+  extends: .app
+  script: make
+`,
+			"42:ci/base.yml@main": `
+variables:
+  APP_NAME: base
+  STAGE: build
+`,
+			"platform/ci-templates:app.yml@v2": `
+include: common.yml
+.defaults: &defaults
+  variables:
+    MOAB_ID: MOAB-000001
+.app:
+  <<: *defaults
+  script: deploy
+`,
+			"platform/ci-templates:common.yml@v2": "variables:\n  STAGE: common\n",
+		},
+		templates: map[string]string{
+			"Security/SAST.gitlab-ci.yml": `
+sast:
+  variables:
+    SAST_EXCLUDED_PATHS: spec
+.sast-analyzer:
+  extends: sast
+  artifacts:
+    reports:
+      sast: gl-sast-report.json
+semgrep-sast:
+  extends: .sast-analyzer
+`,
+		},
+	}
+
+	c, err := Load(context.Background(), f, "42", "main", ParsePath(""))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// The including file overrides its includes, which merge in order
+	app := c.Lookup("APP_NAME")
+	if app == nil || app.Value != "payments" || app.Source.Path != ".gitlab-ci.yml" || len(app.Overrides) != 1 || app.Overrides[0].Path != "ci/base.yml" {
+		t.Errorf("APP_NAME = %+v", app)
+	}
+	if stage := c.Lookup("STAGE"); stage == nil || stage.Value != "common" || stage.Source.Project != "platform/ci-templates" || stage.Source.Ref != "v2" {
+		t.Errorf("STAGE = %+v", stage)
+	}
+
+	// Job variables come through extends and merge keys
+	moab := c.Lookup("MOAB_ID")
+	if moab == nil || moab.Value != "MOAB-000001" || moab.Source.Job != ".app" || moab.Source.Path != "app.yml" {
+		t.Errorf("MOAB_ID = %+v", moab)
+	}
+	if got := moab.Source.String(); got != "job .app in app.yml in platform/ci-templates@v2" {
+		t.Errorf("MOAB_ID source = %q", got)
+	}
+	if v := c.Jobs["sast"].Variables["SAST_EXCLUDED_PATHS"]; v == nil || v.Source.Kind != SourceTemplate {
+		t.Errorf("template job variable = %+v", v)
+	}
+
+	// Reports come through extends too, and only visible jobs upload them
+	if jobs := c.ReportJobs("sast"); len(jobs) != 1 || jobs[0].Name != "semgrep-sast" || jobs[0].Source.Kind != SourceTemplate {
+		t.Errorf("ReportJobs(sast) = %+v", jobs)
+	}
+
+	if len(c.Files) != 5 || c.Files[0].Path != "ci/base.yml" || c.Files[4].Path != ".gitlab-ci.yml" {
+		t.Errorf("Files = %+v", c.Files)
+	}
+	if len(c.Unresolved) != 2 || c.Unresolved[0].Source.Kind != SourceRemote || c.Unresolved[1].Source.Path != "ci/missing.yml" || c.Unresolved[1].Reason != "not found" {
+		t.Errorf("Unresolved = %+v", c.Unresolved)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	f := stubFetcher{files: map[string]string{
+		"42:.gitlab-ci.yml@main": "include: bad.yml\n",
+		"42:bad.yml@main":        "variables: [unclosed\n",
+		"42:loop.yml@main":       "include: loop.yml\nvariables:\n  A: 1\n",
+	}}
+	ctx := context.Background()
+
+	var parseErr *ParseError
+	if _, err := Load(ctx, f, "42", "main", ParsePath("")); !errors.As(err, &parseErr) || parseErr.Source.Path != "bad.yml" {
+		t.Errorf("Load() with invalid include error = %v, want a ParseError for bad.yml", err)
+	}
+	if _, err := Load(ctx, f, "42", "main", ParsePath("missing.yml")); !errors.Is(err, gitlab.ErrNotFound) {
+		t.Errorf("Load() of a missing file error = %v, want ErrNotFound", err)
+	}
+
+	// A file including itself is read once
+	c, err := Load(ctx, f, "42", "main", ParsePath("loop.yml"))
+	if err != nil || len(c.Files) != 1 || c.Lookup("A").Value != "1" {
+		t.Errorf("Load() of a self-including file = %+v, %v", c, err)
+	}
+}
+
+func TestConfig_ApplySettings(t *testing.T) {
+	f := stubFetcher{files: map[string]string{"42:.gitlab-ci.yml@main": "variables:\n  APP_NAME: file\n"}}
+	c, err := Load(context.Background(), f, "42", "main", ParsePath(""))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	c.ApplySettings(Source{Kind: SourceGroupSettings, Group: "platform"}, []gitlab.Variable{
+		{Key: "APP_NAME", Value: "group", EnvironmentScope: "*"},
+		{Key: "MOAB_ID", Value: "MOAB-123456", EnvironmentScope: "production"},
+	})
+	c.ApplySettings(Source{Kind: SourceProjectSettings}, []gitlab.Variable{{Key: "APP_NAME", Value: "project", EnvironmentScope: "*"}})
+
+	app := c.Lookup("APP_NAME")
+	if app.Value != "project" || len(app.Overrides) != 2 || app.Overrides[0].Group != "platform" || app.Overrides[1].Kind != SourceFile {
+		t.Errorf("APP_NAME = %+v", app)
+	}
+	if v := c.Lookup("MOAB_ID"); v != nil {
+		t.Errorf("environment-scoped MOAB_ID = %+v, want unset", v)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := map[string]Source{
+		"":                             {Kind: SourceFile, Path: ".gitlab-ci.yml"},
+		"ci/main.yml":                  {Kind: SourceFile, Path: "ci/main.yml"},
+		"ci.yml@platform/pipelines":    {Kind: SourceFile, Path: "ci.yml", Project: "platform/pipelines"},
+		"ci.yml@platform/pipelines:v1": {Kind: SourceFile, Path: "ci.yml", Project: "platform/pipelines", Ref: "v1"},
+		"https://example.com/ci.yml":   {Kind: SourceRemote, Path: "https://example.com/ci.yml"},
+	}
+	for setting, want := range tests {
+		if got := ParsePath(setting); got != want {
+			t.Errorf("ParsePath(%q) = %+v, want %+v", setting, got, want)
+		}
+	}
+}
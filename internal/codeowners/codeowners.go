@@ -0,0 +1,259 @@
+// Package codeowners parses GitLab CODEOWNERS files into sections, rules and
+// owners, reporting the lines GitLab would not understand.
+package codeowners
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Owner kinds
+const (
+	OwnerUser  = "user"  // @username, which may also name a top-level group
+	OwnerGroup = "group" // @group/subgroup
+	OwnerRole  = "role"  // @@developer, @@maintainer or @@owner
+	OwnerEmail = "email" // user@example.com
+)
+
+// roles are the role names an @@ owner may give
+var roles = map[string]bool{
+	"developer": true, "developers": true,
+	"maintainer": true, "maintainers": true,
+	"owner": true, "owners": true,
+}
+
+// OwnerKind returns the kind of an owner, or "" if it is not a valid owner
+func OwnerKind(owner string) string {
+	switch {
+	case strings.HasPrefix(owner, "@@"):
+		if roles[strings.ToLower(owner[2:])] {
+			return OwnerRole
+		}
+	case strings.HasPrefix(owner, "@"):
+		name := owner[1:]
+		if name == "" || strings.ContainsAny(name, "@") || strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/") {
+			return ""
+		}
+		if strings.Contains(name, "/") {
+			return OwnerGroup
+		}
+		return OwnerUser
+	case strings.Count(owner, "@") == 1 && strings.Contains(owner[strings.Index(owner, "@"):], "."):
+		return OwnerEmail
+	}
+	return ""
+}
+
+// Rule assigns owners to the files a pattern matches
+type Rule struct {
+	Pattern string   `json:"pattern" example:"/db/"`
+	Owners  []string `json:"owners"` // The section's default owners when the rule names none
+	Line    int      `json:"line"`
+}
+
+// CatchAll reports whether the rule's pattern matches every file
+func (r Rule) CatchAll() bool {
+	switch r.Pattern {
+	case "*", "**", "/**", "/**/*":
+		return true
+	}
+	return false
+}
+
+// Section is a [Section] of a CODEOWNERS file. Rules before any section
+// header belong to a section without a name.
+type Section struct {
+	Name          string   `json:"name,omitempty" example:"Backend"`
+	Optional      bool     `json:"optional,omitempty"`  // Marked ^[Section]: approval is not required
+	Approvals     int      `json:"approvals,omitempty"` // Required approvals, as in [Section][2]
+	DefaultOwners []string `json:"default_owners,omitempty"`
+	Rules         []Rule   `json:"rules"`
+	Line          int      `json:"line,omitempty"`
+}
+
+// SyntaxError is a line GitLab would not understand
+type SyntaxError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message" example:"invalid owner \"team\""`
+}
+
+func (e SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Sections []*Section
+	Errors   []SyntaxError
+}
+
+// sectionHeader matches [Name], ^[Name], [Name][2] and ^[Name] @owner
+var sectionHeader = regexp.MustCompile(`^(\^)?\[([^\]]*)\](?:\[([^\]]*)\])?(.*)$`)
+
+// Parse reads a CODEOWNERS file. Sections with the same name, compared
+// without regard to case, are combined as GitLab combines them.
+func Parse(content []byte) *File {
+	f := &File{}
+	current := &Section{}
+	f.Sections = append(f.Sections, current)
+	byName := map[string]*Section{"": current}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		lineNo := i + 1
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			m := sectionHeader.FindStringSubmatch(line)
+			if m == nil {
+				f.errorf(lineNo, "malformed section header %q", line)
+				continue
+			}
+			name := strings.TrimSpace(m[2])
+			if name == "" {
+				f.errorf(lineNo, "section name is empty")
+				continue
+			}
+
+			key := strings.ToLower(name)
+			section, ok := byName[key]
+			if !ok {
+				section = &Section{Name: name, Line: lineNo}
+				byName[key] = section
+				f.Sections = append(f.Sections, section)
+			}
+			section.Optional = m[1] != ""
+			if m[3] != "" {
+				n, err := strconv.Atoi(m[3])
+				if err != nil || n < 1 {
+					f.errorf(lineNo, "invalid approval count %q", m[3])
+				} else {
+					section.Approvals = n
+				}
+			}
+			if owners := f.owners(lineNo, strings.Fields(m[4])); len(owners) > 0 {
+				section.DefaultOwners = owners
+			}
+			current = section
+			continue
+		}
+
+		pattern, rest := splitPattern(line)
+		rule := Rule{Pattern: pattern, Owners: f.owners(lineNo, strings.Fields(rest)), Line: lineNo}
+		if len(rule.Owners) == 0 {
+			if len(current.DefaultOwners) == 0 {
+				f.errorf(lineNo, "pattern %q has no owners", pattern)
+			}
+			rule.Owners = current.DefaultOwners
+		}
+		current.Rules = append(current.Rules, rule)
+	}
+
+	if len(f.Sections[0].Rules) == 0 {
+		f.Sections = f.Sections[1:]
+	}
+	return f
+}
+
+func (f *File) errorf(line int, format string, args ...any) {
+	f.Errors = append(f.Errors, SyntaxError{Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// owners returns the valid owners of a line, reporting the others
+func (f *File) owners(line int, fields []string) []string {
+	var owners []string
+	for _, owner := range fields {
+		if OwnerKind(owner) == "" {
+			f.errorf(line, "invalid owner %q", owner)
+			continue
+		}
+		owners = append(owners, owner)
+	}
+	return owners
+}
+
+// splitPattern splits a rule into its pattern, in which spaces may be
+// escaped with a backslash, and the rest of the line
+func splitPattern(line string) (pattern, rest string) {
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line):
+			i++
+			if line[i] != ' ' && line[i] != '#' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(line[i])
+		case c == ' ' || c == '\t':
+			return b.String(), line[i:]
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), ""
+}
+
+// Rules returns how many rules the file has
+func (f *File) Rules() int {
+	n := 0
+	for _, s := range f.Sections {
+		n += len(s.Rules)
+	}
+	return n
+}
+
+// NamedSections returns how many [Section] headers the file has
+func (f *File) NamedSections() int {
+	n := 0
+	for _, s := range f.Sections {
+		if s.Name != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Owners returns every owner the file names, sorted and without duplicates
+func (f *File) Owners() []string {
+	var owners []string
+	for _, s := range f.Sections {
+		owners = append(owners, s.DefaultOwners...)
+		for _, r := range s.Rules {
+			owners = append(owners, r.Owners...)
+		}
+	}
+	slices.Sort(owners)
+	return slices.Compact(owners)
+}
+
+// OptionalSections returns the names of the sections marked optional
+func (f *File) OptionalSections() []string {
+	var names []string
+	for _, s := range f.Sections {
+		if s.Optional {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+// CatchAll returns the first owned rule matching every file in a section
+// whose approval is required, or nil if there is none
+func (f *File) CatchAll() *Rule {
+	for _, s := range f.Sections {
+		if s.Optional {
+			continue
+		}
+		for i, r := range s.Rules {
+			if r.CatchAll() && len(r.Owners) > 0 {
+				return &s.Rules[i]
+			}
+		}
+	}
+	return nil
+}
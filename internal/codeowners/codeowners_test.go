@@ -0,0 +1,94 @@
+package codeowners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	f := Parse([]byte(`# Global owners
+* @platform/leads
+
+[Backend][2] @alice @bob
+/app/
+/db/ @dba
+
+^[Docs]
+/docs/ writers@example.com
+/my\ notes/ @carol
+
+[backend]
+/lib/ @dave
+`))
+
+	if len(f.Errors) != 0 {
+		t.Fatalf("Parse() errors = %v", f.Errors)
+	}
+	if len(f.Sections) != 3 || f.NamedSections() != 2 || f.Rules() != 6 {
+		t.Fatalf("Parse() sections = %+v", f.Sections)
+	}
+
+	backend := f.Sections[1]
+	if backend.Name != "Backend" || backend.Approvals != 2 || len(backend.Rules) != 3 {
+		t.Errorf("Backend section = %+v", backend)
+	}
+	if r := backend.Rules[0]; r.Pattern != "/app/" || !slices.Equal(r.Owners, []string{"@alice", "@bob"}) {
+		t.Errorf("rule without owners = %+v, want the section's default owners", r)
+	}
+	if r := f.Sections[2].Rules[1]; r.Pattern != "/my notes/" || r.Line != 10 {
+		t.Errorf("escaped pattern = %+v", r)
+	}
+
+	if got := f.OptionalSections(); !slices.Equal(got, []string{"Docs"}) {
+		t.Errorf("OptionalSections() = %v", got)
+	}
+	want := []string{"@alice", "@bob", "@carol", "@dave", "@dba", "@platform/leads", "writers@example.com"}
+	if got := f.Owners(); !slices.Equal(got, want) {
+		t.Errorf("Owners() = %v, want %v", got, want)
+	}
+	if r := f.CatchAll(); r == nil || r.Line != 2 {
+		t.Errorf("CatchAll() = %+v", r)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	f := Parse([]byte(`[]
+[Backend][0]
+[Unclosed
+/app/
+/lib/ team @@admins @ok
+^[Optional]
+* @docs
+`))
+
+	var lines []int
+	for _, e := range f.Errors {
+		lines = append(lines, e.Line)
+	}
+	if !slices.Equal(lines, []int{1, 2, 3, 4, 5, 5}) {
+		t.Errorf("Parse() errors = %v", f.Errors)
+	}
+
+	// A catch-all in an optional section does not count
+	if r := f.CatchAll(); r != nil {
+		t.Errorf("CatchAll() = %+v, want nil", r)
+	}
+}
+
+func TestOwnerKind(t *testing.T) {
+	tests := map[string]string{
+		"@alice":            OwnerUser,
+		"@platform/backend": OwnerGroup,
+		"@@maintainers":     OwnerRole,
+		"@@admins":          "",
+		"dev@example.com":   OwnerEmail,
+		"@":                 "",
+		"@a@b":              "",
+		"team":              "",
+	}
+	for owner, want := range tests {
+		if got := OwnerKind(owner); got != want {
+			t.Errorf("OwnerKind(%q) = %q, want %q", owner, got, want)
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
 )
 
 type Config struct {
@@ -18,6 +24,27 @@ type Config struct {
 	LogLevel string
 
 	Environment string // "development", "production", etc.
+
+	// GitLab instances projects are registered against, the default one
+	// first. Instances without a token are not scanned, without a webhook
+	// secret do not accept hooks and without sync groups are not synced.
+	GitLabInstances []GitLabInstance
+
+	GitLabSyncInterval time.Duration // Time between group syncs
+
+	// Readiness profiles projects can be held to. A profile named "default"
+	// replaces the thresholds of projects without one.
+	Profiles []checks.Profile
+
+	IdempotencyTTL time.Duration // How long responses to Idempotency-Key requests are replayed
+
+	// WebhookSecretKey encrypts webhook subscription secrets at rest. They
+	// are stored unencrypted when it is empty.
+	WebhookSecretKey []byte
+
+	OutboxHTTPURL   string        // Also relay events as NDJSON to this URL when set
+	OutboxFile      string        // Also append events as NDJSON to this file when set; "-" is stdout
+	OutboxRetention time.Duration // How long published events are kept in the outbox
 }
 
 func Load() (*Config, error) {
@@ -33,6 +60,32 @@ func Load() (*Config, error) {
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 
 		Environment: getEnv("ENVIRONMENT", "development"),
+
+		GitLabSyncInterval: getEnvAsDuration("GITLAB_SYNC_INTERVAL", time.Hour),
+
+		IdempotencyTTL: getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		OutboxHTTPURL:   getEnv("OUTBOX_HTTP_URL", ""),
+		OutboxFile:      getEnv("OUTBOX_FILE", ""),
+		OutboxRetention: getEnvAsDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+	}
+
+	instances, err := loadGitLabInstances()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	cfg.GitLabInstances = instances
+
+	profiles, err := loadProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	cfg.Profiles = profiles
+
+	if key := os.Getenv("WEBHOOK_SECRET_KEY"); key != "" {
+		if cfg.WebhookSecretKey, err = base64.StdEncoding.DecodeString(key); err != nil {
+			return nil, fmt.Errorf("invalid configuration: invalid WEBHOOK_SECRET_KEY: must be base64")
+		}
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -62,9 +115,38 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid LOG_LEVEL: must be debug, info, warn, or error")
 	}
 
+	if c.IdempotencyTTL <= 0 {
+		return fmt.Errorf("invalid IDEMPOTENCY_TTL: must be a positive duration")
+	}
+
+	if n := len(c.WebhookSecretKey); n != 0 && n != 32 {
+		return fmt.Errorf("invalid WEBHOOK_SECRET_KEY: must be 32 bytes, got %d", n)
+	}
+
+	if err := validateGitLabInstances(c.GitLabInstances); err != nil {
+		return err
+	}
+
+	if err := validateProfiles(c.Profiles); err != nil {
+		return err
+	}
+
+	if c.GitLabSyncInterval <= 0 {
+		return fmt.Errorf("invalid GITLAB_SYNC_INTERVAL: must be a positive duration")
+	}
+
+	if c.OutboxRetention <= 0 {
+		return fmt.Errorf("invalid OUTBOX_RETENTION: must be a positive duration")
+	}
+
 	return nil
 }
 
+// ScanningEnabled reports whether a token is configured for any GitLab instance
+func (c *Config) ScanningEnabled() bool {
+	return slices.ContainsFunc(c.GitLabInstances, func(i GitLabInstance) bool { return i.ScanningEnabled() })
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
@@ -88,3 +170,32 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList splits a comma-separated variable, dropping empty entries
+func getEnvAsList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
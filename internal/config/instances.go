@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// instanceNamePattern keeps instance names usable as URL path segments
+var instanceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// GitLabInstance is a GitLab server projects are registered against. Tokens
+// are referenced by environment variable so the registry file holds no
+// secrets.
+type GitLabInstance struct {
+	Name             string   `json:"name"`
+	URL              string   `json:"url"`
+	TokenEnv         string   `json:"token_env"`          // Variable holding an access token with read_api scope
+	WebhookSecretEnv string   `json:"webhook_secret_env"` // Variable holding the secret its hooks send
+	RateLimit        float64  `json:"requests_per_second"`
+	Burst            int      `json:"burst"`
+	SyncGroups       []string `json:"sync_groups"`
+
+	// Resolved from TokenEnv and WebhookSecretEnv
+	Token         string `json:"-"`
+	WebhookSecret string `json:"-"`
+}
+
+// ScanningEnabled reports whether a token is configured for the instance
+func (i *GitLabInstance) ScanningEnabled() bool {
+	return i.Token != ""
+}
+
+// loadGitLabInstances returns the default instance configured through
+// GITLAB_* variables followed by the instances listed in the JSON file at
+// GITLAB_INSTANCES_FILE, if set. A file entry named "default" replaces the
+// one built from the environment.
+func loadGitLabInstances() ([]GitLabInstance, error) {
+	instances := []GitLabInstance{{
+		Name:          models.DefaultInstance,
+		URL:           getEnv("GITLAB_URL", "https://gitlab.com"),
+		Token:         getEnv("GITLAB_TOKEN", ""),
+		WebhookSecret: getEnv("GITLAB_WEBHOOK_SECRET", ""),
+		RateLimit:     getEnvAsFloat("GITLAB_RATE_LIMIT", 0),
+		Burst:         getEnvAsInt("GITLAB_RATE_BURST", 10),
+		SyncGroups:    getEnvAsList("GITLAB_SYNC_GROUPS"),
+	}}
+
+	path := getEnv("GITLAB_INSTANCES_FILE", "")
+	if path == "" {
+		return instances, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GITLAB_INSTANCES_FILE: %w", err)
+	}
+	var listed []GitLabInstance
+	if err := json.Unmarshal(data, &listed); err != nil {
+		return nil, fmt.Errorf("failed to parse GITLAB_INSTANCES_FILE: %w", err)
+	}
+
+	for _, instance := range listed {
+		if instance.TokenEnv != "" {
+			instance.Token = os.Getenv(instance.TokenEnv)
+		}
+		if instance.WebhookSecretEnv != "" {
+			instance.WebhookSecret = os.Getenv(instance.WebhookSecretEnv)
+		}
+		if instance.Burst == 0 {
+			instance.Burst = 10
+		}
+
+		if instance.Name == models.DefaultInstance {
+			instances[0] = instance
+			continue
+		}
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func validateGitLabInstances(instances []GitLabInstance) error {
+	var names []string
+	for _, instance := range instances {
+		if !instanceNamePattern.MatchString(instance.Name) {
+			return fmt.Errorf("invalid GitLab instance name %q: use lowercase letters, digits, - and _", instance.Name)
+		}
+		if slices.Contains(names, instance.Name) {
+			return fmt.Errorf("duplicate GitLab instance %q", instance.Name)
+		}
+		names = append(names, instance.Name)
+
+		if instance.URL == "" {
+			return fmt.Errorf("GitLab instance %q has no url", instance.Name)
+		}
+		if instance.RateLimit < 0 || instance.Burst < 0 {
+			return fmt.Errorf("GitLab instance %q: rate limits must not be negative", instance.Name)
+		}
+		if len(instance.SyncGroups) > 0 && !instance.ScanningEnabled() {
+			return fmt.Errorf("GitLab instance %q: sync groups require a token", instance.Name)
+		}
+	}
+	return nil
+}
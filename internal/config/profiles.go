@@ -0,0 +1,47 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/user/go-backend/internal/checks"
+)
+
+// loadProfiles returns the readiness profiles listed in the JSON file at
+// READINESS_PROFILES_FILE, if set
+func loadProfiles() ([]checks.Profile, error) {
+	path := getEnv("READINESS_PROFILES_FILE", "")
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read READINESS_PROFILES_FILE: %w", err)
+	}
+	var profiles []checks.Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse READINESS_PROFILES_FILE: %w", err)
+	}
+	return profiles, nil
+}
+
+func validateProfiles(profiles []checks.Profile) error {
+	var names []string
+	for _, profile := range profiles {
+		if !instanceNamePattern.MatchString(profile.Name) {
+			return fmt.Errorf("invalid readiness profile name %q: use lowercase letters, digits, - and _", profile.Name)
+		}
+		if slices.Contains(names, profile.Name) {
+			return fmt.Errorf("duplicate readiness profile %q", profile.Name)
+		}
+		names = append(names, profile.Name)
+
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Listen subscribes to a PostgreSQL notification channel on a dedicated
+// connection. The returned channel receives a value whenever a notification
+// arrives, and also after the connection is re-established since
+// notifications may have been missed meanwhile. Notifications arriving
+// faster than they are received are coalesced. The channel is closed once
+// ctx is cancelled.
+func Listen(ctx context.Context, url, channel string) (<-chan struct{}, error) {
+	listener := pq.NewListener(url, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Warn("database listener error", "channel", channel, "error", err)
+		}
+	})
+	if err := listener.Listen(channel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	wake := make(chan struct{}, 1)
+	go func() {
+		defer close(wake)
+		defer listener.Close()
+
+		// Pinging detects a dead connection that would otherwise go unnoticed
+		ping := time.NewTicker(90 * time.Second)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify: // nil after a reconnect
+			case <-ping.C:
+				go listener.Ping()
+				continue
+			}
+
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return wake, nil
+}
@@ -0,0 +1,295 @@
+// Package discovery keeps the project inventory in step with the projects
+// in configured GitLab groups, registering new projects and marking those
+// that left the groups absent.
+package discovery
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+// GroupLister lists every project in a group and its subgroups
+type GroupLister interface {
+	ListGroupProjects(ctx context.Context, group string) ([]gitlab.Project, error)
+}
+
+// Rescanner schedules background rescans of new projects
+type Rescanner interface {
+	Enqueue(key models.ProjectKey, checks []string) bool
+}
+
+type Config struct {
+	Instance string        // GitLab instance the groups are on, default models.DefaultInstance
+	Groups   []string      // Full paths of the groups to sync
+	Interval time.Duration // Time between scheduled syncs, default 1h
+}
+
+type Syncer struct {
+	gitlab   GroupLister
+	projects repository.ProjectRepository
+	runs     repository.SyncRepository
+	rescans  Rescanner
+	cfg      Config
+	logger   *slog.Logger
+	trigger  chan struct{}
+}
+
+// New returns a syncer. rescans may be nil, leaving new projects unscanned
+// until their next scan.
+func New(gl GroupLister, projects repository.ProjectRepository, runs repository.SyncRepository, rescans Rescanner, cfg Config, logger *slog.Logger) *Syncer {
+	if cfg.Instance == "" {
+		cfg.Instance = models.DefaultInstance
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+
+	return &Syncer{
+		gitlab:   gl,
+		projects: projects,
+		runs:     runs,
+		rescans:  rescans,
+		cfg:      cfg,
+		logger:   logger,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// Trigger asks Run to sync now. It reports false when a requested sync has
+// not started yet.
+func (s *Syncer) Trigger() bool {
+	select {
+	case s.trigger <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Run syncs on schedule, and whenever triggered, until ctx is cancelled.
+// Scheduled syncs are skipped while the latest run of the same GitLab
+// instance by any replica is less than an interval old, so replicas take
+// turns.
+func (s *Syncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(min(s.cfg.Interval, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		trigger := ""
+		if s.due(ctx) {
+			trigger = models.SyncTriggerSchedule
+		}
+
+		if trigger == "" {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				continue
+			case <-s.trigger:
+				trigger = models.SyncTriggerManual
+			}
+		}
+
+		if _, err := s.Sync(ctx, trigger); err != nil && ctx.Err() == nil {
+			s.logger.Error("group sync failed", "instance", s.cfg.Instance, "error", err)
+		}
+	}
+}
+
+// due reports whether the latest run started more than an interval ago
+func (s *Syncer) due(ctx context.Context) bool {
+	latest, err := s.runs.LatestRun(ctx, s.cfg.Instance)
+	if err != nil {
+		if err.Error() != "sync run not found" {
+			s.logger.Error("failed to read latest group sync", "instance", s.cfg.Instance, "error", err)
+			return false
+		}
+		return true
+	}
+	return time.Since(latest.StartedAt) >= s.cfg.Interval
+}
+
+// Sync enumerates the configured groups once and returns the stored report.
+// Projects are matched to stored ones by numeric ID or full path; new ones
+// are registered under their numeric ID. Projects previously seen in a group
+// that no group contains any more are marked absent; projects last seen in a
+// group that cannot be listed are left untouched.
+func (s *Syncer) Sync(ctx context.Context, trigger string) (*models.GroupSyncRun, error) {
+	run := &models.GroupSyncRun{
+		Instance:     s.cfg.Instance,
+		Groups:       s.cfg.Groups,
+		Trigger:      trigger,
+		Created:      []string{},
+		MarkedAbsent: []string{},
+		Errors:       []models.GroupSyncError{},
+	}
+	if err := s.runs.StartRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	err := s.sync(ctx, run)
+	switch {
+	case err != nil:
+		run.Status = models.SyncFailed
+		run.Errors = append(run.Errors, models.GroupSyncError{Error: err.Error()})
+	case len(run.Errors) == len(s.cfg.Groups) && len(s.cfg.Groups) > 0:
+		run.Status = models.SyncFailed
+	case len(run.Errors) > 0:
+		run.Status = models.SyncPartial
+	default:
+		run.Status = models.SyncSucceeded
+	}
+
+	if finishErr := s.runs.FinishRun(ctx, run); finishErr != nil {
+		return nil, finishErr
+	}
+
+	s.logger.Info("group sync finished",
+		"run_id", run.ID,
+		"instance", run.Instance,
+		"status", run.Status,
+		"projects_seen", run.ProjectsSeen,
+		"created", len(run.Created),
+		"marked_absent", len(run.MarkedAbsent),
+	)
+	return run, err
+}
+
+func (s *Syncer) sync(ctx context.Context, run *models.GroupSyncRun) error {
+	stored := make(map[string]bool)
+	for project, err := range s.projects.Stream(ctx, repository.ProjectFilter{Instance: s.cfg.Instance}) {
+		if err != nil {
+			return err
+		}
+		stored[project.ProjectID] = true
+	}
+
+	seen := make(map[string]bool)
+	listed := make(map[string][]string)
+	var discovered []*models.Project
+
+	for _, group := range s.cfg.Groups {
+		projects, err := s.gitlab.ListGroupProjects(ctx, group)
+		if err != nil {
+			s.logger.Warn("failed to list group projects", "instance", s.cfg.Instance, "group", group, "error", err)
+			run.Errors = append(run.Errors, models.GroupSyncError{Group: group, Error: err.Error()})
+			continue
+		}
+
+		keys := make([]string, 0, len(projects))
+		for _, p := range projects {
+			key := strconv.Itoa(p.ID)
+			if !stored[key] && stored[p.PathWithNamespace] {
+				key = p.PathWithNamespace
+			}
+			if !stored[key] && !seen[key] {
+				discovered = append(discovered, &models.Project{
+					Instance:        s.cfg.Instance,
+					ProjectID:       key,
+					Results:         map[string]bool{checks.ProjectPresent: true},
+					ProjectMetadata: models.NewProjectMetadata(&p),
+				})
+			}
+			seen[key] = true
+			keys = append(keys, key)
+		}
+		listed[group] = keys
+	}
+	run.ProjectsSeen = len(seen)
+
+	if len(discovered) > 0 {
+		results, err := s.projects.Import(ctx, discovered, repository.ImportOptions{
+			OnConflict: repository.ConflictSkip,
+			Partial:    true,
+		})
+		if err != nil {
+			return err
+		}
+		for i, result := range results {
+			if result.Outcome != repository.ImportCreated {
+				continue
+			}
+			run.Created = append(run.Created, discovered[i].ProjectID)
+			if s.rescans != nil {
+				s.rescans.Enqueue(discovered[i].Key(), nil)
+			}
+		}
+	}
+
+	// Projects last seen in a group that could not be listed may still be
+	// there, so they are kept too
+	keep := maps.Clone(seen)
+	for _, group := range s.cfg.Groups {
+		if _, ok := listed[group]; ok {
+			continue
+		}
+		members, err := s.runs.Members(ctx, s.cfg.Instance, group)
+		if err != nil {
+			return err
+		}
+		for _, id := range members {
+			keep[id] = true
+		}
+	}
+
+	for _, group := range s.cfg.Groups {
+		keys, ok := listed[group]
+		if !ok {
+			continue
+		}
+
+		previous, err := s.runs.Members(ctx, s.cfg.Instance, group)
+		if err != nil {
+			return err
+		}
+		for _, id := range previous {
+			if keep[id] {
+				continue
+			}
+			keep[id] = true // Marked once however many groups it left
+
+			marked, err := s.markAbsent(ctx, id)
+			if err != nil {
+				return err
+			}
+			if marked {
+				run.MarkedAbsent = append(run.MarkedAbsent, id)
+			}
+		}
+
+		if err := s.runs.SetMembers(ctx, s.cfg.Instance, group, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markAbsent clears the project's presence check, reporting whether it was
+// set. Projects since deleted from the inventory are skipped.
+func (s *Syncer) markAbsent(ctx context.Context, projectID string) (bool, error) {
+	project, err := s.projects.GetByID(ctx, models.NewProjectKey(s.cfg.Instance, projectID))
+	if err != nil {
+		if err.Error() == "project not found" {
+			return false, nil
+		}
+		return false, err
+	}
+	if !project.Result(checks.ProjectPresent) {
+		return false, nil
+	}
+
+	project.SetCheck(checks.ProjectPresent, false)
+	if err := s.projects.Update(ctx, project); err != nil {
+		return false, err
+	}
+	return true, nil
+}
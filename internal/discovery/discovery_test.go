@@ -0,0 +1,152 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository/repotest"
+)
+
+// fakeGroups lists canned projects per group; groups it has no entry for fail
+type fakeGroups map[string][]gitlab.Project
+
+func (f fakeGroups) ListGroupProjects(ctx context.Context, group string) ([]gitlab.Project, error) {
+	projects, ok := f[group]
+	if !ok {
+		return nil, errors.New("gitlab unavailable")
+	}
+	return projects, nil
+}
+
+type recordingQueue struct {
+	enqueued []string
+}
+
+func (q *recordingQueue) Enqueue(key models.ProjectKey, checks []string) bool {
+	q.enqueued = append(q.enqueued, key.ProjectID)
+	return true
+}
+
+func TestSyncer_Sync(t *testing.T) {
+	ctx := context.Background()
+	projects := repotest.NewProjectRepository()
+	runs := repotest.NewSyncRepository()
+	queue := &recordingQueue{}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Registered by hand under its path
+	if err := projects.Create(ctx, &models.Project{ProjectID: "platform/api", Results: map[string]bool{"project_present": true}}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	groups := fakeGroups{
+		"platform": {
+			{ID: 1, PathWithNamespace: "platform/api"},
+			{ID: 2, PathWithNamespace: "platform/web"},
+		},
+		"data": {
+			{ID: 3, PathWithNamespace: "data/etl"},
+			{ID: 2, PathWithNamespace: "platform/web"}, // Shared with a group
+		},
+	}
+	syncer := New(groups, projects, runs, queue, Config{Groups: []string{"platform", "data"}}, logger)
+
+	run, err := syncer.Sync(ctx, models.SyncTriggerManual)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if run.Status != models.SyncSucceeded || run.ProjectsSeen != 3 {
+		t.Errorf("run = %s with %d projects, want succeeded with 3", run.Status, run.ProjectsSeen)
+	}
+	if !slices.Equal(run.Created, []string{"2", "3"}) {
+		t.Errorf("Created = %v, want [2 3]", run.Created)
+	}
+	if !slices.Equal(queue.enqueued, []string{"2", "3"}) {
+		t.Errorf("enqueued = %v, want [2 3]", queue.enqueued)
+	}
+
+	// platform/web left both groups; data cannot be listed
+	groups["platform"] = groups["platform"][:1]
+	delete(groups, "data")
+
+	run, err = syncer.Sync(ctx, models.SyncTriggerSchedule)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if run.Status != models.SyncPartial || len(run.Errors) != 1 || run.Errors[0].Group != "data" {
+		t.Errorf("run = %s with errors %v, want partial with a data error", run.Status, run.Errors)
+	}
+	// Still a member of data as far as the last successful listing knows
+	if len(run.MarkedAbsent) != 0 {
+		t.Errorf("MarkedAbsent = %v, want none while data is unlisted", run.MarkedAbsent)
+	}
+
+	groups["data"] = []gitlab.Project{{ID: 3, PathWithNamespace: "data/etl"}}
+	run, err = syncer.Sync(ctx, models.SyncTriggerSchedule)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if !slices.Equal(run.MarkedAbsent, []string{"2"}) || len(run.Created) != 0 {
+		t.Errorf("run created %v and marked %v absent, want none and [2]", run.Created, run.MarkedAbsent)
+	}
+
+	web, err := projects.GetByID(ctx, models.NewProjectKey("", "2"))
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if web.Result("project_present") {
+		t.Error("project 2 is still present")
+	}
+
+	if latest, err := runs.LatestRun(ctx, models.DefaultInstance); err != nil || latest.ID != run.ID || latest.FinishedAt == nil {
+		t.Errorf("LatestRun() = %+v, %v, want finished run %d", latest, err, run.ID)
+	}
+}
+
+func TestSyncer_Sync_AllGroupsFail(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	syncer := New(fakeGroups{}, repotest.NewProjectRepository(), repotest.NewSyncRepository(), nil, Config{Groups: []string{"platform"}}, logger)
+
+	run, err := syncer.Sync(context.Background(), models.SyncTriggerManual)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if run.Status != models.SyncFailed {
+		t.Errorf("Status = %s, want failed", run.Status)
+	}
+}
+
+func TestSyncer_Sync_Instance(t *testing.T) {
+	ctx := context.Background()
+	projects := repotest.NewProjectRepository()
+	runs := repotest.NewSyncRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// The same ID on another instance is a different project
+	if err := projects.Create(ctx, &models.Project{ProjectID: "1", Results: map[string]bool{"project_present": true}}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	groups := fakeGroups{"platform": {{ID: 1, PathWithNamespace: "platform/api"}}}
+	syncer := New(groups, projects, runs, nil, Config{Instance: "onprem", Groups: []string{"platform"}}, logger)
+
+	run, err := syncer.Sync(ctx, models.SyncTriggerManual)
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if run.Instance != "onprem" || !slices.Equal(run.Created, []string{"1"}) {
+		t.Errorf("run on %s created %v, want onprem and [1]", run.Instance, run.Created)
+	}
+	if _, err := projects.GetByID(ctx, models.NewProjectKey("onprem", "1")); err != nil {
+		t.Errorf("GetByID() error = %v", err)
+	}
+	if _, err := runs.LatestRun(ctx, models.DefaultInstance); err == nil {
+		t.Error("LatestRun() for the default instance found the onprem run")
+	}
+}
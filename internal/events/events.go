@@ -0,0 +1,93 @@
+// Package events derives change events from successive versions of a
+// project. The project repository stores them in the outbox alongside the
+// change itself.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// Created returns the event for a new project
+func Created(p *models.Project) []models.Event {
+	return []models.Event{newEvent(models.EventProjectCreated, p, nil)}
+}
+
+// Deleted returns the event for a removed project, carrying its last state
+func Deleted(p *models.Project) []models.Event {
+	return []models.Event{newEvent(models.EventProjectDeleted, p, nil)}
+}
+
+// Diff returns the events implied by a project changing from old to new.
+// Updates that change no check result produce no events.
+func Diff(old, new *models.Project) []models.Event {
+	changes, regressions := compare(old, new)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	events := []models.Event{newEvent(models.EventProjectUpdated, new, changes)}
+	if new.Ready() && !old.Ready() {
+		events = append(events, newEvent(models.EventProjectReady, new, changes))
+	}
+	if len(regressions) > 0 {
+		events = append(events, newEvent(models.EventProjectRegressed, new, regressions))
+	}
+	for _, change := range changes {
+		events = append(events, newEvent(models.EventCheckChanged, new, []models.CheckChange{change}))
+	}
+	return events
+}
+
+// Scanned returns the events for a project rescanned from GitLab: those of
+// Diff followed by scan.completed, which is sent even when nothing changed
+func Scanned(old, new *models.Project) []models.Event {
+	changes, _ := compare(old, new)
+	return append(Diff(old, new), newEvent(models.EventScanCompleted, new, changes))
+}
+
+// compare returns the checks whose results differ between old and new, and
+// the subset that went from passing to failing
+func compare(old, new *models.Project) (changes, regressions []models.CheckChange) {
+	previous := old.Checks()
+	for i, check := range new.Checks() {
+		if check.Passed == previous[i].Passed {
+			continue
+		}
+		change := models.CheckChange{
+			Name:     check.Name,
+			Category: check.Category,
+			Passed:   check.Passed,
+			Previous: previous[i].Passed,
+		}
+		changes = append(changes, change)
+		if !check.Passed {
+			regressions = append(regressions, change)
+		}
+	}
+	return changes, regressions
+}
+
+func newEvent(eventType string, p *models.Project, changes []models.CheckChange) models.Event {
+	return models.Event{
+		ID:         newEventID(),
+		Type:       eventType,
+		Instance:   p.Key().Instance,
+		ProjectID:  p.ProjectID,
+		OccurredAt: time.Now().UTC(),
+		Data: models.EventData{
+			Project: p.Clone(),
+			Changes: changes,
+		},
+	}
+}
+
+// newEventID returns a random ID receivers can use to deduplicate deliveries
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+// readyProject returns a project passing every check
+func readyProject(id string) *models.Project {
+	p := &models.Project{ProjectID: id}
+	for _, id := range checks.IDs() {
+		p.SetCheck(id, true)
+	}
+	return p
+}
+
+func eventTypes(events []models.Event) []string {
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestDiff(t *testing.T) {
+	notReady := readyProject("1")
+	notReady.SetCheck("codeowners_exists", false)
+
+	regressedTwice := readyProject("1")
+	regressedTwice.SetCheck("codeowners_exists", false)
+	regressedTwice.SetCheck("force_push_disabled", false)
+
+	mixed := readyProject("1")
+	mixed.SetCheck("force_push_disabled", false)
+
+	tests := []struct {
+		name      string
+		old, new  *models.Project
+		wantTypes []string
+	}{
+		{
+			name:      "no change",
+			old:       readyProject("1"),
+			new:       readyProject("1"),
+			wantTypes: nil,
+		},
+		{
+			name:      "becomes ready",
+			old:       notReady,
+			new:       readyProject("1"),
+			wantTypes: []string{models.EventProjectUpdated, models.EventProjectReady, models.EventCheckChanged},
+		},
+		{
+			name:      "regresses",
+			old:       readyProject("1"),
+			new:       regressedTwice,
+			wantTypes: []string{models.EventProjectUpdated, models.EventProjectRegressed, models.EventCheckChanged, models.EventCheckChanged},
+		},
+		{
+			name:      "fixes one check and breaks another",
+			old:       notReady,
+			new:       mixed,
+			wantTypes: []string{models.EventProjectUpdated, models.EventProjectRegressed, models.EventCheckChanged, models.EventCheckChanged},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.old, tt.new)
+			if types := eventTypes(got); !slices.Equal(types, tt.wantTypes) {
+				t.Errorf("Diff() types = %v, want %v", types, tt.wantTypes)
+			}
+			for _, event := range got {
+				if event.ID == "" || event.ProjectID != "1" || event.Data.Project == nil {
+					t.Errorf("Diff() event = %+v, want ID, project ID and project", event)
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_RegressedChanges(t *testing.T) {
+	old := readyProject("1")
+	old.SetCheck("codeowners_exists", false)
+	new := readyProject("1")
+	new.SetCheck("force_push_disabled", false)
+
+	for _, event := range Diff(old, new) {
+		if event.Type != models.EventProjectRegressed {
+			continue
+		}
+		want := []models.CheckChange{{
+			Name:     "force_push_disabled",
+			Category: checks.CategoryBranchProtection,
+			Passed:   false,
+			Previous: true,
+		}}
+		if !slices.Equal(event.Data.Changes, want) {
+			t.Errorf("regressed changes = %+v, want %+v", event.Data.Changes, want)
+		}
+		return
+	}
+	t.Fatal("Diff() did not report a regression")
+}
+
+func TestScanned(t *testing.T) {
+	unchanged := Scanned(readyProject("1"), readyProject("1"))
+	if types := eventTypes(unchanged); !slices.Equal(types, []string{models.EventScanCompleted}) {
+		t.Errorf("Scanned() without changes types = %v, want only %s", types, models.EventScanCompleted)
+	}
+
+	regressed := readyProject("1")
+	regressed.SetCheck("codeowners_exists", false)
+
+	got := Scanned(readyProject("1"), regressed)
+	want := []string{models.EventProjectUpdated, models.EventProjectRegressed, models.EventCheckChanged, models.EventScanCompleted}
+	if types := eventTypes(got); !slices.Equal(types, want) {
+		t.Fatalf("Scanned() types = %v, want %v", types, want)
+	}
+	if changes := got[len(got)-1].Data.Changes; len(changes) != 1 || changes[0].Name != "codeowners_exists" {
+		t.Errorf("scan.completed changes = %+v, want codeowners_exists", changes)
+	}
+}
@@ -0,0 +1,91 @@
+package gitlab
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Variable is a CI/CD variable set in project or group settings
+type Variable struct {
+	Key              string `json:"key"`
+	Value            string `json:"value"`
+	VariableType     string `json:"variable_type"` // env_var or file
+	Protected        bool   `json:"protected"`
+	Masked           bool   `json:"masked"`
+	EnvironmentScope string `json:"environment_scope"` // * for every environment
+}
+
+// ListProjectVariables returns the CI/CD variables set on a project. GitLab
+// only shows them to maintainers.
+func (c *Client) ListProjectVariables(ctx context.Context, projectID string) ([]Variable, error) {
+	return getAll[Variable](ctx, c, projectPath(projectID)+"/variables", nil)
+}
+
+// ListGroupVariables returns the CI/CD variables set on a group, given by
+// numeric ID or full path, not including those of its parent groups
+func (c *Client) ListGroupVariables(ctx context.Context, group string) ([]Variable, error) {
+	return getAll[Variable](ctx, c, "/groups/"+url.PathEscape(group)+"/variables", nil)
+}
+
+// GetCITemplate returns a CI/CD template shipped with GitLab, named as in an
+// include such as Security/SAST.gitlab-ci.yml
+func (c *Client) GetCITemplate(ctx context.Context, name string) ([]byte, error) {
+	var template struct {
+		Content string `json:"content"`
+	}
+	key := strings.TrimSuffix(name, ".gitlab-ci.yml")
+	if err := c.get(ctx, "/templates/gitlab_ci_ymls/"+url.PathEscape(key), nil, &template); err != nil {
+		return nil, err
+	}
+	return []byte(template.Content), nil
+}
+
+// Pipeline is a CI/CD pipeline run
+type Pipeline struct {
+	ID     int    `json:"id"`
+	Ref    string `json:"ref"`
+	SHA    string `json:"sha"`
+	Status string `json:"status"` // e.g. running, success, failed or canceled
+	WebURL string `json:"web_url"`
+}
+
+// Job is a job of a pipeline and the artifacts it kept
+type Job struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Stage     string        `json:"stage"`
+	Status    string        `json:"status"`
+	Artifacts []JobArtifact `json:"artifacts"`
+}
+
+// JobArtifact is a file a job uploaded. Reports have the report type, such
+// as sast or dependency_scanning, as their file type.
+type JobArtifact struct {
+	FileType string `json:"file_type"`
+	Filename string `json:"filename"`
+	Size     int    `json:"size"`
+}
+
+// GetLatestPipeline returns the most recent finished pipeline for a ref,
+// or ErrNotFound if none has finished
+func (c *Client) GetLatestPipeline(ctx context.Context, projectID, ref string) (*Pipeline, error) {
+	query := url.Values{}
+	query.Set("ref", ref)
+	query.Set("scope", "finished")
+	query.Set("per_page", "1")
+	var pipelines []Pipeline
+	if err := c.get(ctx, projectPath(projectID)+"/pipelines", query, &pipelines); err != nil {
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, ErrNotFound
+	}
+	return &pipelines[0], nil
+}
+
+// ListPipelineJobs returns the jobs of a pipeline, without those retried
+func (c *Client) ListPipelineJobs(ctx context.Context, projectID string, pipelineID int) ([]Job, error) {
+	return getAll[Job](ctx, c, projectPath(projectID)+"/pipelines/"+strconv.Itoa(pipelineID)+"/jobs", nil)
+}
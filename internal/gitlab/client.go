@@ -0,0 +1,159 @@
+// Package gitlab is a minimal client for the parts of the GitLab REST API
+// the readiness scanner needs.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when GitLab answers 404 for a resource
+var ErrNotFound = errors.New("gitlab: not found")
+
+type Config struct {
+	BaseURL string // e.g. https://gitlab.com
+	Token   string // Personal, group or project access token with read_api scope
+	Timeout time.Duration
+
+	// RateLimit caps requests per second, allowing bursts of Burst
+	// requests. Zero leaves requests unthrottled.
+	RateLimit float64
+	Burst     int
+}
+
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	limiter    *limiter // Nil when unthrottled
+}
+
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("gitlab base URL is required")
+	}
+	if _, err := url.Parse(cfg.BaseURL); err != nil {
+		return nil, fmt.Errorf("invalid gitlab base URL: %w", err)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	client := &Client{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/") + "/api/v4",
+		token:      cfg.Token,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+	if cfg.RateLimit > 0 {
+		client.limiter = newLimiter(cfg.RateLimit, cfg.Burst)
+	}
+	return client, nil
+}
+
+// APIError is returned for non-2xx responses other than 404
+type APIError struct {
+	StatusCode int
+	Path       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gitlab: %s returned %d: %s", e.Path, e.StatusCode, e.Message)
+}
+
+// get performs a GET against the API and decodes the JSON body into v
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	body, err := c.getRaw(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("gitlab: failed to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// getAll fetches every page of a list endpoint
+func getAll[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	const perPage = 100
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("per_page", strconv.Itoa(perPage))
+
+	var items []T
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+
+		var batch []T
+		if err := c.get(ctx, path, query, &batch); err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if len(batch) < perPage {
+			return items, nil
+		}
+	}
+}
+
+func (c *Client) getRaw(ctx context.Context, path string, query url.Values) ([]byte, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: failed to build request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("gitlab: GET %s: %w", path, err)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: GET %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: failed to read %s: %w", path, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Path:       path,
+			Message:    strings.TrimSpace(string(body)),
+		}
+	}
+
+	return body, nil
+}
+
+// projectPath returns the API path for a project given its numeric ID or
+// full namespace path
+func projectPath(projectID string) string {
+	return "/projects/" + url.PathEscape(projectID)
+}
@@ -0,0 +1,100 @@
+package gitlab
+
+import (
+	"cmp"
+	"strconv"
+)
+
+// Headers GitLab sends with webhook and system hook requests
+const (
+	HookTokenHeader = "X-Gitlab-Token" // The secret configured for the hook
+	HookEventHeader = "X-Gitlab-Event" // e.g. "Push Hook" or "System Hook"
+)
+
+// HookEvent is the subset of a project, group or system hook payload the
+// readiness service acts on. Project and group hooks identify the event by
+// object_kind, system hooks by event_name; push payloads share one shape
+// across both.
+type HookEvent struct {
+	ObjectKind string `json:"object_kind"`
+	EventName  string `json:"event_name"`
+
+	// Push and repository_update events
+	Ref               string       `json:"ref"`
+	ProjectID         int          `json:"project_id"`
+	Project           *HookProject `json:"project"`
+	Commits           []HookCommit `json:"commits"`
+	TotalCommitsCount int          `json:"total_commits_count"`
+	Changes           []HookChange `json:"changes"`
+
+	// Pipeline events
+	ObjectAttributes *HookPipeline `json:"object_attributes"`
+
+	// Project system hooks (project_create, project_update, ...)
+	Name                 string `json:"name"`
+	PathWithNamespace    string `json:"path_with_namespace"`
+	OldPathWithNamespace string `json:"old_path_with_namespace"`
+	ProjectVisibility    string `json:"project_visibility"`
+
+	// Project membership system hooks (user_add_to_team, ...), which name
+	// the project in their own field
+	ProjectPathWithNamespace string `json:"project_path_with_namespace"`
+}
+
+type HookProject struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+}
+
+// HookCommit lists the files a pushed commit touched
+type HookCommit struct {
+	Added    []string `json:"added"`
+	Modified []string `json:"modified"`
+	Removed  []string `json:"removed"`
+}
+
+// HookPipeline is the pipeline a pipeline event reports on
+type HookPipeline struct {
+	ID     int    `json:"id"`
+	Ref    string `json:"ref"`
+	Tag    bool   `json:"tag"`
+	Status string `json:"status"`
+}
+
+// HookChange is one ref updated by a repository_update event
+type HookChange struct {
+	Ref string `json:"ref"`
+}
+
+// Kind returns the event's type, whichever field carries it
+func (e *HookEvent) Kind() string {
+	if e.EventName != "" {
+		return e.EventName
+	}
+	return e.ObjectKind
+}
+
+// ProjectKeys returns the ways the event identifies its project: the numeric
+// ID, then the full path and, for renames and transfers, the previous path
+func (e *HookEvent) ProjectKeys() []string {
+	var keys []string
+	id, path := e.ProjectID, cmp.Or(e.PathWithNamespace, e.ProjectPathWithNamespace)
+	if e.Project != nil {
+		if id == 0 {
+			id = e.Project.ID
+		}
+		if path == "" {
+			path = e.Project.PathWithNamespace
+		}
+	}
+	if id != 0 {
+		keys = append(keys, strconv.Itoa(id))
+	}
+	for _, p := range []string{path, e.OldPathWithNamespace} {
+		if p != "" {
+			keys = append(keys, p)
+		}
+	}
+	return keys
+}
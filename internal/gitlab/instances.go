@@ -0,0 +1,29 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrUnknownInstance is returned for instance names missing from a registry
+var ErrUnknownInstance = errors.New("gitlab: unknown instance")
+
+// Instances holds a client for each GitLab instance projects are registered
+// against, by instance name
+type Instances map[string]*Client
+
+// Get returns the client for the named instance
+func (i Instances) Get(name string) (*Client, error) {
+	client, ok := i[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownInstance, name)
+	}
+	return client, nil
+}
+
+// Names returns the instance names in sorted order
+func (i Instances) Names() []string {
+	return slices.Sorted(maps.Keys(i))
+}
@@ -0,0 +1,262 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// Access levels of members and of protected branch rules
+const (
+	AccessNoOne      = 0
+	AccessGuest      = 10
+	AccessReporter   = 20
+	AccessDeveloper  = 30
+	AccessMaintainer = 40
+	AccessOwner      = 50
+	AccessAdmin      = 60
+)
+
+// RoleName returns the role GitLab names an access level
+func RoleName(level int) string {
+	switch {
+	case level >= AccessAdmin:
+		return "Admin"
+	case level >= AccessOwner:
+		return "Owner"
+	case level >= AccessMaintainer:
+		return "Maintainer"
+	case level >= AccessDeveloper:
+		return "Developer"
+	case level >= AccessReporter:
+		return "Reporter"
+	case level >= AccessGuest:
+		return "Guest"
+	default:
+		return "No access"
+	}
+}
+
+type Project struct {
+	ID                int           `json:"id"`
+	Name              string        `json:"name"`
+	PathWithNamespace string        `json:"path_with_namespace"`
+	DefaultBranch     string        `json:"default_branch"`
+	WebURL            string        `json:"web_url"`
+	Visibility        string        `json:"visibility"`
+	Archived          bool          `json:"archived"`
+	CIConfigPath      string        `json:"ci_config_path"` // Empty for .gitlab-ci.yml
+	Namespace         Namespace     `json:"namespace"`
+	SharedWithGroups  []SharedGroup `json:"shared_with_groups"`
+
+	OnlyAllowMergeIfPipelineSucceeds          bool `json:"only_allow_merge_if_pipeline_succeeds"`
+	AllowMergeOnSkippedPipeline               bool `json:"allow_merge_on_skipped_pipeline"`
+	OnlyAllowMergeIfAllDiscussionsAreResolved bool `json:"only_allow_merge_if_all_discussions_are_resolved"`
+}
+
+// SharedGroup is a group a project is shared with
+type SharedGroup struct {
+	GroupID          int    `json:"group_id"`
+	GroupFullPath    string `json:"group_full_path"`
+	GroupAccessLevel int    `json:"group_access_level"`
+}
+
+// Namespace is the group or user a project belongs to
+type Namespace struct {
+	ID       int    `json:"id"`
+	Kind     string `json:"kind"` // group or user
+	FullPath string `json:"full_path"`
+}
+
+// Member is a user with access to a project, directly or through a group
+type Member struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	State       string `json:"state"` // active, blocked, deactivated, ...
+	AccessLevel int    `json:"access_level"`
+	ExpiresAt   string `json:"expires_at"` // Date the membership ends, empty for none
+}
+
+// User is a GitLab account
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	State    string `json:"state"`
+	Bot      bool   `json:"bot"`
+
+	// External is only shown to administrators, and nil when hidden
+	External *bool `json:"external"`
+}
+
+type AccessLevel struct {
+	AccessLevel int  `json:"access_level"`
+	UserID      *int `json:"user_id"`
+	GroupID     *int `json:"group_id"`
+}
+
+type ProtectedBranch struct {
+	Name                      string        `json:"name"`
+	PushAccessLevels          []AccessLevel `json:"push_access_levels"`
+	MergeAccessLevels         []AccessLevel `json:"merge_access_levels"`
+	AllowForcePush            bool          `json:"allow_force_push"`
+	CodeOwnerApprovalRequired bool          `json:"code_owner_approval_required"`
+}
+
+// ProtectedTag is a protected tag rule, a tag name or wildcard pattern
+type ProtectedTag struct {
+	Name               string        `json:"name"`
+	CreateAccessLevels []AccessLevel `json:"create_access_levels"`
+}
+
+type PushRule struct {
+	CommitMessageRegex         string `json:"commit_message_regex"`
+	CommitMessageNegativeRegex string `json:"commit_message_negative_regex"`
+	RejectUnsignedCommits      bool   `json:"reject_unsigned_commits"`
+}
+
+// DeployKey is an SSH key giving read or write access to a project's
+// repository
+type DeployKey struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	CanPush   bool   `json:"can_push"`
+	ExpiresAt string `json:"expires_at"` // Empty for a key that never expires
+}
+
+// AccessToken is a project access token
+type AccessToken struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	Active    bool     `json:"active"`
+	Revoked   bool     `json:"revoked"`
+	ExpiresAt string   `json:"expires_at"` // Empty for a token that never expires
+}
+
+// ApprovalConfig is the project-level merge request approval settings
+type ApprovalConfig struct {
+	ApprovalsBeforeMerge                      int  `json:"approvals_before_merge"`
+	ResetApprovalsOnPush                      bool `json:"reset_approvals_on_push"`
+	MergeRequestsAuthorApproval               bool `json:"merge_requests_author_approval"`
+	MergeRequestsDisableCommittersApproval    bool `json:"merge_requests_disable_committers_approval"`
+	DisableOverridingApproversPerMergeRequest bool `json:"disable_overriding_approvers_per_merge_request"`
+}
+
+type ApprovalRule struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	RuleType          string `json:"rule_type"`
+	ApprovalsRequired int    `json:"approvals_required"`
+}
+
+// GetProject fetches a project by numeric ID or full path
+func (c *Client) GetProject(ctx context.Context, projectID string) (*Project, error) {
+	var project Project
+	if err := c.get(ctx, projectPath(projectID), nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListGroupProjects returns every project in a group, given by numeric ID or
+// full path, including those in its subgroups
+func (c *Client) ListGroupProjects(ctx context.Context, group string) ([]Project, error) {
+	query := url.Values{}
+	query.Set("include_subgroups", "true")
+	query.Set("simple", "true")
+	return getAll[Project](ctx, c, "/groups/"+url.PathEscape(group)+"/projects", query)
+}
+
+// ListAllProjectMembers returns the project's members, including those
+// inheriting access from its groups and the groups it is shared with
+func (c *Client) ListAllProjectMembers(ctx context.Context, projectID string) ([]Member, error) {
+	return getAll[Member](ctx, c, projectPath(projectID)+"/members/all", nil)
+}
+
+// ListProjectMembers returns the project's direct members, without those
+// inheriting access from groups
+func (c *Client) ListProjectMembers(ctx context.Context, projectID string) ([]Member, error) {
+	return getAll[Member](ctx, c, projectPath(projectID)+"/members", nil)
+}
+
+// GetUser returns a user by numeric ID
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	var user User
+	if err := c.get(ctx, "/users/"+strconv.Itoa(id), nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetRawFile returns a repository file's contents at ref
+func (c *Client) GetRawFile(ctx context.Context, projectID, filePath, ref string) ([]byte, error) {
+	query := url.Values{}
+	if ref != "" {
+		query.Set("ref", ref)
+	}
+	return c.getRaw(ctx, projectPath(projectID)+"/repository/files/"+url.PathEscape(filePath)+"/raw", query)
+}
+
+// GetProtectedBranch returns the protection settings for a branch name or
+// wildcard pattern, or ErrNotFound if it is not protected
+func (c *Client) GetProtectedBranch(ctx context.Context, projectID, branch string) (*ProtectedBranch, error) {
+	var pb ProtectedBranch
+	if err := c.get(ctx, projectPath(projectID)+"/protected_branches/"+url.PathEscape(branch), nil, &pb); err != nil {
+		return nil, err
+	}
+	return &pb, nil
+}
+
+// ListProtectedBranches returns every protected branch rule of the project,
+// names and wildcard patterns alike
+func (c *Client) ListProtectedBranches(ctx context.Context, projectID string) ([]ProtectedBranch, error) {
+	return getAll[ProtectedBranch](ctx, c, projectPath(projectID)+"/protected_branches", nil)
+}
+
+// ListProtectedTags returns every protected tag rule of the project
+func (c *Client) ListProtectedTags(ctx context.Context, projectID string) ([]ProtectedTag, error) {
+	return getAll[ProtectedTag](ctx, c, projectPath(projectID)+"/protected_tags", nil)
+}
+
+// ListDeployKeys returns the deploy keys enabled for the project
+func (c *Client) ListDeployKeys(ctx context.Context, projectID string) ([]DeployKey, error) {
+	return getAll[DeployKey](ctx, c, projectPath(projectID)+"/deploy_keys", nil)
+}
+
+// ListAccessTokens returns the project's access tokens. GitLab only shows
+// them to maintainers.
+func (c *Client) ListAccessTokens(ctx context.Context, projectID string) ([]AccessToken, error) {
+	return getAll[AccessToken](ctx, c, projectPath(projectID)+"/access_tokens", nil)
+}
+
+// GetPushRule returns the project's push rules. Projects without push rules
+// (or on tiers without the feature) yield a nil rule and no error.
+func (c *Client) GetPushRule(ctx context.Context, projectID string) (*PushRule, error) {
+	var rule *PushRule
+	err := c.get(ctx, projectPath(projectID)+"/push_rule", nil, &rule)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (c *Client) GetApprovalConfig(ctx context.Context, projectID string) (*ApprovalConfig, error) {
+	var cfg ApprovalConfig
+	if err := c.get(ctx, projectPath(projectID)+"/approvals", nil, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Client) ListApprovalRules(ctx context.Context, projectID string) ([]ApprovalRule, error) {
+	var rules []ApprovalRule
+	if err := c.get(ctx, projectPath(projectID)+"/approval_rules", nil, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
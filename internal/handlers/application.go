@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/user/go-backend/internal/bulk"
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+type ApplicationHandler struct {
+	projects repository.ProjectRepository
+	apps     repository.ApplicationRepository
+	logger   *slog.Logger
+}
+
+// NewApplicationHandler creates the handler for the registered application
+// list and the reports on captured APP_NAME and MOAB_ID values
+func NewApplicationHandler(projects repository.ProjectRepository, apps repository.ApplicationRepository, logger *slog.Logger) *ApplicationHandler {
+	return &ApplicationHandler{
+		projects: projects,
+		apps:     apps,
+		logger:   logger,
+	}
+}
+
+// ListApplications handles GET /api/v1/applications
+//
+//	@Summary		List registered applications
+//	@Description	List the applications APP_NAME values are expected to name, ordered by name
+//	@Tags			applications
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse{data=[]models.Application}	"Registered applications"
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/applications [get]
+func (h *ApplicationHandler) ListApplications(w http.ResponseWriter, r *http.Request) {
+	apps, err := h.apps.List(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list applications", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve applications")
+		return
+	}
+	if apps == nil {
+		apps = []*models.Application{}
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Applications retrieved successfully", apps)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// ReplaceApplications handles PUT /api/v1/applications
+//
+//	@Summary		Replace registered applications
+//	@Description	Upload the registered application list as CSV with a name column and an optional description column. The upload replaces the whole list; any invalid row rejects the file.
+//	@Tags			applications
+//	@Accept			text/csv
+//	@Produce		json
+//	@Param			file	body		string	true	"CSV file"
+//	@Success		200		{object}	models.SuccessResponse{data=models.ApplicationImportSummary}	"Applications replaced"
+//	@Failure		400		{object}	models.ErrorResponse	"Bad request"
+//	@Failure		413		{object}	models.ErrorResponse	"File too large"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/applications [put]
+func (h *ApplicationHandler) ReplaceApplications(w http.ResponseWriter, r *http.Request) {
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	apps, err := bulk.ReadApplications(body, maxImportRows)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			respondWithError(w, h.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("Application file exceeds %d bytes", maxImportBytes))
+		case errors.Is(err, bulk.ErrTooManyRows):
+			respondWithError(w, h.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("Application file exceeds %d rows", maxImportRows))
+		default:
+			respondWithError(w, h.logger, http.StatusBadRequest, "Invalid application file: "+err.Error())
+		}
+		return
+	}
+
+	if err := h.apps.Replace(r.Context(), apps); err != nil {
+		h.logger.Error("failed to replace applications", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to replace applications")
+		return
+	}
+
+	h.logger.Info("applications replaced", "applications", len(apps))
+	response := models.NewSuccessResponse(http.StatusOK, "Applications replaced successfully", &models.ApplicationImportSummary{Applications: len(apps)})
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// DuplicateMoabIDs handles GET /api/v1/reports/duplicate-moab-ids
+//
+//	@Summary		Duplicate MOAB IDs
+//	@Description	List every MOAB ID captured from more than one project's .gitlab-ci.yml at its last scan, with the projects setting it
+//	@Tags			applications
+//	@Accept			json
+//	@Produce		json
+//	@Param			instance	query		string	false	"Only projects on this GitLab instance"
+//	@Success		200			{object}	models.SuccessResponse{data=[]models.DuplicateMoabID}	"Duplicate MOAB IDs"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/reports/duplicate-moab-ids [get]
+func (h *ApplicationHandler) DuplicateMoabIDs(w http.ResponseWriter, r *http.Request) {
+	byID := make(map[string][]models.ProjectRef)
+	filter := repository.ProjectFilter{Instance: r.URL.Query().Get("instance")}
+	for p, err := range h.projects.Stream(r.Context(), filter) {
+		if err != nil {
+			h.logger.Error("failed to stream projects", "error", err)
+			respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve projects")
+			return
+		}
+		if id := p.Evaluation(checks.MoabIDSet).Captured; id != "" {
+			byID[id] = append(byID[id], models.NewProjectRef(p))
+		}
+	}
+
+	duplicates := []models.DuplicateMoabID{}
+	for id, projects := range byID {
+		if len(projects) > 1 {
+			duplicates = append(duplicates, models.DuplicateMoabID{MoabID: id, Projects: projects})
+		}
+	}
+	slices.SortFunc(duplicates, func(a, b models.DuplicateMoabID) int { return cmp.Compare(a.MoabID, b.MoabID) })
+
+	response := models.NewSuccessResponse(http.StatusOK, "Duplicate MOAB IDs retrieved successfully", duplicates)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// UnregisteredAppNames handles GET /api/v1/reports/unregistered-app-names
+//
+//	@Summary		Unregistered APP_NAME values
+//	@Description	List projects whose APP_NAME, as captured at their last scan, names no registered application. Projects without an APP_NAME are left to the app_name_set check.
+//	@Tags			applications
+//	@Accept			json
+//	@Produce		json
+//	@Param			instance	query		string	false	"Only projects on this GitLab instance"
+//	@Success		200			{object}	models.SuccessResponse{data=[]models.UnregisteredAppName}	"Projects with unregistered APP_NAME values"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/reports/unregistered-app-names [get]
+func (h *ApplicationHandler) UnregisteredAppNames(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apps, err := h.apps.List(ctx)
+	if err != nil {
+		h.logger.Error("failed to list applications", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve applications")
+		return
+	}
+	registered := make(map[string]bool, len(apps))
+	for _, app := range apps {
+		registered[app.Name] = true
+	}
+
+	unregistered := []models.UnregisteredAppName{}
+	filter := repository.ProjectFilter{Instance: r.URL.Query().Get("instance")}
+	for p, err := range h.projects.Stream(ctx, filter) {
+		if err != nil {
+			h.logger.Error("failed to stream projects", "error", err)
+			respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve projects")
+			return
+		}
+		if name := p.Evaluation(checks.AppNameSet).Captured; name != "" && !registered[name] {
+			unregistered = append(unregistered, models.UnregisteredAppName{ProjectRef: models.NewProjectRef(p), AppName: name})
+		}
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Unregistered APP_NAME values retrieved successfully", unregistered)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
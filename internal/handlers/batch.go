@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+const (
+	maxBatchOperations = 1000
+	maxBatchBytes      = 10 << 20
+)
+
+// batchStatus is the status a standalone request would have received
+var batchStatus = map[repository.BatchOutcome]int{
+	repository.BatchCreated:  http.StatusCreated,
+	repository.BatchUpdated:  http.StatusOK,
+	repository.BatchDeleted:  http.StatusNoContent,
+	repository.BatchConflict: http.StatusConflict,
+	repository.BatchNotFound: http.StatusNotFound,
+}
+
+var batchErrors = map[repository.BatchOutcome]string{
+	repository.BatchConflict: "Project already exists",
+	repository.BatchNotFound: "project_id not found",
+}
+
+type BatchHandler struct {
+	repo   repository.ProjectRepository
+	logger *slog.Logger
+}
+
+func NewBatchHandler(repo repository.ProjectRepository, logger *slog.Logger) *BatchHandler {
+	return &BatchHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Batch handles POST /api/v1/gitlab/projects:batch
+// It applies many create, update, upsert and delete operations in one request
+//
+//	@Summary		Batch write projects
+//	@Description	Apply up to 1000 operations in one transaction. In atomic mode every operation succeeds or none are applied; in best_effort mode each succeeds or fails on its own. Each result carries the status the operation would get as a standalone request; operations rolled back because of another failure get 424. Operations that name no instance are on the default instance. A project may appear only once per batch.
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			batch	body		models.BatchRequest	true	"Operations"
+//	@Success		200		{object}	models.SuccessResponse{data=models.BatchResult}	"Per-operation results"
+//	@Failure		400		{object}	models.ErrorResponse	"Bad request"
+//	@Failure		413		{object}	models.ErrorResponse	"Too many operations"
+//	@Failure		422		{object}	models.ErrorResponse{details=models.BatchResult}	"Atomic batch rolled back"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects:batch [post]
+func (h *BatchHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req models.BatchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, h.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBatchBytes))
+			return
+		}
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	switch req.Mode {
+	case "":
+		req.Mode = models.BatchAtomic
+	case models.BatchAtomic, models.BatchBestEffort:
+	default:
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid mode: must be atomic or best_effort")
+		return
+	}
+
+	if len(req.Operations) == 0 {
+		respondWithError(w, h.logger, http.StatusBadRequest, "At least one operation is required")
+		return
+	}
+	if len(req.Operations) > maxBatchOperations {
+		respondWithError(w, h.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("A batch may contain at most %d operations", maxBatchOperations))
+		return
+	}
+
+	atomic := req.Mode == models.BatchAtomic
+	result := &models.BatchResult{
+		Mode:    req.Mode,
+		Results: make([]models.BatchItemResult, len(req.Operations)),
+	}
+
+	// valid holds the request index of each operation passed to the repository
+	var valid []int
+	var ops []models.BatchOperation
+	seen := make(map[models.ProjectKey]int)
+	for i, op := range req.Operations {
+		item := &result.Results[i]
+		item.Index = i
+		item.Op = op.Op
+
+		if err := normalizeBatchOperation(&op, chi.URLParam(r, "instance")); err != nil {
+			item.Instance, item.ProjectID = op.Instance, op.ProjectID
+			item.Status = http.StatusBadRequest
+			item.Error = err.Error()
+			continue
+		}
+		item.Instance, item.ProjectID = op.Instance, op.ProjectID
+
+		key := models.NewProjectKey(op.Instance, op.ProjectID)
+		if first, ok := seen[key]; ok {
+			item.Status = http.StatusBadRequest
+			item.Error = fmt.Sprintf("project_id already used by operation %d", first)
+			continue
+		}
+		seen[key] = i
+
+		valid = append(valid, i)
+		ops = append(ops, op)
+	}
+
+	invalid := len(req.Operations) - len(valid)
+	if atomic && invalid > 0 {
+		h.rejectBatch(w, result)
+		return
+	}
+
+	committed := true
+	if len(ops) > 0 {
+		outcomes, ok, err := h.repo.Batch(ctx, ops, atomic)
+		if err != nil {
+			h.logger.Error("failed to apply batch", "error", err, "operations", len(ops))
+			respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to apply batch")
+			return
+		}
+		committed = ok
+
+		for j, outcome := range outcomes {
+			item := &result.Results[valid[j]]
+			item.Status = batchStatus[outcome]
+			item.Error = batchErrors[outcome]
+		}
+	}
+
+	if !committed {
+		h.rejectBatch(w, result)
+		return
+	}
+
+	result.Committed = true
+	for _, item := range result.Results {
+		if item.Status >= 400 {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+
+	h.logger.Info("batch applied", "mode", req.Mode, "succeeded", result.Succeeded, "failed", result.Failed)
+	response := models.NewSuccessResponse(http.StatusOK, "Batch applied", result)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// rejectBatch reports an atomic batch that wrote nothing. Operations that
+// would have succeeded are marked 424 Failed Dependency.
+func (h *BatchHandler) rejectBatch(w http.ResponseWriter, result *models.BatchResult) {
+	for i := range result.Results {
+		item := &result.Results[i]
+		if item.Status < 400 {
+			item.Status = http.StatusFailedDependency
+			item.Error = "Not applied because another operation failed"
+		} else {
+			result.Failed++
+		}
+	}
+
+	message := fmt.Sprintf("%d operation(s) failed; nothing was applied", result.Failed)
+	response := models.NewErrorResponseWithDetails(http.StatusUnprocessableEntity, message, result)
+	respondWithJSON(w, h.logger, http.StatusUnprocessableEntity, response)
+}
+
+// normalizeBatchOperation checks an operation has what its kind needs and
+// fills its key from the project body. Operations naming no instance are on
+// instance, the one in the URL if any.
+func normalizeBatchOperation(op *models.BatchOperation, instance string) error {
+	switch op.Op {
+	case models.BatchDelete:
+		if op.ProjectID == "" && op.Project != nil {
+			op.Instance, op.ProjectID = op.Project.Instance, op.Project.ProjectID
+		}
+		op.Project = nil
+	case models.BatchCreate, models.BatchUpdate, models.BatchUpsert:
+		if op.Project == nil {
+			return fmt.Errorf("%s requires a project", op.Op)
+		}
+		if op.ProjectID == "" {
+			op.ProjectID = op.Project.ProjectID
+		}
+		if op.Project.ProjectID == "" {
+			op.Project.ProjectID = op.ProjectID
+		}
+		if op.Project.ProjectID != op.ProjectID {
+			return fmt.Errorf("project_id does not match project.project_id")
+		}
+		if op.Instance == "" {
+			op.Instance = op.Project.Instance
+		}
+		if op.Project.Instance != "" && op.Project.Instance != op.Instance {
+			return fmt.Errorf("instance does not match project.instance")
+		}
+	default:
+		return fmt.Errorf("unknown op %q: must be create, update, upsert or delete", op.Op)
+	}
+
+	if instance != "" {
+		if op.Instance != "" && op.Instance != instance {
+			return fmt.Errorf("instance does not match the URL")
+		}
+		op.Instance = instance
+	}
+	op.Instance = models.NewProjectKey(op.Instance, "").Instance
+	if op.Project != nil {
+		op.Project.Instance = op.Instance
+	}
+
+	if op.ProjectID == "" {
+		return fmt.Errorf("Project ID is required")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+type CheckHandler struct {
+	profiles checks.Profiles
+	logger   *slog.Logger
+}
+
+// NewCheckHandler creates the handler describing the registered readiness
+// checks and the configured profiles
+func NewCheckHandler(profiles checks.Profiles, logger *slog.Logger) *CheckHandler {
+	return &CheckHandler{
+		profiles: profiles,
+		logger:   logger,
+	}
+}
+
+// ListChecks handles GET /api/v1/checks
+//
+//	@Summary		List readiness checks
+//	@Description	List every readiness check in display order. Each check's ID is the name of its boolean in project JSON and the value accepted by the failing filter, exports, imports and exemptions.
+//	@Tags			checks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse{data=[]checks.Definition}	"Registered checks"
+//	@Router			/checks [get]
+func (h *CheckHandler) ListChecks(w http.ResponseWriter, r *http.Request) {
+	response := models.NewSuccessResponse(http.StatusOK, "Checks retrieved successfully", checks.Definitions())
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// ListProfiles handles GET /api/v1/profiles
+//
+//	@Summary		List readiness profiles
+//	@Description	List the readiness profiles projects can be held to, the default one first. Each lists the threshold it puts on every measured check; a project's profile takes effect at its next scan.
+//	@Tags			checks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse{data=[]checks.Profile}	"Configured profiles"
+//	@Router			/profiles [get]
+func (h *CheckHandler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	response := models.NewSuccessResponse(http.StatusOK, "Profiles retrieved successfully", h.profiles.List())
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
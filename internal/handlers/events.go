@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+const (
+	// eventStreamBuffer is how many events a stream may fall behind before
+	// it is dropped; the client then reconnects and resumes from the outbox
+	eventStreamBuffer = 256
+
+	// eventReplayBatch is how many events a resuming stream reads at once
+	eventReplayBatch = 500
+
+	// eventStreamRetry is the reconnection delay suggested to clients
+	eventStreamRetry = 3 * time.Second
+
+	// groupRefreshInterval limits how often a stream filtered by group asks
+	// GitLab for the group's projects again after a project is created
+	groupRefreshInterval = time.Minute
+)
+
+// EventSubscriber delivers events as they are published
+type EventSubscriber interface {
+	Subscribe(buffer int) (<-chan models.Event, func())
+}
+
+// GroupProjectLister resolves a GitLab group to its projects
+type GroupProjectLister interface {
+	ListGroupProjects(ctx context.Context, group string) ([]gitlab.Project, error)
+}
+
+type EventsHandler struct {
+	broker    EventSubscriber
+	outbox    repository.OutboxRepository
+	groups    map[string]GroupProjectLister
+	heartbeat time.Duration
+	logger    *slog.Logger
+}
+
+// NewEventsHandler creates the event stream handler. groups holds a lister
+// for each GitLab instance with scanning configured; streams cannot be
+// filtered by the groups of other instances.
+func NewEventsHandler(broker EventSubscriber, outbox repository.OutboxRepository, groups map[string]GroupProjectLister, logger *slog.Logger) *EventsHandler {
+	return &EventsHandler{
+		broker:    broker,
+		outbox:    outbox,
+		groups:    groups,
+		heartbeat: 15 * time.Second,
+		logger:    logger,
+	}
+}
+
+// StreamEvents handles GET /api/v1/events
+// It streams project events as server-sent events
+//
+//	@Summary		Stream events
+//	@Description	Server-sent event stream of project changes from every instance: project.created, project.updated, project.deleted, project.ready, project.regressed, check.changed and scan.completed. Each event's id is its sequence number; reconnecting with Last-Event-ID (or last_event_id) first replays the events published since, as far back as the outbox retention. The instance filter applies to every other filter; project_id and group combine, so a stream with both receives events for either.
+//	@Tags			events
+//	@Produce		text/event-stream
+//	@Param			project_id		query		[]string	false	"Only events for these projects; repeat or comma-separate"	collectionFormat(csv)
+//	@Param			instance		query		string		false	"Only events for projects on this GitLab instance; the default instance when group is set"
+//	@Param			group			query		string		false	"Only events for projects in this GitLab group (ID or full path), including subgroups"
+//	@Param			last_event_id	query		int			false	"Resume after this sequence number, for clients that cannot set Last-Event-ID"
+//	@Param			Last-Event-ID	header		int			false	"Resume after this sequence number"
+//	@Success		200				{object}	models.Event	"Stream of events, each sent as JSON data"
+//	@Failure		400				{object}	models.ErrorResponse	"Bad request"
+//	@Failure		502				{object}	models.ErrorResponse	"GitLab group lookup failed"
+//	@Router			/events [get]
+func (h *EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	lastID := int64(-1) // No replay
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("last_event_id")
+	}
+	if v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 0 {
+			respondWithError(w, h.logger, http.StatusBadRequest, "Invalid last event ID")
+			return
+		}
+		lastID = id
+	}
+
+	filter := &eventFilter{
+		instance: strings.TrimSpace(r.URL.Query().Get("instance")),
+		projects: make(map[string]bool),
+		group:    strings.TrimSpace(r.URL.Query().Get("group")),
+	}
+	for _, v := range r.URL.Query()["project_id"] {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				filter.projects[id] = true
+			}
+		}
+	}
+	if filter.group != "" {
+		filter.instance = models.NewProjectKey(filter.instance, "").Instance
+		lister, ok := h.groups[filter.instance]
+		if !ok {
+			respondWithError(w, h.logger, http.StatusBadRequest, "Filtering by group requires GitLab scanning to be configured for instance "+filter.instance)
+			return
+		}
+		filter.lister = lister
+		if err := filter.resolve(ctx); err != nil {
+			h.logger.Error("failed to list group projects", "instance", filter.instance, "group", filter.group, "error", err)
+			respondWithError(w, h.logger, http.StatusBadGateway, "Failed to list the group's projects")
+			return
+		}
+	}
+
+	// Streams stay open far beyond the server's write timeout
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("failed to clear write deadline for event stream", "error", err)
+	}
+
+	// Subscribe before replaying so no event falls between the two
+	live, unsubscribe := h.broker.Subscribe(eventStreamBuffer)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetry.Milliseconds())
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	sent := lastID
+	send := func(event models.Event) error {
+		if event.Sequence <= sent {
+			return nil // Already sent while replaying
+		}
+		sent = event.Sequence
+		if !filter.matches(ctx, event) {
+			return nil
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data)
+		return err
+	}
+
+	for resuming := lastID >= 0; resuming; {
+		events, err := h.outbox.Since(ctx, sent, eventReplayBatch)
+		if err != nil {
+			h.logger.Error("failed to replay events", "last_event_id", sent, "error", err)
+			return
+		}
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		resuming = len(events) == eventReplayBatch
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-live:
+			if !ok {
+				// Dropped for falling behind, or the server is shutting
+				// down; either way the client reconnects and resumes
+				h.logger.Info("event stream closed", "last_event_id", sent)
+				return
+			}
+			if err := send(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// eventFilter selects the events a stream receives. With no instance,
+// projects or group it matches every event.
+type eventFilter struct {
+	instance string
+	projects map[string]bool
+	group    string
+
+	lister    GroupProjectLister
+	members   map[string]bool // IDs and paths of the group's projects
+	refreshed time.Time
+}
+
+// resolve fetches the group's projects, matching them by numeric ID or path
+func (f *eventFilter) resolve(ctx context.Context) error {
+	projects, err := f.lister.ListGroupProjects(ctx, f.group)
+	if err != nil {
+		return err
+	}
+
+	f.members = make(map[string]bool, 2*len(projects))
+	for _, p := range projects {
+		f.members[strconv.Itoa(p.ID)] = true
+		f.members[p.PathWithNamespace] = true
+	}
+	f.refreshed = time.Now()
+	return nil
+}
+
+func (f *eventFilter) matches(ctx context.Context, event models.Event) bool {
+	if f.instance != "" && models.NewProjectKey(event.Instance, "").Instance != f.instance {
+		return false
+	}
+	if len(f.projects) == 0 && f.group == "" {
+		return true
+	}
+	if f.projects[event.ProjectID] {
+		return true
+	}
+	if f.group == "" {
+		return false
+	}
+	if f.members[event.ProjectID] || strings.HasPrefix(event.ProjectID, f.group+"/") {
+		return true
+	}
+
+	// A new project may have joined the group since it was resolved
+	if event.Type == models.EventProjectCreated && time.Since(f.refreshed) > groupRefreshInterval {
+		if err := f.resolve(ctx); err != nil {
+			f.refreshed = time.Now() // Keep the stale members rather than retrying on every event
+			return false
+		}
+		return f.members[event.ProjectID]
+	}
+	return false
+}
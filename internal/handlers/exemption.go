@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+type ExemptionHandler struct {
+	projects   repository.ProjectRepository
+	exemptions repository.ExemptionRepository
+	logger     *slog.Logger
+}
+
+func NewExemptionHandler(projects repository.ProjectRepository, exemptions repository.ExemptionRepository, logger *slog.Logger) *ExemptionHandler {
+	return &ExemptionHandler{
+		projects:   projects,
+		exemptions: exemptions,
+		logger:     logger,
+	}
+}
+
+// ListExemptions handles GET /api/v1/gitlab/projects/{id}/exemptions
+//
+//	@Summary		List exemptions
+//	@Description	List every exemption recorded for a project, including expired ones
+//	@Tags			exemptions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Project ID"
+//	@Success		200	{object}	models.SuccessResponse{data=[]models.Exemption}	"Project exemptions"
+//	@Failure		404	{object}	models.ErrorResponse	"Project ID not found"
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/{id}/exemptions [get]
+func (h *ExemptionHandler) ListExemptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := projectKey(r)
+
+	if !h.projectExists(w, r, key) {
+		return
+	}
+
+	exemptions, err := h.exemptions.ListByProject(ctx, key)
+	if err != nil {
+		h.logger.Error("failed to list exemptions", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve exemptions")
+		return
+	}
+	if exemptions == nil {
+		exemptions = []*models.Exemption{}
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Exemptions retrieved successfully", exemptions)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// CreateExemption handles POST /api/v1/gitlab/projects/{id}/exemptions
+//
+//	@Summary		Create exemption
+//	@Description	Waive a readiness check for a project, optionally for one environment and until expires_at
+//	@Tags			exemptions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Project ID"
+//	@Param			exemption	body		models.Exemption	true	"Exemption (check_name and reason are required)"
+//	@Success		201			{object}	models.SuccessResponse{data=models.Exemption}	"Created exemption"
+//	@Failure		400			{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404			{object}	models.ErrorResponse	"Project ID not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/{id}/exemptions [post]
+func (h *ExemptionHandler) CreateExemption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := projectKey(r)
+
+	var exemption models.Exemption
+	if err := json.NewDecoder(r.Body).Decode(&exemption); err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if _, ok := checks.Lookup(exemption.CheckName); !ok {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Unknown check: "+exemption.CheckName)
+		return
+	}
+	if exemption.Reason == "" {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Reason is required")
+		return
+	}
+	if exemption.ExpiresAt != nil && !exemption.ExpiresAt.After(time.Now()) {
+		respondWithError(w, h.logger, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	if !h.projectExists(w, r, key) {
+		return
+	}
+
+	exemption.Instance, exemption.ProjectID = key.Instance, key.ProjectID
+	if err := h.exemptions.Create(ctx, &exemption); err != nil {
+		h.logger.Error("failed to create exemption", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to create exemption")
+		return
+	}
+
+	h.logger.Info("exemption created",
+		"instance", key.Instance,
+		"project_id", key.ProjectID,
+		"check", exemption.CheckName,
+		"environment", exemption.Environment,
+		"created_by", exemption.CreatedBy,
+	)
+	response := models.NewSuccessResponse(http.StatusCreated, "Exemption created successfully", exemption)
+	respondWithJSON(w, h.logger, http.StatusCreated, response)
+}
+
+// DeleteExemption handles DELETE /api/v1/gitlab/projects/{id}/exemptions/{exemptionID}
+//
+//	@Summary		Delete exemption
+//	@Description	Revoke an exemption
+//	@Tags			exemptions
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path	string	true	"Project ID"
+//	@Param			exemptionID	path	int		true	"Exemption ID"
+//	@Success		204			{object}	models.SuccessResponse	"Exemption deleted successfully"
+//	@Failure		400			{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404			{object}	models.ErrorResponse	"Exemption not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/{id}/exemptions/{exemptionID} [delete]
+func (h *ExemptionHandler) DeleteExemption(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := projectKey(r)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "exemptionID"), 10, 64)
+	if err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid exemption ID")
+		return
+	}
+
+	if err := h.exemptions.Delete(ctx, key, id); err != nil {
+		if err.Error() == "exemption not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "Exemption not found")
+			return
+		}
+		h.logger.Error("failed to delete exemption", "error", err, "instance", key.Instance, "project_id", key.ProjectID, "exemption_id", id)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to delete exemption")
+		return
+	}
+
+	h.logger.Info("exemption deleted", "instance", key.Instance, "project_id", key.ProjectID, "exemption_id", id)
+	response := models.NewSuccessResponse(http.StatusNoContent, "Exemption deleted successfully", nil)
+	respondWithJSON(w, h.logger, http.StatusNoContent, response)
+}
+
+// projectExists writes a 404 or 500 response and returns false when the
+// project cannot be found
+func (h *ExemptionHandler) projectExists(w http.ResponseWriter, r *http.Request, key models.ProjectKey) bool {
+	if _, err := h.projects.GetByID(r.Context(), key); err != nil {
+		if err.Error() == "project not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "project_id not found")
+			return false
+		}
+		h.logger.Error("failed to get project", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve project")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/user/go-backend/internal/bulk"
+	"github.com/user/go-backend/internal/repository"
+)
+
+// exportFlushRows is how often an export pushes buffered rows to the client
+const exportFlushRows = 500
+
+type ExportHandler struct {
+	repo   repository.ProjectRepository
+	logger *slog.Logger
+}
+
+func NewExportHandler(repo repository.ProjectRepository, logger *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ExportProjects handles GET /api/v1/gitlab/projects/export
+// It streams every matching project as CSV, NDJSON or XLSX
+//
+//	@Summary		Export projects
+//	@Description	Stream the full inventory, honoring the same filters as listing. Columns follow models.Project (instance, project_id, each check, created_at, updated_at) plus a derived ready column; rows are ordered by instance and project_id. CSV and NDJSON exports can be uploaded again through the import endpoint.
+//	@Tags			gitlab
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string	false	"Export format"	Enums(csv, ndjson, xlsx)	default(csv)
+//	@Param			instance	query		string	false	"Only projects on the named GitLab instance; every instance by default"
+//	@Param			ready	query		bool	false	"Only projects that pass (true) or fail (false) every check"
+//	@Param			failing	query		string	false	"Only projects failing the named check, e.g. codeowners_exists"
+//	@Param			code_owner	query		string	false	"Only projects whose CODEOWNERS file names the owner, e.g. @platform/leads"
+//	@Success		200		{file}		file	"Export file"
+//	@Failure		400		{object}	models.ErrorResponse	"Bad request"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/export [get]
+func (h *ExportHandler) ExportProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format := bulk.FormatCSV
+	if v := r.URL.Query().Get("format"); v != "" {
+		var err error
+		if format, err = bulk.ParseFormat(v); err != nil {
+			respondWithError(w, h.logger, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	filter, err := parseProjectFilter(r)
+	if err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Large exports outlive the server's write timeout, so lift it for
+	// this response. The export route's own timeout, longer than other
+	// routes', still bounds the request context.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		h.logger.Warn("failed to clear write deadline for export", "error", err)
+	}
+
+	var out bulk.Writer
+	rows := 0
+
+	// start commits to a 200 response once the first row (or the end of an
+	// empty result) shows the query is working
+	start := func() {
+		filename := "projects-" + time.Now().UTC().Format("2006-01-02") + "." + format.Extension()
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+
+		var err error
+		if out, err = bulk.NewWriter(w, format); err != nil {
+			h.abortExport(err, rows)
+		}
+	}
+
+	for project, err := range h.repo.Stream(ctx, filter) {
+		if err != nil && out == nil {
+			h.logger.Error("failed to export projects", "error", err)
+			respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to export projects")
+			return
+		}
+		if err != nil {
+			h.abortExport(err, rows)
+		}
+
+		if out == nil {
+			start()
+		}
+		if err := out.Write(project); err != nil {
+			h.abortExport(err, rows)
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			rc.Flush()
+		}
+	}
+
+	if out == nil {
+		start()
+	}
+	if err := out.Close(); err != nil {
+		h.abortExport(err, rows)
+	}
+
+	h.logger.Info("projects exported", "format", format, "rows", rows)
+}
+
+// abortExport ends an export whose status line has already been sent. The
+// connection is dropped so the client sees a broken transfer rather than a
+// silently truncated file.
+func (h *ExportHandler) abortExport(err error, rows int) {
+	h.logger.Error("export aborted", "error", err, "rows", rows)
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+const (
+	defaultGateEnvironment = "production"
+
+	// maxGateScanTimeout keeps synchronous scans inside the server's write timeout
+	maxGateScanTimeout = 10 * time.Second
+)
+
+type GateHandler struct {
+	repo       repository.ProjectRepository
+	exemptions repository.ExemptionRepository
+	scanner    Scanner
+	logger     *slog.Logger
+}
+
+// NewGateHandler creates the deploy gate handler. scanner may be nil, in
+// which case stale data cannot be refreshed and fails the gate.
+func NewGateHandler(repo repository.ProjectRepository, exemptions repository.ExemptionRepository, scanner Scanner, logger *slog.Logger) *GateHandler {
+	return &GateHandler{
+		repo:       repo,
+		exemptions: exemptions,
+		scanner:    scanner,
+		logger:     logger,
+	}
+}
+
+// Gate handles GET /api/v1/gitlab/projects/{id}/gate
+// It answers whether a project may deploy to an environment
+//
+//	@Summary		Deploy gate
+//	@Description	Decide whether a project may deploy, taking active exemptions and data freshness into account. With max_age set, stale data triggers a synchronous rescan.
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string	true	"Project ID"
+//	@Param			environment		query		string	false	"Target environment"	default(production)
+//	@Param			max_age			query		int		false	"Require a full scan newer than this many minutes"
+//	@Param			scan_timeout	query		int		false	"Seconds to wait for a rescan of stale data (max 10)"	default(10)
+//	@Success		200				{object}	models.SuccessResponse{data=models.GateResult}	"Gate decision"
+//	@Failure		400				{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404				{object}	models.ErrorResponse	"Project ID not found"
+//	@Failure		500				{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/{id}/gate [get]
+func (h *GateHandler) Gate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	key := projectKey(r)
+	query := r.URL.Query()
+
+	environment := query.Get("environment")
+	if environment == "" {
+		environment = defaultGateEnvironment
+	}
+
+	var maxAge time.Duration
+	if v := query.Get("max_age"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes < 0 {
+			respondWithError(w, h.logger, http.StatusBadRequest, "Invalid max_age: must be a non-negative number of minutes")
+			return
+		}
+		maxAge = time.Duration(minutes) * time.Minute
+	}
+
+	scanTimeout := maxGateScanTimeout
+	if v := query.Get("scan_timeout"); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds <= 0 {
+			respondWithError(w, h.logger, http.StatusBadRequest, "Invalid scan_timeout: must be a positive number of seconds")
+			return
+		}
+		scanTimeout = min(time.Duration(seconds)*time.Second, maxGateScanTimeout)
+	}
+
+	project, err := h.repo.GetByID(ctx, key)
+	if err != nil {
+		if err.Error() == "project not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "project_id not found")
+			return
+		}
+		h.logger.Error("failed to get project", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve project")
+		return
+	}
+
+	// Freshness counts from the last full scan, since other writes and
+	// rescans of some checks leave the rest of the data unverified
+	freshness := models.Freshness{MaxAgeSeconds: int64(maxAge.Seconds())}
+	if maxAge > 0 && stale(project, maxAge) {
+		project = h.rescan(ctx, project, scanTimeout, &freshness)
+	}
+
+	if project.LastScannedAt != nil {
+		freshness.UpdatedAt = *project.LastScannedAt
+		freshness.AgeSeconds = int64(time.Since(*project.LastScannedAt).Seconds())
+	}
+	freshness.Stale = maxAge > 0 && stale(project, maxAge)
+
+	exemptions, err := h.exemptions.ListByProject(ctx, key)
+	if err != nil {
+		h.logger.Error("failed to list exemptions", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve exemptions")
+		return
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Gate evaluated", evaluateGate(project, environment, exemptions, freshness))
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// stale reports whether the project's last full scan is older than maxAge,
+// or it has never had one
+func stale(project *models.Project, maxAge time.Duration) bool {
+	return project.LastScannedAt == nil || time.Since(*project.LastScannedAt) > maxAge
+}
+
+// rescan refreshes stale check data within timeout. Failures are recorded
+// in freshness and the stored project is returned unchanged.
+func (h *GateHandler) rescan(ctx context.Context, project *models.Project, timeout time.Duration, freshness *models.Freshness) *models.Project {
+	if h.scanner == nil {
+		freshness.ScanError = "scanning is not configured"
+		return project
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	scanned, err := h.scanner.Scan(scanCtx, project.Key())
+	if err != nil {
+		h.logger.Warn("gate rescan failed", "error", err, "instance", project.Instance, "project_id", project.ProjectID)
+		freshness.ScanError = err.Error()
+		return project
+	}
+
+	freshness.Rescanned = true
+	return scanned
+}
+
+// evaluateGate applies the exemptions active for environment to the
+// project's failing checks
+func evaluateGate(project *models.Project, environment string, exemptions []*models.Exemption, freshness models.Freshness) *models.GateResult {
+	now := time.Now()
+
+	active := make(map[string]bool)
+	result := &models.GateResult{
+		Instance:       project.Key().Instance,
+		ProjectID:      project.ProjectID,
+		Environment:    environment,
+		FailingChecks:  []models.CheckResult{},
+		ExemptedChecks: []models.CheckResult{},
+		Exemptions:     []*models.Exemption{},
+		Freshness:      freshness,
+	}
+
+	for _, e := range activeExemptions(exemptions, environment, now) {
+		active[e.CheckName] = true
+		result.Exemptions = append(result.Exemptions, e)
+	}
+
+	for _, check := range project.FailingChecks() {
+		if active[check.Name] {
+			result.ExemptedChecks = append(result.ExemptedChecks, check)
+		} else {
+			result.FailingChecks = append(result.FailingChecks, check)
+		}
+	}
+
+	result.Passed = len(result.FailingChecks) == 0 && !freshness.Stale
+	return result
+}
+
+// activeExemptions returns the exemptions that apply to environment at now
+func activeExemptions(exemptions []*models.Exemption, environment string, now time.Time) []*models.Exemption {
+	var active []*models.Exemption
+	for _, e := range exemptions {
+		if e.ActiveFor(environment, now) {
+			active = append(active, e)
+		}
+	}
+	return active
+}
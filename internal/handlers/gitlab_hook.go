@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+	"github.com/user/go-backend/internal/scanner"
+)
+
+// maxHookBody bounds GitLab hook payloads, which list at most 20 commits
+const maxHookBody = 5 << 20
+
+// RescanQueue schedules rescans to run in the background
+type RescanQueue interface {
+	Enqueue(key models.ProjectKey, checks []string) bool
+}
+
+type GitLabHookHandler struct {
+	repo    repository.ProjectRepository
+	queue   RescanQueue
+	secrets map[string]string
+	logger  *slog.Logger
+}
+
+// NewGitLabHookHandler creates the GitLab hook receiver. Hooks from an
+// instance are accepted when they carry its entry in secrets as
+// X-Gitlab-Token; instances without one are refused. queue may be nil when
+// scanning is not configured, in which case only project creation and
+// removal are acted on.
+func NewGitLabHookHandler(repo repository.ProjectRepository, queue RescanQueue, secrets map[string]string, logger *slog.Logger) *GitLabHookHandler {
+	return &GitLabHookHandler{
+		repo:    repo,
+		queue:   queue,
+		secrets: secrets,
+		logger:  logger,
+	}
+}
+
+// ReceiveHook handles POST /api/v1/gitlab/hooks and
+// POST /api/v1/gitlab/instances/{instance}/hooks
+// It keeps stored projects current as GitLab reports changes
+//
+//	@Summary		Receive GitLab hook
+//	@Description	Endpoint for GitLab project, group and system hooks. Pushes to the default branch rescan the checks reading the files they touched (.gitlab-ci.yml, CODEOWNERS), repository_update rescans every file-based check, and project_update, project_rename and project_transfer rescan every check. project_create registers the project and project_destroy marks it absent. Rescans run in the background; events for unregistered projects are ignored.
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			X-Gitlab-Token	header		string					true	"Secret configured for the hook"
+//	@Param			event			body		gitlab.HookEvent		true	"Hook payload"
+//	@Success		200				{object}	models.SuccessResponse{data=models.GitLabHookResult}	"Event handled or ignored"
+//	@Success		202				{object}	models.SuccessResponse{data=models.GitLabHookResult}	"Rescan queued"
+//	@Failure		400				{object}	models.ErrorResponse	"Bad request"
+//	@Failure		401				{object}	models.ErrorResponse	"Invalid token"
+//	@Failure		404				{object}	models.ErrorResponse	"Hooks are not configured for the instance"
+//	@Failure		500				{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/hooks [post]
+func (h *GitLabHookHandler) ReceiveHook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	instance := models.NewProjectKey(chi.URLParam(r, "instance"), "").Instance
+
+	secret, ok := h.secrets[instance]
+	if !ok {
+		respondWithError(w, h.logger, http.StatusNotFound, "Hooks are not configured for instance "+instance)
+		return
+	}
+
+	token := r.Header.Get(gitlab.HookTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		respondWithError(w, h.logger, http.StatusUnauthorized, "Invalid GitLab token")
+		return
+	}
+
+	var event gitlab.HookEvent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxHookBody)).Decode(&event); err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid hook payload")
+		return
+	}
+
+	plan := scanner.PlanHook(&event)
+	keys := event.ProjectKeys()
+	if plan.Action != scanner.HookIgnore && len(keys) == 0 {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Hook payload does not identify a project")
+		return
+	}
+
+	var stored *models.Project
+	if plan.Action != scanner.HookIgnore {
+		var err error
+		if stored, err = h.find(ctx, instance, keys); err != nil {
+			h.logger.Error("failed to look up hook project", "instance", instance, "keys", keys, "error", err)
+			respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to look up project")
+			return
+		}
+		if stored != nil {
+			plan = plan.ForProject(stored)
+		}
+	}
+
+	result := models.GitLabHookResult{
+		Instance: instance,
+		Event:    event.Kind(),
+		Action:   string(plan.Action),
+		Checks:   plan.Checks,
+		Reason:   plan.Reason,
+	}
+	ignore := func(reason string) {
+		result.Action, result.Checks, result.Reason = string(scanner.HookIgnore), nil, reason
+	}
+
+	status, message := http.StatusOK, "Hook processed"
+	switch plan.Action {
+	case scanner.HookCreate:
+		if stored == nil {
+			stored = &models.Project{Instance: instance, ProjectID: keys[0], Results: map[string]bool{checks.ProjectPresent: true}}
+			// The rest of the metadata is filled in by the scan queued below
+			stored.GitLabID = event.ProjectID
+			stored.Name = event.Name
+			stored.PathWithNamespace = event.PathWithNamespace
+			stored.Visibility = event.ProjectVisibility
+			if err := h.repo.Create(ctx, stored); err != nil {
+				h.logger.Error("failed to register project from hook", "instance", instance, "project_id", stored.ProjectID, "error", err)
+				respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to create project")
+				return
+			}
+			h.logger.Info("project registered from gitlab hook", "instance", instance, "project_id", stored.ProjectID)
+		}
+		result.ProjectID = stored.ProjectID
+
+		// New projects are scanned in full
+		if h.queue != nil && !h.queue.Enqueue(stored.Key(), nil) {
+			h.logger.Warn("rescan queue full, new project left unscanned", "instance", instance, "project_id", stored.ProjectID)
+		}
+
+	case scanner.HookRemove:
+		if stored == nil {
+			ignore("project is not registered")
+			break
+		}
+		// The same result as a scan finding the project gone
+		absent := &models.Project{Instance: stored.Instance, ProjectID: stored.ProjectID}
+		if err := h.repo.SaveScan(ctx, absent, nil); err != nil {
+			h.logger.Error("failed to mark project absent", "instance", instance, "project_id", stored.ProjectID, "error", err)
+			respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to update project")
+			return
+		}
+		result.ProjectID = stored.ProjectID
+		h.logger.Info("project marked absent from gitlab hook", "instance", instance, "project_id", stored.ProjectID)
+
+	case scanner.HookRescan:
+		switch {
+		case stored == nil:
+			ignore("project is not registered")
+		case h.queue == nil:
+			ignore("scanning is not configured")
+		case !h.queue.Enqueue(stored.Key(), plan.Checks):
+			h.logger.Warn("rescan queue full, dropping gitlab hook", "instance", instance, "project_id", stored.ProjectID)
+			ignore("rescan queue is full")
+		default:
+			result.ProjectID = stored.ProjectID
+			status, message = http.StatusAccepted, "Rescan queued"
+		}
+	}
+
+	respondWithJSON(w, h.logger, status, models.NewSuccessResponse(status, message, result))
+}
+
+// find returns the instance's stored project matching any of keys, by
+// project_id or by the GitLab ID and path a scan recorded, or nil
+func (h *GitLabHookHandler) find(ctx context.Context, instance string, keys []string) (*models.Project, error) {
+	for _, key := range keys {
+		project, err := h.repo.GetByRef(ctx, instance, key)
+		if err != nil && err.Error() == "project not found" {
+			continue
+		}
+		return project, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 16 << 20
+
+	// idempotencyLockTimeout is how long an in-flight key blocks retries
+	// before it is considered abandoned, e.g. after a crash. It must exceed
+	// the router's request timeout.
+	idempotencyLockTimeout = 2 * time.Minute
+)
+
+// replayedHeaders are not stored with a response: they describe the original
+// exchange rather than the result
+var replayedHeaders = map[string]bool{
+	"Content-Length":           true,
+	"Date":                     true,
+	middleware.RequestIDHeader: true,
+}
+
+// IdempotencyMiddleware makes write requests carrying an Idempotency-Key
+// header safe to retry: the first response for a key is stored and replayed
+// for later requests with the same key and body.
+type IdempotencyMiddleware struct {
+	repo   repository.IdempotencyRepository
+	ttl    time.Duration
+	logger *slog.Logger
+}
+
+// NewIdempotencyMiddleware creates the middleware. Stored responses are
+// replayed for ttl after the original request.
+func NewIdempotencyMiddleware(repo repository.IdempotencyRepository, ttl time.Duration, logger *slog.Logger) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		repo:   repo,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Wrap applies idempotency to POST, PUT, PATCH and DELETE requests that send
+// an Idempotency-Key header. Other requests pass straight through.
+func (m *IdempotencyMiddleware) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || !idempotentMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithError(w, m.logger, http.StatusBadRequest, fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestBytes))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				respondWithError(w, m.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxIdempotentRequestBytes))
+				return
+			}
+			respondWithError(w, m.logger, http.StatusBadRequest, "Failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		key = idempotencyScope(r, key)
+		hash := requestHash(r, body)
+		existing, err := m.claim(r.Context(), key, hash)
+		if errors.Is(err, repository.ErrIdempotencyKeyNotFound) {
+			// The owner released the key between our claim and reading it,
+			// so it is free to claim again
+			existing, err = m.claim(r.Context(), key, hash)
+		}
+		if err != nil {
+			m.logger.Error("failed to claim idempotency key", "error", err)
+			respondWithError(w, m.logger, http.StatusInternalServerError, "Failed to process Idempotency-Key")
+			return
+		}
+
+		switch {
+		case existing == nil:
+			m.execute(w, r, next, key)
+		case existing.RequestHash != hash:
+			respondWithError(w, m.logger, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+		case !existing.Completed():
+			w.Header().Set("Retry-After", "1")
+			respondWithError(w, m.logger, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		default:
+			m.replay(w, existing)
+		}
+	})
+}
+
+func (m *IdempotencyMiddleware) claim(ctx context.Context, key, hash string) (*models.IdempotencyRecord, error) {
+	now := time.Now()
+	return m.repo.Claim(ctx, key, hash, now.Add(m.ttl), now.Add(-idempotencyLockTimeout))
+}
+
+// execute runs the request and stores its response. Server errors are not
+// stored, so a retry with the same key runs the request again.
+func (m *IdempotencyMiddleware) execute(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	// Bookkeeping must happen even if the client has gone away
+	ctx := context.WithoutCancel(r.Context())
+
+	rec := &recordingWriter{ResponseWriter: w}
+	completed := false
+	defer func() {
+		if !completed {
+			if err := m.repo.Release(ctx, key); err != nil {
+				m.logger.Error("failed to release idempotency key", "error", err)
+			}
+		}
+	}()
+
+	next.ServeHTTP(rec, r)
+
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= 500 || status == http.StatusTooManyRequests {
+		return
+	}
+
+	headers := make(http.Header)
+	for name, values := range w.Header() {
+		if !replayedHeaders[name] {
+			headers[name] = values
+		}
+	}
+
+	err := m.repo.Complete(ctx, &models.IdempotencyRecord{
+		Key:        key,
+		StatusCode: status,
+		Headers:    headers,
+		Body:       rec.body.Bytes(),
+	})
+	if err != nil {
+		m.logger.Error("failed to store idempotent response", "error", err)
+		return
+	}
+	completed = true
+}
+
+// replay writes a stored response
+func (m *IdempotencyMiddleware) replay(w http.ResponseWriter, record *models.IdempotencyRecord) {
+	for name, values := range record.Headers {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	if _, err := w.Write(record.Body); err != nil {
+		m.logger.Error("failed to replay response", "error", err)
+	}
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// idempotencyScope is the key a request's Idempotency-Key is stored under. It
+// includes the method and path, so a key reused on another endpoint is a
+// different key rather than a mismatched request. Paths are escaped and
+// methods are single words, so the scope is unambiguous.
+func idempotencyScope(r *http.Request, key string) string {
+	return r.Method + " " + r.URL.EscapedPath() + " " + key
+}
+
+// requestHash identifies a request by method, path, query and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", r.Method, r.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter passes a response through while keeping a copy
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	if rw.status == 0 {
+		rw.status = code
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *recordingWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
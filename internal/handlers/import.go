@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/go-backend/internal/bulk"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+const (
+	maxImportBytes = 10 << 20
+	maxImportRows  = 5000
+)
+
+// Import modes
+const (
+	importAtomic  = "atomic"
+	importPartial = "partial"
+)
+
+type ImportHandler struct {
+	repo   repository.ProjectRepository
+	logger *slog.Logger
+}
+
+func NewImportHandler(repo repository.ProjectRepository, logger *slog.Logger) *ImportHandler {
+	return &ImportHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ImportProjects handles POST /api/v1/gitlab/projects/import
+// It creates or updates many projects from a CSV or NDJSON upload
+//
+//	@Summary		Bulk import projects
+//	@Description	Upload projects as CSV (a project_id column, an optional instance column and one column per check) or NDJSON (one project per line). Every row is validated first. In atomic mode any invalid row rejects the whole file and nothing is written; in partial mode valid rows are committed and invalid ones reported.
+//	@Tags			gitlab
+//	@Accept			text/csv
+//	@Accept			application/x-ndjson
+//	@Produce		json
+//	@Param			file		body		string	true	"CSV or NDJSON file"
+//	@Param			format		query		string	false	"Overrides the Content-Type"	Enums(csv, ndjson)
+//	@Param			mode		query		string	false	"Transaction mode"	Enums(atomic, partial)	default(atomic)
+//	@Param			on_conflict	query		string	false	"What to do with existing projects"	Enums(skip, update, fail)	default(skip)
+//	@Success		200			{object}	models.SuccessResponse{data=models.ImportSummary}	"Import summary"
+//	@Failure		400			{object}	models.ErrorResponse	"Bad request"
+//	@Failure		413			{object}	models.ErrorResponse	"File too large"
+//	@Failure		415			{object}	models.ErrorResponse	"Unsupported format"
+//	@Failure		422			{object}	models.ErrorResponse{details=models.ImportSummary}	"Rows failed validation; nothing was imported"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/import [post]
+func (h *ImportHandler) ImportProjects(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	var format bulk.Format
+	if v := query.Get("format"); v != "" {
+		var err error
+		if format, err = bulk.ParseFormat(v); err != nil {
+			respondWithError(w, h.logger, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		var ok bool
+		if format, ok = bulk.FormatForContentType(r.Header.Get("Content-Type")); !ok {
+			respondWithError(w, h.logger, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or application/x-ndjson")
+			return
+		}
+	}
+
+	mode := query.Get("mode")
+	switch mode {
+	case "":
+		mode = importAtomic
+	case importAtomic, importPartial:
+	default:
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid mode: must be atomic or partial")
+		return
+	}
+
+	onConflict := repository.ConflictAction(query.Get("on_conflict"))
+	switch onConflict {
+	case "":
+		onConflict = repository.ConflictSkip
+	case repository.ConflictSkip, repository.ConflictUpdate, repository.ConflictFail:
+	default:
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid on_conflict: must be skip, update or fail")
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	instance := chi.URLParam(r, "instance")
+	rows, err := bulk.Read(body, format, maxImportRows, instance)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			respondWithError(w, h.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("Import file exceeds %d bytes", maxImportBytes))
+		case errors.Is(err, bulk.ErrTooManyRows):
+			respondWithError(w, h.logger, http.StatusRequestEntityTooLarge, fmt.Sprintf("Import file exceeds %d rows", maxImportRows))
+		default:
+			respondWithError(w, h.logger, http.StatusBadRequest, "Invalid import file: "+err.Error())
+		}
+		return
+	}
+	if len(rows) == 0 {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Import file contains no projects")
+		return
+	}
+
+	summary := &models.ImportSummary{
+		Mode:       mode,
+		OnConflict: string(onConflict),
+		Total:      len(rows),
+		Errors:     []models.ImportRowError{},
+	}
+
+	// valid holds the rows handed to the repository, in the same order as projects
+	var valid []bulk.Row
+	var projects []*models.Project
+	for _, row := range rows {
+		if row.Err == nil && instance != "" && row.Project.Instance != instance {
+			row.Err = errors.New("instance does not match the URL")
+		}
+		if row.Err != nil {
+			summary.Errors = append(summary.Errors, models.ImportRowError{Line: row.Line, ProjectID: row.ProjectID(), Error: row.Err.Error()})
+			continue
+		}
+		valid = append(valid, row)
+		projects = append(projects, row.Project)
+	}
+
+	if mode == importAtomic && len(summary.Errors) > 0 {
+		summary.Failed = len(summary.Errors)
+		h.rejectImport(w, summary)
+		return
+	}
+
+	results, err := h.repo.Import(ctx, projects, repository.ImportOptions{
+		OnConflict: onConflict,
+		Partial:    mode == importPartial,
+	})
+	if err != nil {
+		var rowErr *repository.ImportRowError
+		if errors.As(err, &rowErr) {
+			row := valid[rowErr.Index]
+			summary.Failed = 1
+			summary.Errors = append(summary.Errors, models.ImportRowError{Line: row.Line, ProjectID: row.ProjectID(), Error: h.rowError(row, rowErr.Err)})
+			h.rejectImport(w, summary)
+			return
+		}
+		h.logger.Error("failed to import projects", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to import projects")
+		return
+	}
+
+	summary.Committed = true
+	summary.Failed = len(summary.Errors)
+	for i, result := range results {
+		switch result.Outcome {
+		case repository.ImportCreated:
+			summary.Created++
+		case repository.ImportUpdated:
+			summary.Updated++
+		case repository.ImportSkipped:
+			summary.Skipped++
+		case repository.ImportFailed:
+			summary.Failed++
+			row := valid[i]
+			summary.Errors = append(summary.Errors, models.ImportRowError{Line: row.Line, ProjectID: row.ProjectID(), Error: h.rowError(row, result.Err)})
+		}
+	}
+
+	h.logger.Info("projects imported",
+		"mode", mode,
+		"on_conflict", onConflict,
+		"created", summary.Created,
+		"updated", summary.Updated,
+		"skipped", summary.Skipped,
+		"failed", summary.Failed,
+	)
+	response := models.NewSuccessResponse(http.StatusOK, "Import completed", summary)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// rejectImport reports an atomic import that wrote nothing
+func (h *ImportHandler) rejectImport(w http.ResponseWriter, summary *models.ImportSummary) {
+	message := fmt.Sprintf("%d row(s) failed; nothing was imported", summary.Failed)
+	response := models.NewErrorResponseWithDetails(http.StatusUnprocessableEntity, message, summary)
+	respondWithJSON(w, h.logger, http.StatusUnprocessableEntity, response)
+}
+
+// rowError turns a repository failure into a message safe to return,
+// logging database errors rather than echoing them
+func (h *ImportHandler) rowError(row bulk.Row, err error) string {
+	if errors.Is(err, repository.ErrProjectExists) {
+		return err.Error()
+	}
+	h.logger.Error("failed to import project", "error", err, "project_id", row.ProjectID(), "line", row.Line)
+	return "failed to write project"
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+type InstanceHandler struct {
+	instances []models.GitLabInstance
+	logger    *slog.Logger
+}
+
+// NewInstanceHandler creates the handler describing the configured GitLab
+// instances
+func NewInstanceHandler(instances []models.GitLabInstance, logger *slog.Logger) *InstanceHandler {
+	return &InstanceHandler{
+		instances: instances,
+		logger:    logger,
+	}
+}
+
+// ListInstances handles GET /api/v1/gitlab/instances
+//
+//	@Summary		List GitLab instances
+//	@Description	List the GitLab instances projects can be registered against, with their rate limits and which of scanning, hooks and group sync are configured. Projects of an instance are served under /gitlab/instances/{instance}/projects.
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse{data=[]models.GitLabInstance}	"Configured instances"
+//	@Router			/gitlab/instances [get]
+func (h *InstanceHandler) ListInstances(w http.ResponseWriter, r *http.Request) {
+	instances := h.instances
+	if instances == nil {
+		instances = []models.GitLabInstance{}
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Instances retrieved successfully", instances)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
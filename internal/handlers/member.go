@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+)
+
+// MemberReviewer reads a stored project's members from GitLab
+type MemberReviewer interface {
+	ReviewMembers(ctx context.Context, key models.ProjectKey) (*checks.MemberReview, error)
+}
+
+type MemberHandler struct {
+	reviewer MemberReviewer
+	logger   *slog.Logger
+}
+
+// NewMemberHandler creates a handler for member reports. reviewer may be nil
+// when no GitLab connection is configured, in which case reports return 503.
+func NewMemberHandler(reviewer MemberReviewer, logger *slog.Logger) *MemberHandler {
+	return &MemberHandler{
+		reviewer: reviewer,
+		logger:   logger,
+	}
+}
+
+// ListMembers handles GET /api/v1/gitlab/projects/{id}/members
+// It reports who has access to the project, as the access review checks see it
+//
+//	@Summary		Project member report
+//	@Description	List the project's members, directly or through its groups, read live from GitLab, with the owners, maintainers, external users with write access and inactive accounts the access review checks count
+//	@Tags			gitlab
+//	@Produce		json
+//	@Param			id	path		string	true	"Project ID"
+//	@Success		200	{object}	models.SuccessResponse{data=checks.MemberReview}	"Member report"
+//	@Failure		404	{object}	models.ErrorResponse	"Project ID not found, in storage or GitLab"
+//	@Failure		502	{object}	models.ErrorResponse	"GitLab request failed"
+//	@Failure		503	{object}	models.ErrorResponse	"Scanning is not configured for the project's instance"
+//	@Router			/gitlab/projects/{id}/members [get]
+func (h *MemberHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	if h.reviewer == nil {
+		respondWithError(w, h.logger, http.StatusServiceUnavailable, "Scanning is not configured")
+		return
+	}
+
+	key := projectKey(r)
+	review, err := h.reviewer.ReviewMembers(r.Context(), key)
+	if err != nil {
+		switch {
+		case err.Error() == "project not found":
+			respondWithError(w, h.logger, http.StatusNotFound, "project_id not found")
+		case errors.Is(err, gitlab.ErrNotFound):
+			respondWithError(w, h.logger, http.StatusNotFound, "Project not found in GitLab")
+		case errors.Is(err, gitlab.ErrUnknownInstance):
+			respondWithError(w, h.logger, http.StatusServiceUnavailable, "Scanning is not configured for instance "+key.Instance)
+		default:
+			h.logger.Error("failed to review members", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
+			respondWithError(w, h.logger, http.StatusBadGateway, "Failed to read project members")
+		}
+		return
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Members retrieved successfully", review)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/user/go-backend/internal/checks"
 	"github.com/user/go-backend/internal/models"
 	"github.com/user/go-backend/internal/repository"
 )
@@ -31,9 +34,14 @@ func NewProjectHandler(repo repository.ProjectRepository, logger *slog.Logger) *
 //	@Tags			gitlab
 //	@Accept			json
 //	@Produce		json
-//	@Param			limit	query		int	false	"Number of items to return (max 100)"	default(50)
-//	@Param			offset	query		int	false	"Number of items to skip"				default(0)
+//	@Param			limit		query		int		false	"Number of items to return (max 100)"	default(50)
+//	@Param			offset		query		int		false	"Number of items to skip"				default(0)
+//	@Param			instance	query		string	false	"Only projects on the named GitLab instance; every instance by default"
+//	@Param			ready	query		bool	false	"Only projects that pass (true) or fail (false) every check"
+//	@Param			failing	query		string	false	"Only projects failing the named check, e.g. codeowners_exists"
+//	@Param			code_owner	query		string	false	"Only projects whose CODEOWNERS file names the owner, e.g. @platform/leads"
 //	@Success		200		{object}	models.PaginatedResponse	"List of projects with pagination metadata"
+//	@Failure		400		{object}	models.ErrorResponse	"Invalid filter"
 //	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
 //	@Router			/gitlab/projects [get]
 func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
@@ -57,14 +65,20 @@ func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	projects, err := h.repo.List(ctx, limit, offset)
+	filter, err := parseProjectFilter(r)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	projects, err := h.repo.List(ctx, filter, limit, offset)
 	if err != nil {
 		h.logger.Error("failed to list projects", "error", err)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to retrieve projects")
 		return
 	}
 
-	total, err := h.repo.Count(ctx)
+	total, err := h.repo.Count(ctx, filter)
 	if err != nil {
 		h.logger.Error("failed to count projects", "error", err)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to count projects")
@@ -82,14 +96,14 @@ func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetProject handles GET /api/v1/projects/{id}
-// It returns a single project by ID
+// It returns a single project by ID, numeric GitLab ID or path
 //
 //	@Summary		Get project by ID
-//	@Description	Get a single project with all readiness check data
+//	@Description	Get a single project with all readiness check data and its GitLab metadata. The project is found by its project_id, or else by its numeric GitLab ID or full path (with the slashes encoded as %2F) as last seen by a scan.
 //	@Tags			gitlab
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string	true	"Project ID"
+//	@Param			id	path		string	true	"Project ID, numeric GitLab ID or URL-encoded full path"
 //	@Success		200	{object}	models.SuccessResponse	"Project details with readiness status"
 //	@Failure		400	{object}	models.ErrorResponse	"Bad request"
 //	@Failure		404	{object}	models.ErrorResponse	"Project ID not found"
@@ -97,20 +111,20 @@ func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
 //	@Router			/gitlab/projects/{id} [get]
 func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	projectID := chi.URLParam(r, "id")
+	key := projectKey(r)
 
-	if projectID == "" {
+	if key.ProjectID == "" {
 		h.respondWithError(w, http.StatusBadRequest, "Project ID is required")
 		return
 	}
 
-	project, err := h.repo.GetByID(ctx, projectID)
+	project, err := h.repo.GetByRef(ctx, key.Instance, key.ProjectID)
 	if err != nil {
 		if err.Error() == "project not found" {
 			h.respondWithError(w, http.StatusNotFound, "project_id not found")
 			return
 		}
-		h.logger.Error("failed to get project", "error", err, "project_id", projectID)
+		h.logger.Error("failed to get project", "error", err, "instance", key.Instance, "project_id", key.ProjectID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to retrieve project")
 		return
 	}
@@ -124,7 +138,7 @@ func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
 // It creates a new project
 //
 //	@Summary		Create a new project
-//	@Description	Create a new project with initial readiness checks
+//	@Description	Create a new project with initial readiness checks. Projects are on the default GitLab instance unless the body names another.
 //	@Tags			gitlab
 //	@Accept			json
 //	@Produce		json
@@ -148,19 +162,28 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existing, err := h.repo.GetByID(ctx, project.ProjectID)
+	if instance := chi.URLParam(r, "instance"); instance != "" {
+		if project.Instance != "" && project.Instance != instance {
+			h.respondWithError(w, http.StatusBadRequest, "Instance does not match the URL")
+			return
+		}
+		project.Instance = instance
+	}
+	project.Instance = project.Key().Instance
+
+	existing, err := h.repo.GetByID(ctx, project.Key())
 	if err == nil && existing != nil {
 		h.respondWithError(w, http.StatusConflict, "Project already exists")
 		return
 	}
 
 	if err := h.repo.Create(ctx, &project); err != nil {
-		h.logger.Error("failed to create project", "error", err, "project_id", project.ProjectID)
+		h.logger.Error("failed to create project", "error", err, "instance", project.Instance, "project_id", project.ProjectID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to create project")
 		return
 	}
 
-	h.logger.Info("project created", "project_id", project.ProjectID)
+	h.logger.Info("project created", "instance", project.Instance, "project_id", project.ProjectID)
 	response := models.NewSuccessResponse(http.StatusCreated, "Project created successfully", project)
 	h.respondWithJSON(w, http.StatusCreated, response)
 }
@@ -182,7 +205,7 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 //	@Router			/gitlab/projects/{id} [put]
 func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	projectID := chi.URLParam(r, "id")
+	projectID := pathParam(r, "id")
 
 	if projectID == "" {
 		h.respondWithError(w, http.StatusBadRequest, "Project ID is required")
@@ -195,19 +218,20 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	project.ProjectID = projectID
+	key := projectKey(r)
+	project.Instance, project.ProjectID = key.Instance, key.ProjectID
 
 	if err := h.repo.Update(ctx, &project); err != nil {
 		if err.Error() == "project not found" {
 			h.respondWithError(w, http.StatusNotFound, "project_id not found")
 			return
 		}
-		h.logger.Error("failed to update project", "error", err, "project_id", projectID)
+		h.logger.Error("failed to update project", "error", err, "instance", key.Instance, "project_id", projectID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to update project")
 		return
 	}
 
-	h.logger.Info("project updated", "project_id", projectID)
+	h.logger.Info("project updated", "instance", key.Instance, "project_id", projectID)
 	response := models.NewSuccessResponse(http.StatusOK, "Project updated successfully", project)
 	h.respondWithJSON(w, http.StatusOK, response)
 }
@@ -228,24 +252,25 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 //	@Router			/gitlab/projects/{id} [delete]
 func (h *ProjectHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	projectID := chi.URLParam(r, "id")
+	projectID := pathParam(r, "id")
 
 	if projectID == "" {
 		h.respondWithError(w, http.StatusBadRequest, "Project ID is required")
 		return
 	}
 
-	if err := h.repo.Delete(ctx, projectID); err != nil {
+	key := projectKey(r)
+	if err := h.repo.Delete(ctx, key); err != nil {
 		if err.Error() == "project not found" {
 			h.respondWithError(w, http.StatusNotFound, "project_id not found")
 			return
 		}
-		h.logger.Error("failed to delete project", "error", err, "project_id", projectID)
+		h.logger.Error("failed to delete project", "error", err, "instance", key.Instance, "project_id", projectID)
 		h.respondWithError(w, http.StatusInternalServerError, "Failed to delete project")
 		return
 	}
 
-	h.logger.Info("project deleted", "project_id", projectID)
+	h.logger.Info("project deleted", "instance", key.Instance, "project_id", projectID)
 	response := models.NewSuccessResponse(http.StatusNoContent, "Project deleted successfully", nil)
 	h.respondWithJSON(w, http.StatusNoContent, response)
 }
@@ -269,17 +294,65 @@ func (h *ProjectHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
+// projectKey returns the key of the project addressed by the request path.
+// Routes outside /gitlab/instances/{instance} address the default instance.
+func projectKey(r *http.Request) models.ProjectKey {
+	return models.NewProjectKey(chi.URLParam(r, "instance"), pathParam(r, "id"))
+}
+
+// pathParam returns a URL parameter decoded. chi matches routes against the
+// escaped path when it differs from the decoded one, as it does for project
+// paths sent with an encoded slash, and then returns parameters escaped.
+func pathParam(r *http.Request, name string) string {
+	value := chi.URLParam(r, name)
+	if r.URL.RawPath == "" {
+		return value
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// parseProjectFilter reads the listing filters from the query string. Routes
+// under /gitlab/instances/{instance} only list that instance's projects.
+func parseProjectFilter(r *http.Request) (repository.ProjectFilter, error) {
+	var filter repository.ProjectFilter
+
+	filter.Instance = chi.URLParam(r, "instance")
+	if v := r.URL.Query().Get("instance"); v != "" {
+		if filter.Instance != "" && v != filter.Instance {
+			return filter, fmt.Errorf("Instance does not match the URL")
+		}
+		filter.Instance = v
+	}
+
+	if v := r.URL.Query().Get("ready"); v != "" {
+		ready, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid ready parameter: must be true or false")
+		}
+		filter.Ready = &ready
+	}
+
+	if v := r.URL.Query().Get("failing"); v != "" {
+		if _, ok := checks.Lookup(v); !ok {
+			return filter, fmt.Errorf("Unknown check: %s", v)
+		}
+		filter.Failing = v
+	}
+
+	filter.CodeOwner = r.URL.Query().Get("code_owner")
+
+	return filter, nil
+}
+
 // Helper methods for consistent JSON responses
 
 func (h *ProjectHandler) respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		h.logger.Error("failed to encode response", "error", err)
-	}
+	respondWithJSON(w, h.logger, code, payload)
 }
 
 func (h *ProjectHandler) respondWithError(w http.ResponseWriter, code int, message string) {
-	response := models.NewErrorResponse(code, message)
-	h.respondWithJSON(w, code, response)
+	respondWithError(w, h.logger, code, message)
 }
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/user/go-backend/internal/report"
+	"github.com/user/go-backend/internal/repository"
+)
+
+type ReportHandler struct {
+	repo       repository.ProjectRepository
+	exemptions repository.ExemptionRepository
+	logger     *slog.Logger
+}
+
+func NewReportHandler(repo repository.ProjectRepository, exemptions repository.ExemptionRepository, logger *slog.Logger) *ReportHandler {
+	return &ReportHandler{
+		repo:       repo,
+		exemptions: exemptions,
+		logger:     logger,
+	}
+}
+
+// ProjectReport handles GET /api/v1/gitlab/projects/{id}/report
+// It renders the project's checks as a JUnit or SARIF report for CI artifacts
+//
+//	@Summary		Project readiness report
+//	@Description	Render each readiness check as a JUnit testcase or SARIF result so CI jobs can publish it as an artifact. Failing checks with an active exemption for the environment are reported as skipped/suppressed.
+//	@Tags			gitlab
+//	@Produce		xml
+//	@Produce		application/sarif+json
+//	@Param			id			path		string	true	"Project ID"
+//	@Param			format		query		string	false	"Report format"	Enums(junit, sarif)	default(junit)
+//	@Param			environment	query		string	false	"Environment whose exemptions apply"	default(production)
+//	@Success		200			{string}	string	"Report document"
+//	@Failure		400			{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404			{object}	models.ErrorResponse	"Project ID not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/projects/{id}/report [get]
+func (h *ReportHandler) ProjectReport(w http.ResponseWriter, r *http.Request) {
+	format := report.FormatJUnit
+	if v := r.URL.Query().Get("format"); v != "" {
+		var err error
+		if format, err = report.ParseFormat(v); err != nil {
+			respondWithError(w, h.logger, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.render(w, r, format)
+}
+
+// Negotiate serves a report instead of next when the Accept header asks for
+// JUnit XML or SARIF, so GET /projects/{id} works directly as an artifact URL
+func (h *ReportHandler) Negotiate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if format, ok := report.FormatForAccept(r.Header.Get("Accept")); ok {
+			h.render(w, r, format)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *ReportHandler) render(w http.ResponseWriter, r *http.Request, format report.Format) {
+	ctx := r.Context()
+	key := projectKey(r)
+	projectID := key.ProjectID
+
+	environment := r.URL.Query().Get("environment")
+	if environment == "" {
+		environment = defaultGateEnvironment
+	}
+
+	// Reports are also served for GET /gitlab/projects/{id}, which accepts
+	// GitLab IDs and paths
+	project, err := h.repo.GetByRef(ctx, key.Instance, key.ProjectID)
+	if err != nil {
+		if err.Error() == "project not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "project_id not found")
+			return
+		}
+		h.logger.Error("failed to get project", "error", err, "instance", key.Instance, "project_id", projectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve project")
+		return
+	}
+	key = project.Key()
+
+	exemptions, err := h.exemptions.ListByProject(ctx, key)
+	if err != nil {
+		h.logger.Error("failed to list exemptions", "error", err, "instance", key.Instance, "project_id", projectID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve exemptions")
+		return
+	}
+
+	active := activeExemptions(exemptions, environment, time.Now())
+
+	// Render into a buffer so encoding errors can still produce a JSON error
+	var buf bytes.Buffer
+	if err := report.Write(&buf, format, project, active); err != nil {
+		h.logger.Error("failed to render report", "error", err, "project_id", projectID, "format", format)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to render report")
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+format.Filename(projectID)+`"`)
+	w.Header().Set("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.logger.Error("failed to write report", "error", err, "project_id", projectID)
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// respondWithJSON writes payload as the JSON response body
+func respondWithJSON(w http.ResponseWriter, logger *slog.Logger, code int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// respondWithError writes a models.ErrorResponse with the given status
+func respondWithError(w http.ResponseWriter, logger *slog.Logger, code int, message string) {
+	response := models.NewErrorResponse(code, message)
+	respondWithJSON(w, logger, code, response)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+)
+
+// Scanner re-evaluates a stored project's checks against GitLab
+type Scanner interface {
+	Scan(ctx context.Context, key models.ProjectKey) (*models.Project, error)
+}
+
+type ScanHandler struct {
+	scanner Scanner
+	logger  *slog.Logger
+}
+
+// NewScanHandler creates a handler for on-demand scans. scanner may be nil
+// when no GitLab connection is configured, in which case scans return 503.
+func NewScanHandler(scanner Scanner, logger *slog.Logger) *ScanHandler {
+	return &ScanHandler{
+		scanner: scanner,
+		logger:  logger,
+	}
+}
+
+// ScanProject handles POST /api/v1/gitlab/projects/{id}/scan
+// It re-evaluates every readiness check against GitLab and stores the result
+//
+//	@Summary		Rescan project
+//	@Description	Synchronously re-evaluate a project's readiness checks against GitLab
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Project ID"
+//	@Success		200	{object}	models.SuccessResponse	"Rescanned project"
+//	@Failure		404	{object}	models.ErrorResponse	"Project ID not found"
+//	@Failure		502	{object}	models.ErrorResponse	"GitLab request failed"
+//	@Failure		503	{object}	models.ErrorResponse	"Scanning is not configured for the project's instance"
+//	@Router			/gitlab/projects/{id}/scan [post]
+func (h *ScanHandler) ScanProject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := pathParam(r, "id")
+
+	if h.scanner == nil {
+		respondWithError(w, h.logger, http.StatusServiceUnavailable, "Scanning is not configured")
+		return
+	}
+
+	key := projectKey(r)
+	project, err := h.scanner.Scan(ctx, key)
+	if err != nil {
+		if err.Error() == "project not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "project_id not found")
+			return
+		}
+		if errors.Is(err, gitlab.ErrUnknownInstance) {
+			respondWithError(w, h.logger, http.StatusServiceUnavailable, "Scanning is not configured for instance "+key.Instance)
+			return
+		}
+		h.logger.Error("failed to scan project", "error", err, "instance", key.Instance, "project_id", projectID)
+		respondWithError(w, h.logger, http.StatusBadGateway, "Failed to scan project")
+		return
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Project scanned successfully", project)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
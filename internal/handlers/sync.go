@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+// SyncTrigger starts a group sync in the background
+type SyncTrigger interface {
+	Trigger() bool
+}
+
+type SyncHandler struct {
+	repo     repository.SyncRepository
+	triggers map[string]SyncTrigger
+	logger   *slog.Logger
+}
+
+// NewSyncHandler creates the group sync report handler. triggers holds the
+// syncer of each GitLab instance with groups configured; starting a sync of
+// any other instance returns 503.
+func NewSyncHandler(repo repository.SyncRepository, triggers map[string]SyncTrigger, logger *slog.Logger) *SyncHandler {
+	return &SyncHandler{
+		repo:     repo,
+		triggers: triggers,
+		logger:   logger,
+	}
+}
+
+// syncInstance returns the instance named by the instance query parameter,
+// or the default instance
+func syncInstance(r *http.Request) string {
+	return models.NewProjectKey(r.URL.Query().Get("instance"), "").Instance
+}
+
+// ListSyncRuns handles GET /api/v1/gitlab/sync
+//
+//	@Summary		List group sync runs
+//	@Description	Get the reports of GitLab group syncs, newest first: projects registered, projects marked absent and groups that could not be listed
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit		query		int		false	"Number of items to return (max 100)"	default(50)
+//	@Param			offset		query		int		false	"Number of items to skip"				default(0)
+//	@Param			instance	query		string	false	"Only runs syncing the named GitLab instance; every instance by default"
+//	@Success		200		{object}	models.PaginatedResponse{data=[]models.GroupSyncRun}	"Sync runs with pagination metadata"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/sync [get]
+func (h *SyncHandler) ListSyncRuns(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := 50
+	offset := 0
+
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 {
+			limit = min(parsedLimit, 100)
+		}
+	}
+
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if parsedOffset, err := strconv.Atoi(o); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	instance := r.URL.Query().Get("instance")
+
+	runs, err := h.repo.ListRuns(ctx, instance, limit, offset)
+	if err != nil {
+		h.logger.Error("failed to list sync runs", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve sync runs")
+		return
+	}
+	if runs == nil {
+		runs = []*models.GroupSyncRun{}
+	}
+
+	total, err := h.repo.CountRuns(ctx, instance)
+	if err != nil {
+		h.logger.Error("failed to count sync runs", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to count sync runs")
+		return
+	}
+
+	pagination := &models.PaginationMeta{
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	}
+	response := models.NewPaginatedResponse(http.StatusOK, "Sync runs retrieved successfully", runs, pagination)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// LatestSyncRun handles GET /api/v1/gitlab/sync/latest
+//
+//	@Summary		Get latest group sync run
+//	@Description	Get the report of the most recent group sync of a GitLab instance, which is still running when it has no finished_at
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			instance	query		string	false	"GitLab instance"	default(default)
+//	@Success		200	{object}	models.SuccessResponse{data=models.GroupSyncRun}	"Latest sync run"
+//	@Failure		404	{object}	models.ErrorResponse	"No sync has run"
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/gitlab/sync/latest [get]
+func (h *SyncHandler) LatestSyncRun(w http.ResponseWriter, r *http.Request) {
+	run, err := h.repo.LatestRun(r.Context(), syncInstance(r))
+	if err != nil {
+		if err.Error() == "sync run not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "No group sync has run")
+			return
+		}
+		h.logger.Error("failed to get latest sync run", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve sync run")
+		return
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Sync run retrieved successfully", run)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// TriggerSync handles POST /api/v1/gitlab/sync
+//
+//	@Summary		Start group sync
+//	@Description	Sync the configured groups of a GitLab instance now instead of waiting for the schedule. The sync runs in the background; follow it with GET /gitlab/sync/latest.
+//	@Tags			gitlab
+//	@Accept			json
+//	@Produce		json
+//	@Param			instance	query		string	false	"GitLab instance"	default(default)
+//	@Success		202	{object}	models.SuccessResponse	"Sync queued"
+//	@Failure		503	{object}	models.ErrorResponse	"Group sync is not configured"
+//	@Router			/gitlab/sync [post]
+func (h *SyncHandler) TriggerSync(w http.ResponseWriter, r *http.Request) {
+	trigger, ok := h.triggers[syncInstance(r)]
+	if !ok {
+		respondWithError(w, h.logger, http.StatusServiceUnavailable, "Group sync is not configured")
+		return
+	}
+
+	message := "Sync queued"
+	if !trigger.Trigger() {
+		message = "Sync already queued"
+	}
+
+	response := models.NewSuccessResponse(http.StatusAccepted, message, nil)
+	respondWithJSON(w, h.logger, http.StatusAccepted, response)
+}
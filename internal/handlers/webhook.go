@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+// Redeliverer queues another attempt at a webhook delivery
+type Redeliverer interface {
+	Redeliver(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+}
+
+type WebhookHandler struct {
+	repo       repository.WebhookRepository
+	dispatcher Redeliverer
+	logger     *slog.Logger
+}
+
+func NewWebhookHandler(repo repository.WebhookRepository, dispatcher Redeliverer, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		repo:       repo,
+		dispatcher: dispatcher,
+		logger:     logger,
+	}
+}
+
+// CreateWebhook handles POST /api/v1/webhooks
+//
+//	@Summary		Create webhook
+//	@Description	Subscribe a URL to readiness events. Deliveries are signed with HMAC-SHA256 in X-Readiness-Signature; the secret is generated when omitted and only returned here.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhook	body		models.WebhookSubscriptionRequest	true	"Subscription; an empty events list receives every event"
+//	@Success		201		{object}	models.SuccessResponse{data=models.WebhookSubscription}	"Created webhook, including its secret"
+//	@Failure		400		{object}	models.ErrorResponse	"Bad request"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req models.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validateWebhookRequest(&req); err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	subscription := &models.WebhookSubscription{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		Events:      req.Events,
+		Active:      req.Active == nil || *req.Active,
+		Description: req.Description,
+	}
+	if subscription.Secret == "" {
+		subscription.Secret = newWebhookSecret()
+	}
+
+	if err := h.repo.CreateSubscription(r.Context(), subscription); err != nil {
+		h.logger.Error("failed to create webhook", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to create webhook")
+		return
+	}
+
+	h.logger.Info("webhook created", "webhook_id", subscription.ID, "events", subscription.Events)
+	response := models.NewSuccessResponse(http.StatusCreated, "Webhook created successfully", subscription)
+	respondWithJSON(w, h.logger, http.StatusCreated, response)
+}
+
+// ListWebhooks handles GET /api/v1/webhooks
+//
+//	@Summary		List webhooks
+//	@Description	List every webhook subscription. Secrets are not returned.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	models.SuccessResponse{data=[]models.WebhookSubscription}	"Webhooks"
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.repo.ListSubscriptions(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list webhooks", "error", err)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve webhooks")
+		return
+	}
+	if subscriptions == nil {
+		subscriptions = []*models.WebhookSubscription{}
+	}
+	for _, subscription := range subscriptions {
+		subscription.Secret = ""
+	}
+
+	response := models.NewSuccessResponse(http.StatusOK, "Webhooks retrieved successfully", subscriptions)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// GetWebhook handles GET /api/v1/webhooks/{id}
+//
+//	@Summary		Get webhook
+//	@Description	Get a webhook subscription. The secret is not returned.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Webhook ID"
+//	@Success		200	{object}	models.SuccessResponse{data=models.WebhookSubscription}	"Webhook"
+//	@Failure		400	{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404	{object}	models.ErrorResponse	"Webhook not found"
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	subscription, ok := h.subscription(w, r)
+	if !ok {
+		return
+	}
+	subscription.Secret = ""
+
+	response := models.NewSuccessResponse(http.StatusOK, "Webhook retrieved successfully", subscription)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// UpdateWebhook handles PUT /api/v1/webhooks/{id}
+//
+//	@Summary		Update webhook
+//	@Description	Replace a webhook's URL, events, state and description. Sending a secret rotates it; otherwise the current one is kept.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int									true	"Webhook ID"
+//	@Param			webhook	body		models.WebhookSubscriptionRequest	true	"Subscription"
+//	@Success		200		{object}	models.SuccessResponse{data=models.WebhookSubscription}	"Updated webhook"
+//	@Failure		400		{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404		{object}	models.ErrorResponse	"Webhook not found"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req models.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validateWebhookRequest(&req); err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	subscription, ok := h.subscription(w, r)
+	if !ok {
+		return
+	}
+
+	subscription.URL = req.URL
+	subscription.Events = req.Events
+	subscription.Active = req.Active == nil || *req.Active
+	subscription.Description = req.Description
+	rotated := req.Secret != ""
+	if rotated {
+		subscription.Secret = req.Secret
+	}
+
+	if err := h.repo.UpdateSubscription(r.Context(), subscription); err != nil {
+		if err.Error() == "webhook not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		h.logger.Error("failed to update webhook", "error", err, "webhook_id", subscription.ID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to update webhook")
+		return
+	}
+	if !rotated {
+		subscription.Secret = ""
+	}
+
+	h.logger.Info("webhook updated", "webhook_id", subscription.ID, "secret_rotated", rotated)
+	response := models.NewSuccessResponse(http.StatusOK, "Webhook updated successfully", subscription)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// DeleteWebhook handles DELETE /api/v1/webhooks/{id}
+//
+//	@Summary		Delete webhook
+//	@Description	Remove a webhook subscription and its delivery log
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"Webhook ID"
+//	@Success		204	{object}	models.SuccessResponse	"Webhook deleted successfully"
+//	@Failure		400	{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404	{object}	models.ErrorResponse	"Webhook not found"
+//	@Failure		500	{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteSubscription(r.Context(), id); err != nil {
+		if err.Error() == "webhook not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "Webhook not found")
+			return
+		}
+		h.logger.Error("failed to delete webhook", "error", err, "webhook_id", id)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to delete webhook")
+		return
+	}
+
+	h.logger.Info("webhook deleted", "webhook_id", id)
+	response := models.NewSuccessResponse(http.StatusNoContent, "Webhook deleted successfully", nil)
+	respondWithJSON(w, h.logger, http.StatusNoContent, response)
+}
+
+// ListDeliveries handles GET /api/v1/webhooks/{id}/deliveries
+//
+//	@Summary		List webhook deliveries
+//	@Description	Get a webhook's delivery log, newest first, with the outcome of each delivery's latest attempt
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"Webhook ID"
+//	@Param			status	query		string	false	"Only deliveries in this state"	Enums(pending, succeeded, failed)
+//	@Param			limit	query		int		false	"Number of items to return (max 100)"	default(50)
+//	@Param			offset	query		int		false	"Number of items to skip"				default(0)
+//	@Success		200		{object}	models.PaginatedResponse{data=[]models.WebhookDelivery}	"Deliveries with pagination metadata"
+//	@Failure		400		{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404		{object}	models.ErrorResponse	"Webhook not found"
+//	@Failure		500		{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := 50
+	offset := 0
+
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsedLimit, err := strconv.Atoi(l); err == nil && parsedLimit > 0 {
+			limit = min(parsedLimit, 100)
+		}
+	}
+
+	if o := r.URL.Query().Get("offset"); o != "" {
+		if parsedOffset, err := strconv.Atoi(o); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryFailed:
+	default:
+		respondWithError(w, h.logger, http.StatusBadRequest, "status must be pending, succeeded or failed")
+		return
+	}
+
+	subscription, ok := h.subscription(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.repo.ListDeliveries(ctx, subscription.ID, status, limit, offset)
+	if err != nil {
+		h.logger.Error("failed to list deliveries", "error", err, "webhook_id", subscription.ID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve deliveries")
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+
+	total, err := h.repo.CountDeliveries(ctx, subscription.ID, status)
+	if err != nil {
+		h.logger.Error("failed to count deliveries", "error", err, "webhook_id", subscription.ID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to count deliveries")
+		return
+	}
+
+	pagination := &models.PaginationMeta{
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	}
+	response := models.NewPaginatedResponse(http.StatusOK, "Deliveries retrieved successfully", deliveries, pagination)
+	respondWithJSON(w, h.logger, http.StatusOK, response)
+}
+
+// Redeliver handles POST /api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver
+//
+//	@Summary		Redeliver webhook event
+//	@Description	Queue a new delivery of the same event, e.g. after fixing a receiver. The original delivery stays in the log.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int	true	"Webhook ID"
+//	@Param			deliveryID	path		int	true	"Delivery ID"
+//	@Success		202			{object}	models.SuccessResponse{data=models.WebhookDelivery}	"Queued delivery"
+//	@Failure		400			{object}	models.ErrorResponse	"Bad request"
+//	@Failure		404			{object}	models.ErrorResponse	"Delivery not found"
+//	@Failure		500			{object}	models.ErrorResponse	"Internal server error"
+//	@Router			/webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+	if err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.repo.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		if err.Error() == "delivery not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "Delivery not found")
+			return
+		}
+		h.logger.Error("failed to get delivery", "error", err, "webhook_id", id, "delivery_id", deliveryID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve delivery")
+		return
+	}
+
+	redelivery, err := h.dispatcher.Redeliver(ctx, delivery)
+	if err != nil {
+		h.logger.Error("failed to redeliver", "error", err, "webhook_id", id, "delivery_id", deliveryID)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to queue redelivery")
+		return
+	}
+
+	h.logger.Info("webhook redelivery queued", "webhook_id", id, "delivery_id", deliveryID, "redelivery_id", redelivery.ID)
+	response := models.NewSuccessResponse(http.StatusAccepted, "Redelivery queued", redelivery)
+	respondWithJSON(w, h.logger, http.StatusAccepted, response)
+}
+
+// webhookID parses the {id} URL parameter, writing a 400 response when it
+// is not a number
+func (h *WebhookHandler) webhookID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondWithError(w, h.logger, http.StatusBadRequest, "Invalid webhook ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// subscription loads the webhook named by the {id} URL parameter, writing
+// an error response when it cannot
+func (h *WebhookHandler) subscription(w http.ResponseWriter, r *http.Request) (*models.WebhookSubscription, bool) {
+	id, ok := h.webhookID(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	subscription, err := h.repo.GetSubscription(r.Context(), id)
+	if err != nil {
+		if err.Error() == "webhook not found" {
+			respondWithError(w, h.logger, http.StatusNotFound, "Webhook not found")
+			return nil, false
+		}
+		h.logger.Error("failed to get webhook", "error", err, "webhook_id", id)
+		respondWithError(w, h.logger, http.StatusInternalServerError, "Failed to retrieve webhook")
+		return nil, false
+	}
+	return subscription, true
+}
+
+// validateWebhookRequest checks the URL and event types, dropping duplicate
+// events so the stored filter stays tidy
+func validateWebhookRequest(req *models.WebhookSubscriptionRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+
+	for _, event := range req.Events {
+		if !slices.Contains(models.EventTypes, event) {
+			return fmt.Errorf("unknown event type: %s", event)
+		}
+	}
+	slices.Sort(req.Events)
+	req.Events = slices.Compact(req.Events)
+	if req.Events == nil {
+		req.Events = []string{}
+	}
+	return nil
+}
+
+// newWebhookSecret returns a random 256-bit secret
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return "whsec_" + hex.EncodeToString(b)
+}
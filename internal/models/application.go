@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Application is a registered application APP_NAME values are expected to
+// name
+type Application struct {
+	Name        string    `json:"name" example:"payments"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ApplicationImportSummary reports a replacement of the application list
+type ApplicationImportSummary struct {
+	Applications int `json:"applications"` // Applications now registered
+}
+
+// ProjectRef identifies a project in a report
+type ProjectRef struct {
+	Instance          string `json:"instance" example:"default"`
+	ProjectID         string `json:"project_id"`
+	PathWithNamespace string `json:"path_with_namespace,omitempty" example:"platform/payments-api"`
+}
+
+// NewProjectRef returns the reference to a project
+func NewProjectRef(p *Project) ProjectRef {
+	return ProjectRef{Instance: p.Key().Instance, ProjectID: p.ProjectID, PathWithNamespace: p.PathWithNamespace}
+}
+
+// DuplicateMoabID is a MOAB ID set by more than one project
+type DuplicateMoabID struct {
+	MoabID   string       `json:"moab_id" example:"MOAB-123456"`
+	Projects []ProjectRef `json:"projects"`
+}
+
+// UnregisteredAppName is a project whose APP_NAME names no registered
+// application
+type UnregisteredAppName struct {
+	ProjectRef
+	AppName string `json:"app_name" example:"paymnets"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// Batch operation kinds
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchUpsert = "upsert"
+	BatchDelete = "delete"
+)
+
+// Batch modes
+const (
+	BatchAtomic     = "atomic"      // All operations succeed or none are applied
+	BatchBestEffort = "best_effort" // Each operation succeeds or fails on its own
+)
+
+// BatchRequest is the body of POST /gitlab/projects:batch
+type BatchRequest struct {
+	Mode       string           `json:"mode" enums:"atomic,best_effort" default:"atomic"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is one write in a batch. Delete needs only ProjectID, and
+// Instance unless the project is on the default instance; the others need
+// Project, whose key is used when these are empty.
+type BatchOperation struct {
+	Op        string   `json:"op" enums:"create,update,upsert,delete"`
+	Instance  string   `json:"instance,omitempty"`
+	ProjectID string   `json:"project_id,omitempty"`
+	Project   *Project `json:"project,omitempty"`
+}
+
+// BatchResult reports the outcome of every operation in a batch, in request order
+type BatchResult struct {
+	Mode      string            `json:"mode"`
+	Committed bool              `json:"committed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult carries the HTTP status the operation would have received
+// as a standalone request. Operations rolled back because another one failed
+// report 424 Failed Dependency.
+type BatchItemResult struct {
+	Index     int    `json:"index"`
+	Op        string `json:"op"`
+	Instance  string `json:"instance,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
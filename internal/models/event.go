@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Event types published when projects change
+const (
+	EventProjectCreated   = "project.created"
+	EventProjectUpdated   = "project.updated" // Any check result changed
+	EventProjectDeleted   = "project.deleted"
+	EventProjectReady     = "project.ready"     // Now passes every check after failing one
+	EventProjectRegressed = "project.regressed" // A previously passing check now fails
+	EventCheckChanged     = "check.changed"     // One per check whose result changed
+	EventScanCompleted    = "scan.completed"    // A rescan finished, whether or not any result changed
+)
+
+// EventTypes lists every event type, for validating subscriptions
+var EventTypes = []string{
+	EventProjectCreated,
+	EventProjectUpdated,
+	EventProjectDeleted,
+	EventProjectReady,
+	EventProjectRegressed,
+	EventCheckChanged,
+	EventScanCompleted,
+}
+
+// Event describes a change to a project
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Instance   string    `json:"instance"`
+	ProjectID  string    `json:"project_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       EventData `json:"data"`
+
+	// Sequence orders events as they were published, across instances. It
+	// is assigned when the event leaves the outbox and is the ID of
+	// server-sent events.
+	Sequence int64 `json:"sequence,omitempty"`
+}
+
+// EventData carries the project after the change (before it, for deletes)
+// and the check results that changed
+type EventData struct {
+	Project *Project      `json:"project"`
+	Changes []CheckChange `json:"changes,omitempty"`
+}
+
+// CheckChange is a check whose result differs between two versions of a project
+type CheckChange struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Passed   bool   `json:"passed"`
+	Previous bool   `json:"previous"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// Exemption waives a readiness check for a project. An empty Environment
+// applies to every environment; a nil ExpiresAt never expires.
+type Exemption struct {
+	ID          int64      `json:"id" db:"id"`
+	Instance    string     `json:"instance" db:"instance"`
+	ProjectID   string     `json:"project_id" db:"project_id"`
+	CheckName   string     `json:"check_name" db:"check_name"`
+	Environment string     `json:"environment,omitempty" db:"environment"`
+	Reason      string     `json:"reason" db:"reason"`
+	CreatedBy   string     `json:"created_by,omitempty" db:"created_by"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ActiveFor reports whether the exemption applies to the environment at the given time
+func (e *Exemption) ActiveFor(environment string, at time.Time) bool {
+	if e.Environment != "" && e.Environment != environment {
+		return false
+	}
+	return e.ExpiresAt == nil || e.ExpiresAt.After(at)
+}
+
+// GateResult answers whether a project may deploy to an environment
+type GateResult struct {
+	Instance    string `json:"instance"`
+	ProjectID   string `json:"project_id"`
+	Environment string `json:"environment"`
+	Passed      bool   `json:"passed"`
+
+	FailingChecks  []CheckResult `json:"failing_checks"`
+	ExemptedChecks []CheckResult `json:"exempted_checks"`
+	Exemptions     []*Exemption  `json:"exemptions"`
+
+	Freshness Freshness `json:"freshness"`
+}
+
+// Freshness describes how current the check data behind a gate decision is.
+// UpdatedAt is when the project was last scanned in full, and is zero, with
+// no age, if it never was.
+type Freshness struct {
+	UpdatedAt     time.Time `json:"updated_at"`
+	AgeSeconds    int64     `json:"age_seconds"`
+	MaxAgeSeconds int64     `json:"max_age_seconds,omitempty"`
+	Stale         bool      `json:"stale"`
+	Rescanned     bool      `json:"rescanned"`
+	ScanError     string    `json:"scan_error,omitempty"`
+}
@@ -0,0 +1,11 @@
+package models
+
+// GitLabHookResult reports what was done with a GitLab hook event
+type GitLabHookResult struct {
+	Event     string   `json:"event"`                // e.g. push or project_update
+	Action    string   `json:"action"`               // ignore, rescan, create or remove
+	Instance  string   `json:"instance"`             // The instance the hook was received for
+	ProjectID string   `json:"project_id,omitempty"` // The stored project acted on
+	Checks    []string `json:"checks,omitempty"`     // Checks queued for rescan; empty means every check
+	Reason    string   `json:"reason,omitempty"`     // Why the event was ignored
+}
@@ -0,0 +1,13 @@
+package models
+
+// GitLabInstance describes a configured GitLab instance. Tokens and hook
+// secrets are never exposed, only whether they are set.
+type GitLabInstance struct {
+	Name       string   `json:"name" example:"default"`
+	URL        string   `json:"url" example:"https://gitlab.com"`
+	RateLimit  float64  `json:"requests_per_second"` // 0 means unthrottled
+	Burst      int      `json:"burst"`
+	Scanning   bool     `json:"scanning"` // A token is configured
+	Hooks      bool     `json:"hooks"`    // A hook secret is configured
+	SyncGroups []string `json:"sync_groups"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"net/http"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a write request sent with an
+// Idempotency-Key header. A zero StatusCode means the original request is
+// still in flight.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Completed reports whether the original request has finished
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
@@ -0,0 +1,24 @@
+package models
+
+// ImportSummary reports the outcome of a bulk project import
+type ImportSummary struct {
+	Mode       string `json:"mode"`        // atomic or partial
+	OnConflict string `json:"on_conflict"` // skip, update or fail
+	Committed  bool   `json:"committed"`   // False when an atomic import was rolled back
+
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+
+	Errors []ImportRowError `json:"errors"`
+}
+
+// ImportRowError describes why a row of an import was rejected. Line is the
+// 1-based line in the uploaded file.
+type ImportRowError struct {
+	Line      int    `json:"line"`
+	ProjectID string `json:"project_id,omitempty"`
+	Error     string `json:"error"`
+}
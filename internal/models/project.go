@@ -1,32 +1,404 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
 	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
 )
 
+// DefaultInstance is the GitLab instance of projects that do not name one,
+// including every project registered before instances were introduced
+const DefaultInstance = "default"
+
+// Project is a registered project and the outcome of its readiness checks.
+// Its JSON holds one boolean per check listed by GET /api/v1/checks, named
+// by check ID, an evaluations object detailing each check and a scores list
+// giving the share of each category's checks passed, alongside the fields
+// below.
 type Project struct {
+	// GitLab project IDs are only unique within an instance, so projects are
+	// identified by both
+	Instance  string `json:"instance" db:"instance" example:"default"`
 	ProjectID string `json:"project_id" db:"project_id"`
 
-	// GitLab presence checks
-	ProjectPresent   bool `json:"project_present" db:"project_present"`
-	AppNameSet       bool `json:"app_name_set" db:"app_name_set"`
-	MoabIDSet        bool `json:"moab_id_set" db:"moab_id_set"`
-	CodeownersExists bool `json:"codeowners_exists" db:"codeowners_exists"`
-
-	// Branch protection checks
-	BranchProtectionEnabled   bool `json:"branch_protection_enabled" db:"branch_protection_enabled"`
-	CodeownerApprovalRequired bool `json:"codeowner_approval_required" db:"codeowner_approval_required"`
-	PushMergeRestricted       bool `json:"push_merge_restricted" db:"push_merge_restricted"`
-	ForcePushDisabled         bool `json:"force_push_disabled" db:"force_push_disabled"`
-
-	// Merge request checks
-	PushRulesEnabled           bool `json:"push_rules_enabled" db:"push_rules_enabled"`
-	MinApprovalsRequired       bool `json:"min_approvals_required" db:"min_approvals_required"`
-	AuthorApprovalPrevented    bool `json:"author_approval_prevented" db:"author_approval_prevented"`
-	CommitterApprovalPrevented bool `json:"committer_approval_prevented" db:"committer_approval_prevented"`
-	ApprovalsRemovedOnCommit   bool `json:"approvals_removed_on_commit" db:"approvals_removed_on_commit"`
+	// Profile names the readiness profile whose thresholds scans hold the
+	// project to. Empty is the default profile on create and keeps the
+	// stored profile on update.
+	Profile string `json:"profile,omitempty" db:"profile" example:"tier-1"`
+
+	// Results holds whether each check passed, by check ID. Checks missing
+	// from it have not passed.
+	Results map[string]bool `json:"-" db:"-"`
+
+	// Evaluations details the scanned outcome of each check, by check ID.
+	// An evaluation disagreeing with Results, as when a result is set by
+	// hand, is superseded by it.
+	Evaluations map[string]Evaluation `json:"-" db:"-"`
+
+	// CodeOwners lists the owners the project's CODEOWNERS file names, as
+	// its last scan found them. Nil keeps the stored list on update.
+	CodeOwners []string `json:"code_owners,omitempty" db:"code_owners" example:"@platform/leads"`
 
 	// Metadata
+	ProjectMetadata
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// LastScannedAt is when a scan last evaluated every check, or nil if
+	// none has. Other writes, including rescans of some checks, keep it.
+	LastScannedAt *time.Time `json:"last_scanned_at,omitempty" db:"last_scanned_at"`
+}
+
+// Evaluation is the detailed outcome of one check for a project
+type Evaluation struct {
+	Status      checks.Status   `json:"status" enums:"pass,fail,error,unknown,not_applicable" example:"fail"`
+	Reason      string          `json:"reason,omitempty" example:"Code owner approval is not required on main"`
+	Evidence    json.RawMessage `json:"evidence,omitempty" swaggertype:"object"`  // The GitLab data the outcome was decided on
+	Value       *int            `json:"value,omitempty" example:"2"`              // What a measured check counted, in the check's unit
+	Captured    string          `json:"captured,omitempty" example:"MOAB-123456"` // The value a capturing check read
+	EvaluatedAt *time.Time      `json:"evaluated_at,omitempty"`                   // Unset for results not set by a scan
+}
+
+// ProjectMetadata describes a project as GitLab reports it. Scans refresh
+// it; it is empty until the project is first found, and kept as it was when
+// the project is no longer found.
+type ProjectMetadata struct {
+	GitLabID          int    `json:"gitlab_id,omitempty" db:"gitlab_id" example:"42"`
+	Name              string `json:"name,omitempty" db:"name"`
+	PathWithNamespace string `json:"path_with_namespace,omitempty" db:"path_with_namespace" example:"platform/payments-api"`
+	DefaultBranch     string `json:"default_branch,omitempty" db:"default_branch" example:"main"`
+	WebURL            string `json:"web_url,omitempty" db:"web_url"`
+	Visibility        string `json:"visibility,omitempty" db:"visibility" example:"private"`
+	Archived          bool   `json:"archived" db:"archived"`
+}
+
+// NewProjectMetadata returns the metadata of a project GitLab reported
+func NewProjectMetadata(p *gitlab.Project) ProjectMetadata {
+	return ProjectMetadata{
+		GitLabID:          p.ID,
+		Name:              p.Name,
+		PathWithNamespace: p.PathWithNamespace,
+		DefaultBranch:     p.DefaultBranch,
+		WebURL:            p.WebURL,
+		Visibility:        p.Visibility,
+		Archived:          p.Archived,
+	}
+}
+
+// IsZero reports whether no metadata is known
+func (m ProjectMetadata) IsZero() bool {
+	return m == ProjectMetadata{}
+}
+
+// ProjectKey identifies a stored project
+type ProjectKey struct {
+	Instance  string
+	ProjectID string
+}
+
+// NewProjectKey returns the key of a project, on the default instance when
+// instance is empty
+func NewProjectKey(instance, projectID string) ProjectKey {
+	if instance == "" {
+		instance = DefaultInstance
+	}
+	return ProjectKey{Instance: instance, ProjectID: projectID}
+}
+
+// Key returns the project's key
+func (p *Project) Key() ProjectKey {
+	return NewProjectKey(p.Instance, p.ProjectID)
+}
+
+// Clone returns a copy of the project that shares no results with it
+func (p *Project) Clone() *Project {
+	c := *p
+	c.Results = maps.Clone(p.Results)
+	c.Evaluations = maps.Clone(p.Evaluations)
+	c.CodeOwners = slices.Clone(p.CodeOwners)
+	return &c
+}
+
+// Result reports whether the project passed the check with the ID
+func (p *Project) Result(id string) bool {
+	return p.Results[id]
+}
+
+// SetCheck records the result of the check with the ID, dropping its
+// evaluation, and reports false for an unregistered check
+func (p *Project) SetCheck(id string, passed bool) bool {
+	if _, ok := checks.Lookup(id); !ok {
+		return false
+	}
+	if p.Results == nil {
+		p.Results = make(map[string]bool)
+	}
+	p.Results[id] = passed
+	delete(p.Evaluations, id)
+	return true
+}
+
+// SetEvaluation records a scanned outcome of the check with the ID and the
+// result it implies
+func (p *Project) SetEvaluation(id string, e Evaluation) {
+	if p.Results == nil {
+		p.Results = make(map[string]bool)
+	}
+	if p.Evaluations == nil {
+		p.Evaluations = make(map[string]Evaluation)
+	}
+	p.Results[id] = e.Status.Passing()
+	p.Evaluations[id] = e
+}
+
+// KeepResults replaces the results and evaluations of every check but the
+// named ones with those of stored, for a rescan that evaluated only the
+// named checks. No names keeps none.
+func (p *Project) KeepResults(stored *Project, scanned []string) {
+	if len(scanned) == 0 {
+		return
+	}
+	for _, id := range checks.IDs() {
+		if slices.Contains(scanned, id) {
+			continue
+		}
+		delete(p.Results, id)
+		delete(p.Evaluations, id)
+		if passed, ok := stored.Results[id]; ok {
+			p.SetCheck(id, passed)
+		}
+		if e, ok := stored.Evaluations[id]; ok {
+			if p.Evaluations == nil {
+				p.Evaluations = make(map[string]Evaluation)
+			}
+			p.Evaluations[id] = e
+		}
+	}
+}
+
+// Evaluation returns the detailed outcome of the check with the ID. Results
+// without an agreeing evaluation are reported as a bare pass or fail, and
+// checks with neither as unknown.
+func (p *Project) Evaluation(id string) Evaluation {
+	passed, ok := p.Results[id]
+	if e, evaluated := p.Evaluations[id]; evaluated && e.Status.Passing() == passed {
+		return e
+	}
+	switch {
+	case !ok:
+		return Evaluation{Status: checks.StatusUnknown}
+	case passed:
+		return Evaluation{Status: checks.StatusPass}
+	default:
+		return Evaluation{Status: checks.StatusFail}
+	}
+}
+
+type CheckResult struct {
+	Name        string          `json:"name"`
+	Category    string          `json:"category"`
+	Description string          `json:"description"`
+	Severity    checks.Severity `json:"severity"`
+	Status      checks.Status   `json:"status"`
+	Reason      string          `json:"reason,omitempty"`
+	Value       *int            `json:"value,omitempty"`
+	Passed      bool            `json:"passed"`
+}
+
+// Checks evaluates every registered check against the project
+func (p *Project) Checks() []CheckResult {
+	defs := checks.Definitions()
+	results := make([]CheckResult, 0, len(defs))
+	for _, def := range defs {
+		e := p.Evaluation(def.ID)
+		results = append(results, CheckResult{
+			Name:        def.ID,
+			Category:    def.Category,
+			Description: def.Description,
+			Severity:    def.Severity,
+			Status:      e.Status,
+			Reason:      e.Reason,
+			Value:       e.Value,
+			Passed:      p.Result(def.ID),
+		})
+	}
+	return results
+}
+
+// FailingChecks returns the checks the project does not pass
+func (p *Project) FailingChecks() []CheckResult {
+	var failing []CheckResult
+	for _, check := range p.Checks() {
+		if !check.Passed {
+			failing = append(failing, check)
+		}
+	}
+	return failing
+}
+
+// Ready reports whether the project passes every readiness check
+func (p *Project) Ready() bool {
+	return len(p.FailingChecks()) == 0
+}
+
+// CategoryScore is how many of a category's checks a project passes
+type CategoryScore struct {
+	Category string  `json:"category" example:"security_scanning"`
+	Passed   int     `json:"passed" example:"3"`
+	Total    int     `json:"total" example:"4"`
+	Score    float64 `json:"score" example:"0.75"` // Passed over Total
+}
+
+// Scores returns the score of every category, in the order the categories'
+// first checks are displayed
+func (p *Project) Scores() []CategoryScore {
+	var scores []CategoryScore
+	for _, def := range checks.Definitions() {
+		i := slices.IndexFunc(scores, func(s CategoryScore) bool { return s.Category == def.Category })
+		if i < 0 {
+			i = len(scores)
+			scores = append(scores, CategoryScore{Category: def.Category})
+		}
+		score := &scores[i]
+		score.Total++
+		if p.Result(def.ID) {
+			score.Passed++
+		}
+	}
+	for i := range scores {
+		scores[i].Score = float64(scores[i].Passed) / float64(scores[i].Total)
+	}
+	return scores
+}
+
+// projectTrailer is the part of a project's JSON after its check results
+type projectTrailer struct {
+	CodeOwners []string `json:"code_owners,omitempty"`
+	ProjectMetadata
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastScannedAt *time.Time `json:"last_scanned_at,omitempty"`
+}
+
+// MarshalJSON writes the project with one field per registered check, in
+// display order, followed by the evaluation of each and the category scores
+func (p Project) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range []struct {
+		name  string
+		value any
+	}{{"instance", p.Instance}, {"project_id", p.ProjectID}} {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeField(&buf, field.name, field.value); err != nil {
+			return nil, err
+		}
+	}
+	if p.Profile != "" {
+		buf.WriteByte(',')
+		if err := writeField(&buf, "profile", p.Profile); err != nil {
+			return nil, err
+		}
+	}
+	ids := checks.IDs()
+	evaluations := make(map[string]Evaluation, len(ids))
+	for _, id := range ids {
+		buf.WriteByte(',')
+		if err := writeField(&buf, id, p.Result(id)); err != nil {
+			return nil, err
+		}
+		evaluations[id] = p.Evaluation(id)
+	}
+	buf.WriteByte(',')
+	if err := writeField(&buf, "evaluations", evaluations); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(',')
+	if err := writeField(&buf, "scores", p.Scores()); err != nil {
+		return nil, err
+	}
+
+	trailer, err := json.Marshal(projectTrailer{
+		CodeOwners:      p.CodeOwners,
+		ProjectMetadata: p.ProjectMetadata,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		LastScannedAt:   p.LastScannedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte(',')
+	buf.Write(trailer[1:]) // Always an object with at least one field
+	return buf.Bytes(), nil
+}
+
+func writeField(buf *bytes.Buffer, name string, value any) error {
+	key, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(v)
+	return nil
+}
+
+// UnmarshalJSON reads a project, taking the result of each registered check
+// from the field named by its ID and its evaluation from evaluations. Other
+// fields are ignored.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type fields Project // Drops the methods, so decoding does not recurse
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, id := range checks.IDs() {
+		v, ok := raw[id]
+		if !ok {
+			continue
+		}
+		var passed *bool
+		if err := json.Unmarshal(v, &passed); err != nil {
+			return fmt.Errorf("invalid %s: %w", id, err)
+		}
+		if passed == nil {
+			continue
+		}
+		if f.Results == nil {
+			f.Results = make(map[string]bool)
+		}
+		f.Results[id] = *passed
+	}
+
+	if v, ok := raw["evaluations"]; ok {
+		if err := json.Unmarshal(v, &f.Evaluations); err != nil {
+			return fmt.Errorf("invalid evaluations: %w", err)
+		}
+		for id := range f.Evaluations {
+			if _, ok := checks.Lookup(id); !ok {
+				delete(f.Evaluations, id)
+			}
+		}
+	}
+
+	*p = Project(f)
+	return nil
 }
@@ -16,6 +16,7 @@ type SuccessResponse struct {
 
 type ErrorResponse struct {
 	BaseResponse
+	Details interface{} `json:"details,omitempty"` // Structured context, e.g. per-row import errors
 }
 
 
@@ -54,6 +55,14 @@ func NewErrorResponse(code int, message string) *ErrorResponse {
 	}
 }
 
+// NewErrorResponseWithDetails is NewErrorResponse with structured details
+// the caller can act on, not just a message
+func NewErrorResponseWithDetails(code int, message string, details interface{}) *ErrorResponse {
+	response := NewErrorResponse(code, message)
+	response.Details = details
+	return response
+}
+
 func NewPaginatedResponse(code int, message string, data interface{}, pagination *PaginationMeta) *PaginatedResponse {
 	return &PaginatedResponse{
 		BaseResponse: BaseResponse{
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Group sync run statuses
+const (
+	SyncRunning   = "running"
+	SyncSucceeded = "succeeded"
+	SyncPartial   = "partial" // Some groups could not be listed
+	SyncFailed    = "failed"
+)
+
+// What started a group sync run
+const (
+	SyncTriggerSchedule = "schedule"
+	SyncTriggerManual   = "manual"
+)
+
+// GroupSyncRun reports one enumeration of an instance's configured groups
+type GroupSyncRun struct {
+	ID           int64            `json:"id"`
+	Instance     string           `json:"instance"`
+	Groups       []string         `json:"groups"`
+	Trigger      string           `json:"trigger"`
+	Status       string           `json:"status"`
+	ProjectsSeen int              `json:"projects_seen"` // Projects found across the groups
+	Created      []string         `json:"created"`       // Projects registered by this run
+	MarkedAbsent []string         `json:"marked_absent"` // Projects no longer in any group
+	Errors       []GroupSyncError `json:"errors"`
+	StartedAt    time.Time        `json:"started_at"`
+	FinishedAt   *time.Time       `json:"finished_at,omitempty"`
+}
+
+// GroupSyncError is a group that could not be listed. Projects previously
+// seen in it are left untouched.
+type GroupSyncError struct {
+	Group string `json:"group"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"   // Waiting for its first or next attempt
+	DeliverySucceeded = "succeeded" // The receiver answered with a 2xx status
+	DeliveryFailed    = "failed"    // Every attempt failed; redeliver to try again
+)
+
+// WebhookSubscription sends matching events to URL, signed with Secret
+type WebhookSubscription struct {
+	ID  int64  `json:"id" db:"id"`
+	URL string `json:"url" db:"url"`
+
+	// Secret is only returned when the subscription is created or rotated
+	Secret string `json:"secret,omitempty" db:"secret"`
+
+	// Events lists the event types to deliver; empty delivers every event
+	Events      []string  `json:"events" db:"events"`
+	Active      bool      `json:"active" db:"active"`
+	Description string    `json:"description,omitempty" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Matches reports whether the subscription wants events of the given type
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	return s.Active && (len(s.Events) == 0 || slices.Contains(s.Events, eventType))
+}
+
+// WebhookSubscriptionRequest is the body for creating or replacing a
+// subscription
+type WebhookSubscriptionRequest struct {
+	URL string `json:"url"`
+
+	// Secret is generated when empty on create, and kept when empty on update
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active,omitempty" default:"true"`
+	Description string   `json:"description,omitempty"`
+}
+
+// WebhookDelivery is one event queued for one subscription, with the
+// outcome of its latest attempt
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id"`
+	SubscriptionID int64           `json:"subscription_id" db:"subscription_id"`
+	EventID        string          `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Instance       string          `json:"instance" db:"instance"`
+	ProjectID      string          `json:"project_id" db:"project_id"`
+	Payload        json.RawMessage `json:"payload" db:"payload" swaggertype:"object"`
+	Status         string          `json:"status" db:"status" enums:"pending,succeeded,failed"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty" db:"response_status"`
+	Error          string          `json:"error,omitempty" db:"error"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
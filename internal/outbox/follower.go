@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/user/go-backend/internal/repository"
+)
+
+// Follower feeds a Broker with the events published by every instance's
+// relay, reading them back from the outbox in sequence order. It is woken by
+// the relay's notifications and also polls, so a missed notification only
+// delays events.
+type Follower struct {
+	repo   repository.OutboxRepository
+	broker *Broker
+	wake   <-chan struct{}
+	cfg    Config
+	logger *slog.Logger
+}
+
+// NewFollower returns a follower woken by wake, which may be nil to rely on
+// polling alone. Of cfg, only BatchSize and PollInterval apply.
+func NewFollower(repo repository.OutboxRepository, broker *Broker, wake <-chan struct{}, cfg Config, logger *slog.Logger) *Follower {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+
+	return &Follower{
+		repo:   repo,
+		broker: broker,
+		wake:   wake,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run follows events published from now on until ctx is cancelled
+func (f *Follower) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+
+	wake := f.wake
+	cursor := int64(-1) // Unknown until the latest sequence number is read
+
+	for {
+		if cursor < 0 {
+			last, err := f.repo.LastSequence(ctx)
+			if err != nil && ctx.Err() == nil {
+				f.logger.Error("failed to read latest event sequence", "error", err)
+			}
+			if err == nil {
+				cursor = last
+			}
+		}
+		if cursor >= 0 {
+			cursor = f.catchUp(ctx, cursor)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-wake:
+			if !ok {
+				wake = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// catchUp publishes the events after cursor to the broker and returns the
+// new cursor
+func (f *Follower) catchUp(ctx context.Context, cursor int64) int64 {
+	for {
+		events, err := f.repo.Since(ctx, cursor, f.cfg.BatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				f.logger.Error("failed to read published events", "error", err)
+			}
+			return cursor
+		}
+		if len(events) > 0 {
+			f.broker.Publish(ctx, events)
+			cursor = events[len(events)-1].Sequence
+		}
+		if len(events) < f.cfg.BatchSize {
+			return cursor
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Package outbox relays the events stored with each project write to sinks
+// such as webhooks or an NDJSON file, and follows the events published by
+// any instance so that they reach in-process subscribers everywhere.
+//
+// Delivery is at least once: a batch is marked published only after every
+// sink accepts it, so a sink may see an event again after another sink
+// fails or the process crashes, and should deduplicate on Event.ID. Only
+// one relay drains the outbox at a time across instances, in the order the
+// events were stored, so events for a project arrive in the order its
+// changes were made.
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+// Sink receives events relayed from the outbox. Returning an error leaves
+// the events in the outbox to be sent again.
+type Sink interface {
+	Publish(ctx context.Context, events []models.Event) error
+}
+
+type Config struct {
+	BatchSize    int           // Events published at once, default 100
+	PollInterval time.Duration // How often to check for new events, default 1s
+	MaxBackoff   time.Duration // Upper bound for the delay after failures, default 1m
+}
+
+type Relay struct {
+	repo   repository.OutboxRepository
+	sinks  []Sink
+	cfg    Config
+	logger *slog.Logger
+}
+
+func NewRelay(repo repository.OutboxRepository, sinks []Sink, cfg Config, logger *slog.Logger) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = time.Minute
+	}
+
+	return &Relay{
+		repo:   repo,
+		sinks:  sinks,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run relays events until ctx is cancelled, backing off while a sink fails
+func (r *Relay) Run(ctx context.Context) {
+	delay := r.cfg.PollInterval
+
+	for {
+		if err := r.Flush(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Error("failed to relay events", "error", err, "retry_in", delay.String())
+			delay = min(delay*2, r.cfg.MaxBackoff)
+		} else {
+			delay = r.cfg.PollInterval
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Flush relays events until the outbox is empty or another instance is
+// draining it
+func (r *Relay) Flush(ctx context.Context) error {
+	for {
+		n, err := r.repo.Drain(ctx, r.cfg.BatchSize, r.publish)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			r.logger.Debug("relayed events", "count", n)
+		}
+		if n < r.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// publish hands events to every sink in turn, stopping at the first failure
+func (r *Relay) publish(ctx context.Context, events []models.Event) error {
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
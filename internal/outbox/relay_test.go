@@ -0,0 +1,199 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository/repotest"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	fail   bool
+	events []models.Event
+}
+
+func (s *recordingSink) Publish(ctx context.Context, events []models.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("sink down")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func testRelay(t *testing.T, sinks ...Sink) (*Relay, *repotest.ProjectRepository) {
+	t.Helper()
+	repo := repotest.NewProjectRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRelay(repo.Outbox(), sinks, Config{BatchSize: 2}, logger), repo
+}
+
+func TestRelay_OrderAndRetry(t *testing.T) {
+	ctx := context.Background()
+	first, second := &recordingSink{}, &recordingSink{fail: true}
+	relay, repo := testRelay(t, first, second)
+
+	repo.Create(ctx, &models.Project{ProjectID: "a"})
+	repo.Update(ctx, &models.Project{ProjectID: "a", Results: map[string]bool{"project_present": true}})
+	repo.Create(ctx, &models.Project{ProjectID: "b"})
+	repo.Delete(ctx, models.NewProjectKey("", "a"))
+
+	if err := relay.Flush(ctx); err == nil {
+		t.Fatal("Flush() with a failing sink returned nil")
+	}
+	if pending := repo.Outbox().Pending(); len(pending) != 5 {
+		t.Fatalf("pending events = %d, want 5 after a failed flush", len(pending))
+	}
+
+	second.fail = false
+	if err := relay.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if pending := repo.Outbox().Pending(); len(pending) != 0 {
+		t.Errorf("pending events = %d after flush, want 0", len(pending))
+	}
+
+	var got []string
+	for _, event := range second.events {
+		if event.ProjectID == "a" {
+			got = append(got, event.Type)
+		}
+	}
+	want := []string{
+		models.EventProjectCreated,
+		models.EventProjectUpdated,
+		models.EventCheckChanged,
+		models.EventProjectDeleted,
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("events for a = %v, want %v", got, want)
+	}
+
+	// The first sink saw the failed batch too: delivery is at least once
+	if len(first.events) != len(second.events)+2 {
+		t.Errorf("first sink got %d events, want %d", len(first.events), len(second.events)+2)
+	}
+}
+
+func TestFollower(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	relay, repo := testRelay(t)
+
+	repo.Create(ctx, &models.Project{ProjectID: "a"})
+	if err := relay.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	broker := NewBroker()
+	events, unsubscribe := broker.Subscribe(10)
+	defer unsubscribe()
+
+	// Polling is effectively off, so only wake-ups deliver events. The first
+	// send returns once the follower has started from the latest event.
+	wake := make(chan struct{})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	go NewFollower(repo.Outbox(), broker, wake, Config{BatchSize: 1, PollInterval: time.Hour}, logger).Run(ctx)
+	wake <- struct{}{}
+
+	repo.Create(ctx, &models.Project{ProjectID: "b"})
+	repo.Create(ctx, &models.Project{ProjectID: "c"})
+	if err := relay.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	wake <- struct{}{}
+
+	for i, want := range []string{"b", "c"} {
+		select {
+		case event := <-events:
+			if event.ProjectID != want || event.Sequence != int64(i+2) {
+				t.Errorf("event %d = %s #%d, want %s #%d", i, event.ProjectID, event.Sequence, want, i+2)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %d", i)
+		}
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	events := []models.Event{
+		{ID: "evt_1", Type: models.EventProjectCreated, ProjectID: "1"},
+		{ID: "evt_2", Type: models.EventProjectDeleted, ProjectID: "1"},
+	}
+
+	if err := sink.Publish(context.Background(), events); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d lines, want 2", len(lines))
+	}
+	var event models.Event
+	if err := json.Unmarshal([]byte(lines[1]), &event); err != nil || event.ID != "evt_2" {
+		t.Errorf("second line = %q, %v", lines[1], err)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	status := http.StatusOK
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := NewHTTPSink(srv.URL, nil)
+	events := []models.Event{{ID: "evt_1", Type: models.EventProjectCreated, ProjectID: "1"}}
+
+	if err := sink.Publish(context.Background(), events); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if !strings.Contains(body, `"id":"evt_1"`) {
+		t.Errorf("body = %q", body)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Publish(context.Background(), events); err == nil {
+		t.Error("Publish() to a failing endpoint returned nil")
+	}
+}
+
+func TestBroker_DropsSlowSubscribers(t *testing.T) {
+	broker := NewBroker()
+	fast, stopFast := broker.Subscribe(10)
+	defer stopFast()
+	slow, stopSlow := broker.Subscribe(1)
+
+	events := []models.Event{{ID: "evt_1"}, {ID: "evt_2"}}
+	broker.Publish(context.Background(), events)
+
+	if len(fast) != 2 {
+		t.Errorf("fast subscriber got %d events, want 2", len(fast))
+	}
+	<-slow
+	if _, ok := <-slow; ok {
+		t.Error("slow subscriber was not dropped")
+	}
+	stopSlow() // Unsubscribing after being dropped is safe
+}
@@ -0,0 +1,192 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// HTTPSink POSTs each batch of events to a URL as NDJSON
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink returns a sink posting to url. client defaults to one with a
+// 10s timeout.
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPSink{url: url, client: client}
+}
+
+// Publish treats any response but a 2xx as a failure
+func (s *HTTPSink) Publish(ctx context.Context, events []models.Event) error {
+	var body bytes.Buffer
+	if err := writeNDJSON(&body, events); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	req.Header.Set("User-Agent", "readiness-outbox/1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post events: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to post events: %s responded with status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// WriterSink appends events to a writer as NDJSON, one event per line
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// OpenFileSink appends to the file at path, creating it if needed. A path of
+// "-" writes to standard output.
+func OpenFileSink(path string) (*WriterSink, error) {
+	if path == "-" {
+		return NewWriterSink(os.Stdout), nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return NewWriterSink(f), nil
+}
+
+// Publish writes the batch in a single call and syncs files to disk so
+// that the events survive a crash once the outbox marks them published
+func (s *WriterSink) Publish(ctx context.Context, events []models.Event) error {
+	var buf bytes.Buffer
+	if err := writeNDJSON(&buf, events); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+	if f, ok := s.w.(*os.File); ok && f != os.Stdout {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync events: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying writer when it is a file other than stdout
+func (s *WriterSink) Close() error {
+	if f, ok := s.w.(*os.File); ok && f != os.Stdout {
+		return f.Close()
+	}
+	return nil
+}
+
+func writeNDJSON(w io.Writer, events []models.Event) error {
+	enc := json.NewEncoder(w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// Broker fans events out to in-process subscribers. Subscribers that fall
+// more than their buffer behind are dropped, with their channel closed, so
+// one slow consumer cannot stall the relay.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan models.Event]struct{}
+	closed      bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan models.Event]struct{})}
+}
+
+// Subscribe returns a channel receiving every event published from now on
+// and a function that unsubscribes and closes it
+func (b *Broker) Subscribe(buffer int) (<-chan models.Event, func()) {
+	ch := make(chan models.Event, buffer)
+
+	b.mu.Lock()
+	if b.closed {
+		close(ch)
+	} else {
+		b.subscribers[ch] = struct{}{}
+	}
+	b.mu.Unlock()
+
+	return ch, func() { b.unsubscribe(ch) }
+}
+
+func (b *Broker) unsubscribe(ch chan models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Publish never fails; events are only held in memory
+func (b *Broker) Publish(ctx context.Context, events []models.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		for _, event := range events {
+			select {
+			case ch <- event:
+				continue
+			default:
+			}
+			delete(b.subscribers, ch)
+			close(ch)
+			break
+		}
+	}
+	return nil
+}
+
+// Close closes every subscriber's channel, and those of later subscribers,
+// so that long-lived consumers end when the server shuts down
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit renders one test suite per check category and one test case per check
+func writeJUnit(w io.Writer, project *models.Project, entries []entry) error {
+	doc := junitTestSuites{Name: "readiness: " + project.ProjectID}
+	suites := make(map[string]*junitTestSuite)
+	var order []string
+
+	for _, e := range entries {
+		category := e.check.Category
+		suite, ok := suites[category]
+		if !ok {
+			suite = &junitTestSuite{
+				Name:      category,
+				Timestamp: project.UpdatedAt.UTC().Format(time.RFC3339),
+			}
+			suites[category] = suite
+			order = append(order, category)
+		}
+
+		tc := junitTestCase{
+			ClassName: "readiness." + category,
+			Name:      e.check.Name,
+			File:      e.file(),
+			SystemOut: e.check.Description,
+		}
+
+		switch {
+		case e.exemption != nil:
+			tc.Skipped = &junitMessage{Message: "Exempted: " + e.exemption.Reason}
+			suite.Skipped++
+			doc.Skipped++
+		case !e.check.Passed:
+			tc.Failure = &junitMessage{
+				Message: e.failure(),
+				Type:    "readiness_check",
+				Body:    "Remediation: " + e.definition.Remediation,
+			}
+			suite.Failures++
+			doc.Failures++
+		}
+
+		suite.Tests++
+		doc.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for _, category := range order {
+		doc.Suites = append(doc.Suites, *suites[category])
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
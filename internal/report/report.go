@@ -0,0 +1,125 @@
+// Package report renders a project's readiness checks in formats CI systems
+// understand natively, so failing checks show up inline in merge requests.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+type Format string
+
+const (
+	FormatJUnit Format = "junit"
+	FormatSARIF Format = "sarif"
+)
+
+// ParseFormat accepts a format name as used in the format query parameter
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case FormatJUnit:
+		return FormatJUnit, nil
+	case FormatSARIF:
+		return FormatSARIF, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q: must be junit or sarif", name)
+	}
+}
+
+// FormatForAccept picks a report format from an Accept header, returning
+// false when the client did not ask for one
+func FormatForAccept(accept string) (Format, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		switch strings.ToLower(mediaType) {
+		case "application/junit+xml", "application/xml", "text/xml":
+			return FormatJUnit, true
+		case "application/sarif+json":
+			return FormatSARIF, true
+		case "application/json", "*/*":
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func (f Format) ContentType() string {
+	if f == FormatSARIF {
+		return "application/sarif+json"
+	}
+	return "application/xml; charset=utf-8"
+}
+
+// Filename is the suggested artifact name for downloads
+func (f Format) Filename(projectID string) string {
+	name := strings.NewReplacer("/", "-", "\\", "-", "\"", "").Replace(projectID)
+	if f == FormatSARIF {
+		return "readiness-" + name + ".sarif"
+	}
+	return "readiness-" + name + ".xml"
+}
+
+// Write renders every check of the project. Failing checks covered by one of
+// the given exemptions are reported as skipped/suppressed rather than failed;
+// callers pass only the exemptions active for the target environment.
+func Write(w io.Writer, format Format, project *models.Project, exemptions []*models.Exemption) error {
+	entries := buildEntries(project, exemptions)
+
+	switch format {
+	case FormatJUnit:
+		return writeJUnit(w, project, entries)
+	case FormatSARIF:
+		return writeSARIF(w, project, entries)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+type entry struct {
+	check      models.CheckResult
+	definition checks.Definition
+	exemption  *models.Exemption // Set when a failing check is exempted
+}
+
+func buildEntries(project *models.Project, exemptions []*models.Exemption) []entry {
+	byCheck := make(map[string]*models.Exemption)
+	for _, e := range exemptions {
+		byCheck[e.CheckName] = e
+	}
+
+	var entries []entry
+	for _, check := range project.Checks() {
+		var def checks.Definition
+		if c, ok := checks.Lookup(check.Name); ok {
+			def = c.Definition()
+		}
+		e := entry{check: check, definition: def}
+		if !check.Passed {
+			e.exemption = byCheck[check.Name]
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// file returns the repository file the check inspects, giving CI tools a
+// location to anchor the finding to
+func (e entry) file() string {
+	if len(e.definition.Files) == 0 {
+		return ""
+	}
+	return e.definition.Files[0]
+}
+
+// failure describes why the check failed, with the scanned reason when there
+// is one
+func (e entry) failure() string {
+	if e.check.Reason == "" {
+		return e.check.Description
+	}
+	return e.check.Description + ": " + e.check.Reason
+}
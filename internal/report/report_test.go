@@ -0,0 +1,124 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+func testProject() *models.Project {
+	return &models.Project{
+		ProjectID: "group/app",
+		Results: map[string]bool{
+			"project_present":           true,
+			"app_name_set":              true,
+			"branch_protection_enabled": true,
+		},
+		UpdatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	exemptions := []*models.Exemption{{CheckName: "moab_id_set", Reason: "legacy app"}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJUnit, testProject(), exemptions); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+
+	if doc.Tests != 29 || doc.Failures != 25 || doc.Skipped != 1 {
+		t.Errorf("tests=%d failures=%d skipped=%d, want 29/25/1", doc.Tests, doc.Failures, doc.Skipped)
+	}
+	if len(doc.Suites) != 6 || doc.Suites[0].Name != checks.CategoryPresence {
+		t.Fatalf("unexpected suites: %+v", doc.Suites)
+	}
+
+	for _, tc := range doc.Suites[0].Cases {
+		switch tc.Name {
+		case "moab_id_set":
+			if tc.Skipped == nil || !strings.Contains(tc.Skipped.Message, "legacy app") || tc.Failure != nil {
+				t.Errorf("moab_id_set should be skipped: %+v", tc)
+			}
+		case "codeowners_exists":
+			if tc.Failure == nil || !strings.Contains(tc.Failure.Body, "CODEOWNERS") || tc.File != "CODEOWNERS" {
+				t.Errorf("codeowners_exists should fail with remediation: %+v", tc)
+			}
+		case "app_name_set":
+			if tc.Failure != nil || tc.Skipped != nil {
+				t.Errorf("app_name_set should pass: %+v", tc)
+			}
+		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	exemptions := []*models.Exemption{{CheckName: "moab_id_set", Reason: "legacy app"}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatSARIF, testProject(), exemptions); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected SARIF envelope: version %q, %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 29 || len(run.Results) != 29 {
+		t.Fatalf("got %d rules and %d results, want 29 each", len(run.Tool.Driver.Rules), len(run.Results))
+	}
+
+	kinds := map[string]int{}
+	for _, r := range run.Results {
+		kinds[r.Kind]++
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("result %s points at rule %d", r.RuleID, r.RuleIndex)
+		}
+		if r.RuleID == "moab_id_set" && (len(r.Suppressions) != 1 || r.Suppressions[0].Justification != "legacy app") {
+			t.Errorf("moab_id_set should be suppressed: %+v", r)
+		}
+		if r.RuleID == "app_name_set" && r.Locations[0].PhysicalLocation.ArtifactLocation.URI != ".gitlab-ci.yml" {
+			t.Errorf("app_name_set should point at .gitlab-ci.yml: %+v", r.Locations)
+		}
+	}
+	if kinds["pass"] != 3 || kinds["fail"] != 26 {
+		t.Errorf("result kinds = %v, want 3 pass and 26 fail", kinds)
+	}
+}
+
+func TestFormatForAccept(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   Format
+		ok     bool
+	}{
+		{"application/junit+xml", FormatJUnit, true},
+		{"text/xml;q=0.9", FormatJUnit, true},
+		{"application/sarif+json", FormatSARIF, true},
+		{"application/json, application/xml", "", false},
+		{"", "", false},
+		{"*/*", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := FormatForAccept(tt.accept)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FormatForAccept(%q) = %q, %v; want %q, %v", tt.accept, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	toolName     = "project-readiness"
+	toolVersion  = "1.0.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string            `json:"id"`
+	ShortDescription sarifText         `json:"shortDescription"`
+	Help             sarifText         `json:"help"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	RuleIndex    int                `json:"ruleIndex"`
+	Kind         string             `json:"kind"`
+	Level        string             `json:"level"`
+	Message      sarifText          `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification"`
+}
+
+// writeSARIF renders one rule per check and one result per check. Passing
+// checks are included with kind "pass" so the log is a complete record.
+func writeSARIF(w io.Writer, project *models.Project, entries []entry) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:    toolName,
+			Version: toolVersion,
+		}},
+		Results: []sarifResult{},
+	}
+
+	for i, e := range entries {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               e.check.Name,
+			ShortDescription: sarifText{Text: e.check.Description},
+			Help:             sarifText{Text: e.definition.Remediation},
+			Properties:       map[string]string{"category": e.check.Category},
+		})
+
+		result := sarifResult{
+			RuleID:    e.check.Name,
+			RuleIndex: i,
+			Kind:      "pass",
+			Level:     "none",
+			Message:   sarifText{Text: e.check.Description},
+			Locations: []sarifLocation{location(project.ProjectID, e.file())},
+		}
+		if !e.check.Passed {
+			result.Kind = "fail"
+			result.Level = "error"
+			result.Message = sarifText{Text: "Failing: " + e.failure() + ". " + e.definition.Remediation}
+		}
+		if e.exemption != nil {
+			result.Suppressions = []sarifSuppression{{
+				Kind:          "external",
+				Justification: e.exemption.Reason,
+			}}
+		}
+
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
+
+func location(projectID, file string) sarifLocation {
+	loc := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{Name: projectID, Kind: "module"}},
+	}
+	if file != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: file},
+		}
+	}
+	return loc
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/models"
+)
+
+type ApplicationRepository interface {
+	// Replace makes apps the whole list of registered applications, setting
+	// their creation time
+	Replace(ctx context.Context, apps []*models.Application) error
+
+	// List returns the registered applications by name
+	List(ctx context.Context) ([]*models.Application, error)
+}
+
+type applicationRepo struct {
+	db *database.DB
+}
+
+func NewApplicationRepository(db *database.DB) ApplicationRepository {
+	return &applicationRepo{db: db}
+}
+
+func (r *applicationRepo) Replace(ctx context.Context, apps []*models.Application) error {
+	query := `
+		INSERT INTO applications (name, description, created_at)
+		SELECT v.*, $3 FROM unnest($1::text[], $2::text[]) AS v(name, description)
+	`
+
+	now := time.Now()
+	names := make([]string, len(apps))
+	descriptions := make([]string, len(apps))
+	for i, app := range apps {
+		app.CreatedAt = now
+		names[i] = app.Name
+		descriptions[i] = app.Description
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM applications`); err != nil {
+		return fmt.Errorf("failed to clear applications: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, pq.Array(names), pq.Array(descriptions), now); err != nil {
+		return fmt.Errorf("failed to register applications: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit applications: %w", err)
+	}
+	return nil
+}
+
+func (r *applicationRepo) List(ctx context.Context) ([]*models.Application, error) {
+	query := `SELECT name, description, created_at FROM applications ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list applications: %w", err)
+	}
+	defer rows.Close()
+
+	var apps []*models.Application
+	for rows.Next() {
+		app := &models.Application{}
+		if err := rows.Scan(&app.Name, &app.Description, &app.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan application: %w", err)
+		}
+		apps = append(apps, app)
+	}
+	return apps, rows.Err()
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/user/go-backend/internal/events"
+	"github.com/user/go-backend/internal/models"
+)
+
+// BatchOutcome is what happened to one operation in a batch
+type BatchOutcome string
+
+const (
+	BatchCreated  BatchOutcome = "created"
+	BatchUpdated  BatchOutcome = "updated"
+	BatchDeleted  BatchOutcome = "deleted"
+	BatchConflict BatchOutcome = "conflict"  // Create of an existing project
+	BatchNotFound BatchOutcome = "not_found" // Update or delete of a missing project
+)
+
+// Failed reports whether the operation was not applied
+func (o BatchOutcome) Failed() bool {
+	return o == BatchConflict || o == BatchNotFound
+}
+
+// batchValues is a set of projects as one array per column, so any number
+// of rows is sent with a fixed number of parameters through unnest. Check
+// results are written separately by saveResults.
+type batchValues struct {
+	index     []int // Position of each row in the batch
+	keys      []models.ProjectKey
+	projects  []*models.Project
+	instances []string
+	ids       []string
+}
+
+func (v *batchValues) add(index int, project *models.Project) {
+	key := project.Key()
+	project.Instance = key.Instance
+	v.index = append(v.index, index)
+	v.keys = append(v.keys, key)
+	v.projects = append(v.projects, project)
+	v.instances = append(v.instances, key.Instance)
+	v.ids = append(v.ids, key.ProjectID)
+}
+
+// args returns the unnest parameters followed by the timestamp
+func (v *batchValues) args(now time.Time) []interface{} {
+	return []interface{}{pq.Array(v.instances), pq.Array(v.ids), now}
+}
+
+// batchSource is the rows of a batchValues, with the timestamp as $3
+const batchSource = `unnest($1::text[], $2::text[]) AS v(instance, project_id)`
+
+// Batch applies create, update, upsert and delete operations in one
+// transaction, issuing a single statement per kind of operation. Project keys
+// must be unique within ops so the statements cannot interact. In atomic mode
+// the transaction is rolled back if any operation fails; otherwise the
+// operations that succeeded are committed. The outcomes are in ops order.
+func (r *projectRepo) Batch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]BatchOutcome, bool, error) {
+	var creates, updates, upserts batchValues
+	var deleteIndex []int
+	var deleteKeys []models.ProjectKey
+	var deleteInstances, deleteIDs []string
+
+	for i, op := range ops {
+		switch op.Op {
+		case models.BatchCreate:
+			creates.add(i, op.Project)
+		case models.BatchUpdate:
+			updates.add(i, op.Project)
+		case models.BatchUpsert:
+			upserts.add(i, op.Project)
+		case models.BatchDelete:
+			key := models.NewProjectKey(op.Instance, op.ProjectID)
+			deleteIndex = append(deleteIndex, i)
+			deleteKeys = append(deleteKeys, key)
+			deleteInstances = append(deleteInstances, key.Instance)
+			deleteIDs = append(deleteIDs, key.ProjectID)
+		default:
+			return nil, false, fmt.Errorf("unknown batch operation: %s", op.Op)
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin batch: %w", err)
+	}
+	defer tx.Rollback()
+
+	keys := append(append(append(slices.Clone(creates.keys), updates.keys...), upserts.keys...), deleteKeys...)
+	current, err := lockProjects(ctx, tx, keys)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	outcomes := make([]BatchOutcome, len(ops))
+	var written []*models.Project // Projects whose results are to be saved
+
+	// affected runs a statement returning (instance, project_id, inserted)
+	// for each row it touched
+	affected := func(query string, args []interface{}) (map[models.ProjectKey]bool, error) {
+		rows, err := tx.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		touched := make(map[models.ProjectKey]bool)
+		for rows.Next() {
+			var key models.ProjectKey
+			var inserted bool
+			if err := rows.Scan(&key.Instance, &key.ProjectID, &inserted); err != nil {
+				return nil, err
+			}
+			touched[key] = inserted
+		}
+		return touched, rows.Err()
+	}
+
+	if len(creates.ids) > 0 {
+		query := `INSERT INTO gitlab_projects (instance, project_id, created_at, updated_at)
+			SELECT v.*, $3, $3 FROM ` + batchSource + `
+			ON CONFLICT (instance, project_id) DO NOTHING
+			RETURNING instance, project_id, TRUE`
+		touched, err := affected(query, creates.args(now))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create projects: %w", err)
+		}
+		for i, key := range creates.keys {
+			outcomes[creates.index[i]] = BatchConflict
+			if _, ok := touched[key]; ok {
+				outcomes[creates.index[i]] = BatchCreated
+				written = append(written, creates.projects[i])
+			}
+		}
+	}
+
+	if len(upserts.ids) > 0 {
+		query := `INSERT INTO gitlab_projects (instance, project_id, created_at, updated_at)
+			SELECT v.*, $3, $3 FROM ` + batchSource + `
+			ON CONFLICT (instance, project_id) DO UPDATE SET updated_at = EXCLUDED.updated_at
+			RETURNING instance, project_id, (xmax = 0)`
+		touched, err := affected(query, upserts.args(now))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to upsert projects: %w", err)
+		}
+		for i, key := range upserts.keys {
+			outcomes[upserts.index[i]] = BatchUpdated
+			if touched[key] {
+				outcomes[upserts.index[i]] = BatchCreated
+			}
+		}
+		written = append(written, upserts.projects...)
+	}
+
+	if len(updates.ids) > 0 {
+		query := `UPDATE gitlab_projects g SET updated_at = $3
+			FROM ` + batchSource + `
+			WHERE g.instance = v.instance AND g.project_id = v.project_id
+			RETURNING g.instance, g.project_id, FALSE`
+		touched, err := affected(query, updates.args(now))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to update projects: %w", err)
+		}
+		for i, key := range updates.keys {
+			outcomes[updates.index[i]] = BatchNotFound
+			if _, ok := touched[key]; ok {
+				outcomes[updates.index[i]] = BatchUpdated
+				written = append(written, updates.projects[i])
+			}
+		}
+	}
+
+	if err := saveResults(ctx, tx, written); err != nil {
+		return nil, false, err
+	}
+
+	if len(deleteKeys) > 0 {
+		query := `DELETE FROM gitlab_projects g
+			USING unnest($1::text[], $2::text[]) AS d(instance, project_id)
+			WHERE g.instance = d.instance AND g.project_id = d.project_id
+			RETURNING g.instance, g.project_id, FALSE`
+		touched, err := affected(query, []interface{}{pq.Array(deleteInstances), pq.Array(deleteIDs)})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to delete projects: %w", err)
+		}
+		for i, key := range deleteKeys {
+			outcomes[deleteIndex[i]] = BatchNotFound
+			if _, ok := touched[key]; ok {
+				outcomes[deleteIndex[i]] = BatchDeleted
+			}
+		}
+	}
+
+	if atomic {
+		for _, outcome := range outcomes {
+			if outcome.Failed() {
+				return outcomes, false, nil
+			}
+		}
+	}
+
+	if err := writeEvents(ctx, tx, batchEvents(ops, outcomes, current, now)); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit batch: %w", err)
+	}
+	return outcomes, true, nil
+}
+
+// batchEvents derives the events for the applied operations from the
+// projects as they were before the batch, stamping written projects with the
+// batch time
+func batchEvents(ops []models.BatchOperation, outcomes []BatchOutcome, before map[models.ProjectKey]*models.Project, now time.Time) []models.Event {
+	var changes []models.Event
+	for i, op := range ops {
+		// Batches write checks only, so written projects keep any stored
+		// metadata, profile and code owners
+		if op.Project != nil && (outcomes[i] == BatchCreated || outcomes[i] == BatchUpdated) {
+			op.Project.CreatedAt, op.Project.UpdatedAt = now, now
+			op.Project.ProjectMetadata = models.ProjectMetadata{}
+			op.Project.Profile = ""
+			op.Project.CodeOwners = nil
+		}
+
+		switch outcomes[i] {
+		case BatchCreated:
+			changes = append(changes, events.Created(op.Project)...)
+		case BatchUpdated:
+			if previous, ok := before[op.Project.Key()]; ok {
+				op.Project.CreatedAt = previous.CreatedAt
+				op.Project.ProjectMetadata = previous.ProjectMetadata
+				op.Project.Profile = previous.Profile
+				op.Project.CodeOwners = previous.CodeOwners
+				changes = append(changes, events.Diff(previous, op.Project)...)
+			} else {
+				changes = append(changes, events.Created(op.Project)...)
+			}
+		case BatchDeleted:
+			if previous, ok := before[models.NewProjectKey(op.Instance, op.ProjectID)]; ok {
+				changes = append(changes, events.Deleted(previous)...)
+			}
+		}
+	}
+	return changes
+}
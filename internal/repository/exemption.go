@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/models"
+)
+
+type ExemptionRepository interface {
+	Create(ctx context.Context, exemption *models.Exemption) error
+
+	ListByProject(ctx context.Context, key models.ProjectKey) ([]*models.Exemption, error)
+
+	Delete(ctx context.Context, key models.ProjectKey, id int64) error
+}
+
+type exemptionRepo struct {
+	db *database.DB
+}
+
+func NewExemptionRepository(db *database.DB) ExemptionRepository {
+	return &exemptionRepo{db: db}
+}
+
+func (r *exemptionRepo) Create(ctx context.Context, exemption *models.Exemption) error {
+	query := `
+		INSERT INTO project_exemptions (
+			instance, project_id, check_name, environment, reason, created_by, expires_at, created_at
+		) VALUES (
+			$1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8
+		)
+		RETURNING id
+	`
+
+	exemption.Instance = models.NewProjectKey(exemption.Instance, exemption.ProjectID).Instance
+	exemption.CreatedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, query,
+		exemption.Instance,
+		exemption.ProjectID,
+		exemption.CheckName,
+		exemption.Environment,
+		exemption.Reason,
+		exemption.CreatedBy,
+		exemption.ExpiresAt,
+		exemption.CreatedAt,
+	).Scan(&exemption.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create exemption: %w", err)
+	}
+
+	return nil
+}
+
+func (r *exemptionRepo) ListByProject(ctx context.Context, key models.ProjectKey) ([]*models.Exemption, error) {
+	query := `
+		SELECT
+			id, instance, project_id, check_name, COALESCE(environment, ''), reason,
+			created_by, expires_at, created_at
+		FROM project_exemptions
+		WHERE instance = $1 AND project_id = $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, key.Instance, key.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exemptions: %w", err)
+	}
+	defer rows.Close()
+
+	var exemptions []*models.Exemption
+	for rows.Next() {
+		exemption := &models.Exemption{}
+		var expiresAt sql.NullTime
+		err := rows.Scan(
+			&exemption.ID,
+			&exemption.Instance,
+			&exemption.ProjectID,
+			&exemption.CheckName,
+			&exemption.Environment,
+			&exemption.Reason,
+			&exemption.CreatedBy,
+			&expiresAt,
+			&exemption.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exemption: %w", err)
+		}
+		if expiresAt.Valid {
+			exemption.ExpiresAt = &expiresAt.Time
+		}
+		exemptions = append(exemptions, exemption)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return exemptions, nil
+}
+
+func (r *exemptionRepo) Delete(ctx context.Context, key models.ProjectKey, id int64) error {
+	query := `DELETE FROM project_exemptions WHERE instance = $1 AND project_id = $2 AND id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, key.Instance, key.ProjectID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete exemption: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("exemption not found")
+	}
+
+	return nil
+}
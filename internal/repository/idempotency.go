@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/models"
+)
+
+// ErrIdempotencyKeyNotFound is returned for a key with no record, such as one
+// released while a claim was reading its owner
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+type IdempotencyRepository interface {
+	// Claim records key as in flight for requestHash. It returns nil when the
+	// caller now owns the key, or the existing record when the key is taken.
+	// Expired records, and in-flight records created before staleBefore, are
+	// replaced.
+	Claim(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (*models.IdempotencyRecord, error)
+
+	// Complete stores the response for a claimed key
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+
+	// Release forgets a claimed key so the request can be retried
+	Release(ctx context.Context, key string) error
+
+	// DeleteExpired removes records past their expiry, returning how many
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyRepo struct {
+	db *database.DB
+}
+
+func NewIdempotencyRepository(db *database.DB) IdempotencyRepository {
+	return &idempotencyRepo{db: db}
+}
+
+func (r *idempotencyRepo) Claim(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	// The upsert only takes over rows that are expired or abandoned, so a
+	// returned row means we own the key
+	query := `
+		INSERT INTO idempotency_keys (idempotency_key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (idempotency_key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			response_headers = '{}',
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < EXCLUDED.created_at
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $5)
+		RETURNING idempotency_key
+	`
+
+	var claimed string
+	err := r.db.QueryRowContext(ctx, query, key, requestHash, time.Now(), expiresAt, staleBefore).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+
+	return r.get(ctx, key)
+}
+
+func (r *idempotencyRepo) get(ctx context.Context, key string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT
+			idempotency_key, request_hash, COALESCE(status_code, 0),
+			response_headers, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE idempotency_key = $1
+	`
+
+	record := &models.IdempotencyRecord{}
+	var headers []byte
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&headers,
+		&record.Body,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		// Released between our claim attempt and this read
+		return nil, ErrIdempotencyKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	if err := json.Unmarshal(headers, &record.Headers); err != nil {
+		return nil, fmt.Errorf("failed to decode stored headers: %w", err)
+	}
+
+	return record, nil
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys SET
+			status_code = $2,
+			response_headers = $3,
+			response_body = $4
+		WHERE idempotency_key = $1
+	`
+
+	headers, err := json.Marshal(record.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, record.Key, record.StatusCode, headers, record.Body)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrIdempotencyKeyNotFound
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepo) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE idempotency_key = $1 AND status_code IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepo) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,275 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/models"
+)
+
+// outboxLockID is the advisory lock key held while draining the outbox, so
+// that only one relay across all instances publishes at a time
+const outboxLockID = 0x6f7574626f78 // "outbox"
+
+// EventsChannel is the PostgreSQL notification channel signalled, with the
+// latest sequence number as payload, whenever events are published
+const EventsChannel = "outbox_events"
+
+type OutboxRepository interface {
+	// Drain passes up to limit unpublished events, oldest first, to publish
+	// and marks them published once it returns nil. The events are numbered
+	// with the next sequence numbers before publish sees them. It returns
+	// how many events were published, which is zero when another instance
+	// is draining.
+	Drain(ctx context.Context, limit int, publish func(context.Context, []models.Event) error) (int, error)
+
+	// Since returns up to limit published events with a sequence number
+	// above the given one, in sequence order
+	Since(ctx context.Context, sequence int64, limit int) ([]models.Event, error)
+
+	// LastSequence returns the sequence number of the latest published
+	// event, or zero if there is none
+	LastSequence(ctx context.Context) (int64, error)
+
+	// DeletePublished removes events published before the given time,
+	// returning how many
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type outboxRepo struct {
+	db *database.DB
+}
+
+func NewOutboxRepository(db *database.DB) OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+func (r *outboxRepo) Drain(ctx context.Context, limit int, publish func(context.Context, []models.Event) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin drain: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockID).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock outbox: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	query := `
+		SELECT id, payload
+		FROM event_outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	var events []models.Event
+	for rows.Next() {
+		var id int64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return 0, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		var event models.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return 0, fmt.Errorf("failed to decode outbox entry %d: %w", id, err)
+		}
+		ids = append(ids, id)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	// Reserve a block of sequence numbers. Holding the advisory lock means
+	// no other drain can commit numbers in between; numbers reserved by a
+	// failed publish are skipped.
+	var last int64
+	reserve := `SELECT setval('event_outbox_sequence', nextval('event_outbox_sequence') + $1 - 1)`
+	if err := tx.QueryRowContext(ctx, reserve, len(events)).Scan(&last); err != nil {
+		return 0, fmt.Errorf("failed to reserve sequence numbers: %w", err)
+	}
+	sequences := make([]int64, len(events))
+	for i := range events {
+		sequences[i] = last - int64(len(events)-1-i)
+		events[i].Sequence = sequences[i]
+	}
+
+	if err := publish(ctx, events); err != nil {
+		return 0, err
+	}
+
+	update := `
+		UPDATE event_outbox e SET published_at = $3, sequence = u.sequence
+		FROM unnest($1::bigint[], $2::bigint[]) AS u(id, sequence)
+		WHERE e.id = u.id
+	`
+	if _, err := tx.ExecContext(ctx, update, pq.Array(ids), pq.Array(sequences), time.Now()); err != nil {
+		return 0, fmt.Errorf("failed to mark events published: %w", err)
+	}
+
+	// Delivered to listeners on every instance when the drain commits
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, EventsChannel, strconv.FormatInt(last, 10)); err != nil {
+		return 0, fmt.Errorf("failed to notify listeners: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit drain: %w", err)
+	}
+
+	return len(events), nil
+}
+
+func (r *outboxRepo) Since(ctx context.Context, sequence int64, limit int) ([]models.Event, error) {
+	query := `
+		SELECT sequence, payload
+		FROM event_outbox
+		WHERE sequence > $1
+		ORDER BY sequence
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sequence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read published events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var seq int64
+		var payload []byte
+		if err := rows.Scan(&seq, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox entry: %w", err)
+		}
+		var event models.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to decode outbox entry %d: %w", seq, err)
+		}
+		event.Sequence = seq
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepo) LastSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(sequence), 0) FROM event_outbox`).Scan(&sequence); err != nil {
+		return 0, fmt.Errorf("failed to get last sequence: %w", err)
+	}
+	return sequence, nil
+}
+
+func (r *outboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM event_outbox WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete published events: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// writeEvents adds events to the outbox as part of tx
+func writeEvents(ctx context.Context, tx *sql.Tx, events []models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(events))
+	types := make([]string, len(events))
+	instances := make([]string, len(events))
+	projects := make([]string, len(events))
+	payloads := make([]string, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		ids[i], types[i], instances[i], projects[i], payloads[i] = event.ID, event.Type, event.Instance, event.ProjectID, string(payload)
+	}
+
+	// ORDINALITY keeps the outbox ids in the order the events were derived
+	query := `
+		INSERT INTO event_outbox (event_id, event_type, instance, project_id, payload, created_at)
+		SELECT e.event_id, e.event_type, e.instance, e.project_id, e.payload::jsonb, $6
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[])
+			WITH ORDINALITY AS e(event_id, event_type, instance, project_id, payload, n)
+		ORDER BY e.n
+	`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(ids), pq.Array(types), pq.Array(instances), pq.Array(projects), pq.Array(payloads), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+
+	return nil
+}
+
+// lockProjects returns the stored versions of the given projects, keyed by
+// instance and ID, and locks their rows until tx ends. Events derived from
+// them are then written to the outbox in the order the changes commit.
+func lockProjects(ctx context.Context, tx *sql.Tx, keys []models.ProjectKey) (map[models.ProjectKey]*models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + ` FROM gitlab_projects
+		WHERE (instance, project_id) IN (SELECT * FROM unnest($1::text[], $2::text[]))
+		ORDER BY instance, project_id
+		FOR UPDATE
+	`
+
+	instances := make([]string, len(keys))
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		instances[i], ids[i] = key.Instance, key.ProjectID
+	}
+
+	rows, err := tx.QueryContext(ctx, query, pq.Array(instances), pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := make(map[models.ProjectKey]*models.Project, len(keys))
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects[project.Key()] = project
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return projects, nil
+}
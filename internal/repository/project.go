@@ -3,25 +3,156 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"iter"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/user/go-backend/internal/checks"
 	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/events"
 	"github.com/user/go-backend/internal/models"
 )
 
 type ProjectRepository interface {
 	Create(ctx context.Context, project *models.Project) error
 
-	GetByID(ctx context.Context, projectID string) (*models.Project, error)
+	GetByID(ctx context.Context, key models.ProjectKey) (*models.Project, error)
+
+	// GetByRef finds a project on an instance by its project_id, numeric
+	// GitLab ID or full path, in that order of preference
+	GetByRef(ctx context.Context, instance, ref string) (*models.Project, error)
 
 	Update(ctx context.Context, project *models.Project) error
 
-	Delete(ctx context.Context, projectID string) error
+	// SaveScan stores the results of a rescan of the named checks, or of
+	// every check when none are named, additionally recording a
+	// scan.completed event even when no result changed. The other checks
+	// and the profile keep what is stored when the write happens, so
+	// results written while the scan ran are not lost.
+	SaveScan(ctx context.Context, project *models.Project, scanned []string) error
+
+	Delete(ctx context.Context, key models.ProjectKey) error
+
+	List(ctx context.Context, filter ProjectFilter, limit, offset int) ([]*models.Project, error)
+
+	Count(ctx context.Context, filter ProjectFilter) (int, error)
+
+	Stream(ctx context.Context, filter ProjectFilter) iter.Seq2[*models.Project, error]
+
+	Import(ctx context.Context, projects []*models.Project, opts ImportOptions) ([]ImportResult, error)
+
+	Batch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]BatchOutcome, bool, error)
+}
+
+// ProjectFilter narrows List and Count. The zero value matches every project.
+type ProjectFilter struct {
+	Instance string // Only projects on the named GitLab instance
+	Ready    *bool  // Only projects that pass (or fail) every check
+	Failing  string // Only projects failing the named check
+
+	// CodeOwner keeps only projects whose CODEOWNERS file names the owner,
+	// written as it is there, such as @alice or @platform/leads
+	CodeOwner string
+}
+
+// ConflictAction says what Import does with a project that already exists
+type ConflictAction string
+
+const (
+	ConflictSkip   ConflictAction = "skip"   // Leave the existing project untouched
+	ConflictUpdate ConflictAction = "update" // Overwrite the existing project's checks
+	ConflictFail   ConflictAction = "fail"   // Treat the row as an error
+)
+
+// ImportOptions controls ProjectRepository.Import
+type ImportOptions struct {
+	OnConflict ConflictAction
+
+	// Partial commits the rows that succeed. Otherwise the first failing row
+	// rolls back the whole import.
+	Partial bool
+}
+
+// ImportOutcome is what happened to one project in an import
+type ImportOutcome string
+
+const (
+	ImportCreated ImportOutcome = "created"
+	ImportUpdated ImportOutcome = "updated"
+	ImportSkipped ImportOutcome = "skipped"
+	ImportFailed  ImportOutcome = "failed"
+)
+
+// ImportResult pairs an outcome with the error behind ImportFailed
+type ImportResult struct {
+	Outcome ImportOutcome
+	Err     error
+}
+
+// ErrProjectExists is reported for existing projects under ConflictFail
+var ErrProjectExists = errors.New("project already exists")
+
+// ImportRowError is returned when a non-partial import is rolled back
+type ImportRowError struct {
+	Index int // Position in the projects passed to Import
+	Err   error
+}
+
+func (e *ImportRowError) Error() string {
+	return fmt.Sprintf("import rolled back at row %d: %v", e.Index, e.Err)
+}
+
+func (e *ImportRowError) Unwrap() error { return e.Err }
+
+// whereClause builds the SQL condition for the filter and its arguments,
+// numbered from $1. Check IDs are validated against the check registry.
+func (f ProjectFilter) whereClause() (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+
+	if f.Instance != "" {
+		args = append(args, f.Instance)
+		conditions = append(conditions, fmt.Sprintf("instance = $%d", len(args)))
+	}
+
+	if f.Ready != nil {
+		// Ready projects have a passing result for every registered check
+		ids := checks.IDs()
+		args = append(args, pq.Array(ids))
+		ready := fmt.Sprintf(`(SELECT count(*) FROM project_check_results r
+			WHERE r.instance = gitlab_projects.instance AND r.project_id = gitlab_projects.project_id
+				AND r.passed AND r.check_id = ANY($%d)) = %d`, len(args), len(ids))
+		if *f.Ready {
+			conditions = append(conditions, ready)
+		} else {
+			conditions = append(conditions, "NOT "+ready)
+		}
+	}
+
+	if f.Failing != "" {
+		if _, ok := checks.Lookup(f.Failing); !ok {
+			return "", nil, fmt.Errorf("unknown check: %s", f.Failing)
+		}
+		args = append(args, f.Failing)
+		conditions = append(conditions, fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM project_check_results r
+			WHERE r.instance = gitlab_projects.instance AND r.project_id = gitlab_projects.project_id
+				AND r.check_id = $%d AND r.passed)`, len(args)))
+	}
 
-	List(ctx context.Context, limit, offset int) ([]*models.Project, error)
+	if f.CodeOwner != "" {
+		args = append(args, f.CodeOwner)
+		conditions = append(conditions, fmt.Sprintf("code_owners @> ARRAY[$%d::text]", len(args)))
+	}
 
-	Count(ctx context.Context) (int, error)
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
 }
 
 type projectRepo struct {
@@ -32,82 +163,93 @@ func NewProjectRepository(db *database.DB) ProjectRepository {
 	return &projectRepo{db: db}
 }
 
-
 func (r *projectRepo) Create(ctx context.Context, project *models.Project) error {
 	query := `
 		INSERT INTO gitlab_projects (
-			project_id, project_present, app_name_set, moab_id_set,
-			codeowners_exists, branch_protection_enabled, codeowner_approval_required,
-			push_merge_restricted, force_push_disabled, push_rules_enabled,
-			min_approvals_required, author_approval_prevented, committer_approval_prevented,
-			approvals_removed_on_commit, created_at, updated_at
+			instance, project_id, gitlab_id, name, path_with_namespace,
+			default_branch, web_url, visibility, archived, created_at, updated_at, profile
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
+			$1, $2, NULLIF($3, 0), $4, $5, $6, $7, $8, $9, $10, $11, $12
 		)
 	`
 
+	project.Instance = project.Key().Instance
 	now := time.Now()
 	project.CreatedAt = now
 	project.UpdatedAt = now
+	project.CodeOwners = nil // Filled in by scans
+	project.LastScannedAt = nil
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
+		project.Instance,
 		project.ProjectID,
-		project.ProjectPresent,
-		project.AppNameSet,
-		project.MoabIDSet,
-		project.CodeownersExists,
-		project.BranchProtectionEnabled,
-		project.CodeownerApprovalRequired,
-		project.PushMergeRestricted,
-		project.ForcePushDisabled,
-		project.PushRulesEnabled,
-		project.MinApprovalsRequired,
-		project.AuthorApprovalPrevented,
-		project.CommitterApprovalPrevented,
-		project.ApprovalsRemovedOnCommit,
+		project.GitLabID,
+		project.Name,
+		project.PathWithNamespace,
+		project.DefaultBranch,
+		project.WebURL,
+		project.Visibility,
+		project.Archived,
 		project.CreatedAt,
 		project.UpdatedAt,
+		project.Profile,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to create project: %w", err)
 	}
 
+	if err := saveResults(ctx, tx, []*models.Project{project}); err != nil {
+		return err
+	}
+
+	if err := writeEvents(ctx, tx, events.Created(project)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project: %w", err)
+	}
+
 	return nil
 }
 
-func (r *projectRepo) GetByID(ctx context.Context, projectID string) (*models.Project, error) {
+func (r *projectRepo) GetByID(ctx context.Context, key models.ProjectKey) (*models.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM gitlab_projects WHERE instance = $1 AND project_id = $2`
+
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, key.Instance, key.ProjectID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("project not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+func (r *projectRepo) GetByRef(ctx context.Context, instance, ref string) (*models.Project, error) {
+	// GitLab paths are matched case-insensitively, as GitLab does
 	query := `
-		SELECT 
-			project_id, project_present, app_name_set, moab_id_set,
-			codeowners_exists, branch_protection_enabled, codeowner_approval_required,
-			push_merge_restricted, force_push_disabled, push_rules_enabled,
-			min_approvals_required, author_approval_prevented, committer_approval_prevented,
-			approvals_removed_on_commit, created_at, updated_at
-		FROM gitlab_projects
-		WHERE project_id = $1
+		SELECT ` + projectColumns + ` FROM gitlab_projects
+		WHERE instance = $1
+			AND (project_id = $2 OR gitlab_id = $3 OR lower(path_with_namespace) = lower($2))
+		ORDER BY project_id = $2 DESC, COALESCE(gitlab_id = $3, FALSE) DESC, project_id
+		LIMIT 1
 	`
 
-	project := &models.Project{}
-	err := r.db.QueryRowContext(ctx, query, projectID).Scan(
-		&project.ProjectID,
-		&project.ProjectPresent,
-		&project.AppNameSet,
-		&project.MoabIDSet,
-		&project.CodeownersExists,
-		&project.BranchProtectionEnabled,
-		&project.CodeownerApprovalRequired,
-		&project.PushMergeRestricted,
-		&project.ForcePushDisabled,
-		&project.PushRulesEnabled,
-		&project.MinApprovalsRequired,
-		&project.AuthorApprovalPrevented,
-		&project.CommitterApprovalPrevented,
-		&project.ApprovalsRemovedOnCommit,
-		&project.CreatedAt,
-		&project.UpdatedAt,
-	)
+	var gitlabID sql.NullInt64
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		gitlabID = sql.NullInt64{Int64: id, Valid: true}
+	}
 
+	project, err := scanProject(r.db.QueryRowContext(ctx, query, instance, ref, gitlabID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("project not found")
 	}
@@ -119,95 +261,162 @@ func (r *projectRepo) GetByID(ctx context.Context, projectID string) (*models.Pr
 }
 
 func (r *projectRepo) Update(ctx context.Context, project *models.Project) error {
+	return r.update(ctx, project, events.Diff, nil)
+}
+
+func (r *projectRepo) SaveScan(ctx context.Context, project *models.Project, scanned []string) error {
+	return r.update(ctx, project, events.Scanned, func(old *models.Project) {
+		project.Profile = old.Profile
+		project.KeepResults(old, scanned)
+		if len(scanned) == 0 {
+			scannedAt := project.UpdatedAt
+			project.LastScannedAt = &scannedAt
+		}
+	})
+}
+
+// update overwrites a project's checks, recording the events derive returns
+// for the stored and new versions. merge, if set, folds the stored version
+// into the new one once its row is locked.
+func (r *projectRepo) update(ctx context.Context, project *models.Project, derive func(old, new *models.Project) []models.Event, merge func(old *models.Project)) error {
 	query := `
 		UPDATE gitlab_projects SET
-			project_present = $2,
-			app_name_set = $3,
-			moab_id_set = $4,
-			codeowners_exists = $5,
-			branch_protection_enabled = $6,
-			codeowner_approval_required = $7,
-			push_merge_restricted = $8,
-			force_push_disabled = $9,
-			push_rules_enabled = $10,
-			min_approvals_required = $11,
-			author_approval_prevented = $12,
-			committer_approval_prevented = $13,
-			approvals_removed_on_commit = $14,
-			updated_at = $15
-		WHERE project_id = $1
+			gitlab_id = NULLIF($3, 0),
+			name = $4,
+			path_with_namespace = $5,
+			default_branch = $6,
+			web_url = $7,
+			visibility = $8,
+			archived = $9,
+			updated_at = $10,
+			profile = $11,
+			code_owners = $12,
+			last_scanned_at = $13
+		WHERE instance = $1 AND project_id = $2
 	`
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	key := project.Key()
+	stored, err := lockProjects(ctx, tx, []models.ProjectKey{key})
+	if err != nil {
+		return err
+	}
+	old, ok := stored[key]
+	if !ok {
+		return fmt.Errorf("project not found")
+	}
+
+	project.Instance = key.Instance
 	project.UpdatedAt = time.Now()
+	project.CreatedAt = old.CreatedAt
+	if project.ProjectMetadata.IsZero() {
+		project.ProjectMetadata = old.ProjectMetadata
+	}
+	if project.Profile == "" {
+		project.Profile = old.Profile
+	}
+	if project.CodeOwners == nil {
+		project.CodeOwners = old.CodeOwners
+	}
+	project.LastScannedAt = old.LastScannedAt
+	if merge != nil {
+		merge(old)
+	}
 
-	result, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
+		project.Instance,
 		project.ProjectID,
-		project.ProjectPresent,
-		project.AppNameSet,
-		project.MoabIDSet,
-		project.CodeownersExists,
-		project.BranchProtectionEnabled,
-		project.CodeownerApprovalRequired,
-		project.PushMergeRestricted,
-		project.ForcePushDisabled,
-		project.PushRulesEnabled,
-		project.MinApprovalsRequired,
-		project.AuthorApprovalPrevented,
-		project.CommitterApprovalPrevented,
-		project.ApprovalsRemovedOnCommit,
+		project.GitLabID,
+		project.Name,
+		project.PathWithNamespace,
+		project.DefaultBranch,
+		project.WebURL,
+		project.Visibility,
+		project.Archived,
 		project.UpdatedAt,
+		project.Profile,
+		pq.Array(project.CodeOwners),
+		project.LastScannedAt,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to update project: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := saveResults(ctx, tx, []*models.Project{project}); err != nil {
+		return err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("project not found")
+	if err := writeEvents(ctx, tx, derive(old, project)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit project: %w", err)
 	}
 
 	return nil
 }
 
-func (r *projectRepo) Delete(ctx context.Context, projectID string) error {
-	query := `DELETE FROM gitlab_projects WHERE project_id = $1`
+func (r *projectRepo) Delete(ctx context.Context, key models.ProjectKey) error {
+	query := `DELETE FROM gitlab_projects WHERE instance = $1 AND project_id = $2 RETURNING ` + projectColumns
 
-	result, err := r.db.ExecContext(ctx, query, projectID)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to delete project: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	rows, err := tx.QueryContext(ctx, query, key.Instance, key.ProjectID)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	var deleted *models.Project
+	for rows.Next() {
+		if deleted, err = scanProject(rows); err != nil {
+			rows.Close()
+			return err
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
 	}
 
-	if rowsAffected == 0 {
+	if deleted == nil {
 		return fmt.Errorf("project not found")
 	}
 
+	if err := writeEvents(ctx, tx, events.Deleted(deleted)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit delete: %w", err)
+	}
+
 	return nil
 }
 
-func (r *projectRepo) List(ctx context.Context, limit, offset int) ([]*models.Project, error) {
+func (r *projectRepo) List(ctx context.Context, filter ProjectFilter, limit, offset int) ([]*models.Project, error) {
+	where, args, err := filter.whereClause()
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT 
-			project_id, project_present, app_name_set, moab_id_set,
-			codeowners_exists, branch_protection_enabled, codeowner_approval_required,
-			push_merge_restricted, force_push_disabled, push_rules_enabled,
-			min_approvals_required, author_approval_prevented, committer_approval_prevented,
-			approvals_removed_on_commit, created_at, updated_at
-		FROM gitlab_projects
+		SELECT ` + projectColumns + ` FROM gitlab_projects
+		` + where + fmt.Sprintf(`
 		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+		LIMIT $%d OFFSET $%d
+	`, len(args)+1, len(args)+2)
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list projects: %w", err)
 	}
@@ -215,27 +424,9 @@ func (r *projectRepo) List(ctx context.Context, limit, offset int) ([]*models.Pr
 
 	var projects []*models.Project
 	for rows.Next() {
-		project := &models.Project{}
-		err := rows.Scan(
-			&project.ProjectID,
-			&project.ProjectPresent,
-			&project.AppNameSet,
-			&project.MoabIDSet,
-			&project.CodeownersExists,
-			&project.BranchProtectionEnabled,
-			&project.CodeownerApprovalRequired,
-			&project.PushMergeRestricted,
-			&project.ForcePushDisabled,
-			&project.PushRulesEnabled,
-			&project.MinApprovalsRequired,
-			&project.AuthorApprovalPrevented,
-			&project.CommitterApprovalPrevented,
-			&project.ApprovalsRemovedOnCommit,
-			&project.CreatedAt,
-			&project.UpdatedAt,
-		)
+		project, err := scanProject(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan project: %w", err)
+			return nil, err
 		}
 		projects = append(projects, project)
 	}
@@ -247,14 +438,363 @@ func (r *projectRepo) List(ctx context.Context, limit, offset int) ([]*models.Pr
 	return projects, nil
 }
 
-func (r *projectRepo) Count(ctx context.Context) (int, error) {
+// Stream yields every project matching the filter, ordered by instance and
+// project_id.
+// Rows are scanned as they arrive from the database rather than collected,
+// so memory use does not grow with the inventory. The first error ends the
+// sequence.
+func (r *projectRepo) Stream(ctx context.Context, filter ProjectFilter) iter.Seq2[*models.Project, error] {
+	return func(yield func(*models.Project, error) bool) {
+		where, args, err := filter.whereClause()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		query := `
+			SELECT ` + projectColumns + ` FROM gitlab_projects
+			` + where + `
+			ORDER BY instance, project_id
+		`
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to stream projects: %w", err))
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			project, err := scanProject(rows)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(project, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			yield(nil, fmt.Errorf("error iterating rows: %w", err))
+		}
+	}
+}
+
+func (r *projectRepo) Count(ctx context.Context, filter ProjectFilter) (int, error) {
+	where, args, err := filter.whereClause()
+	if err != nil {
+		return 0, err
+	}
+
 	var count int
-	query := `SELECT COUNT(*) FROM gitlab_projects`
+	query := `SELECT COUNT(*) FROM gitlab_projects ` + where
 
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count projects: %w", err)
 	}
 
 	return count, nil
 }
+
+// projectColumns is the full gitlab_projects column list read by
+// scanProject, with the project's check results gathered into a JSON object
+// keyed by check ID. It must select from gitlab_projects without an alias.
+const projectColumns = `
+	instance, project_id, profile, code_owners,
+	COALESCE((
+		SELECT jsonb_object_agg(r.check_id, jsonb_strip_nulls(jsonb_build_object(
+			'passed', r.passed, 'status', r.status, 'reason', r.reason,
+			'evidence', r.evidence, 'value', r.value, 'captured', r.captured,
+			'evaluated_at', r.evaluated_at
+		)))
+		FROM project_check_results r
+		WHERE r.instance = gitlab_projects.instance AND r.project_id = gitlab_projects.project_id
+	), '{}'),
+	COALESCE(gitlab_id, 0), name, path_with_namespace,
+	default_branch, web_url, visibility, archived, created_at, updated_at,
+	last_scanned_at
+`
+
+// storedResult is a project_check_results row as gathered by projectColumns
+type storedResult struct {
+	Passed bool `json:"passed"`
+	models.Evaluation
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProject reads a row selected with projectColumns. sql.ErrNoRows from a
+// *sql.Row is returned unwrapped.
+func scanProject(row rowScanner) (*models.Project, error) {
+	project := &models.Project{}
+	var results []byte
+	err := row.Scan(
+		&project.Instance,
+		&project.ProjectID,
+		&project.Profile,
+		pq.Array(&project.CodeOwners),
+		&results,
+		&project.GitLabID,
+		&project.Name,
+		&project.PathWithNamespace,
+		&project.DefaultBranch,
+		&project.WebURL,
+		&project.Visibility,
+		&project.Archived,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+		&project.LastScannedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan project: %w", err)
+	}
+	var stored map[string]storedResult
+	if err := json.Unmarshal(results, &stored); err != nil {
+		return nil, fmt.Errorf("failed to scan check results: %w", err)
+	}
+	project.Results = make(map[string]bool, len(stored))
+	project.Evaluations = make(map[string]models.Evaluation, len(stored))
+	for id, r := range stored {
+		project.Results[id] = r.Passed
+		project.Evaluations[id] = r.Evaluation
+	}
+	return project, nil
+}
+
+// saveResults stores the result and evaluation of every registered check for
+// the projects. Checks missing from a project's results are stored as failed
+// with an unknown status.
+func saveResults(ctx context.Context, tx *sql.Tx, projects []*models.Project) error {
+	// Empty evidence, values, captures and evaluation times stand for NULL,
+	// which arrays cannot carry through pq
+	query := `
+		INSERT INTO project_check_results (instance, project_id, check_id, passed, status, reason, evidence, value, captured, evaluated_at)
+		SELECT instance, project_id, check_id, passed, status, reason,
+			NULLIF(evidence, '')::jsonb, NULLIF(value, '')::integer, NULLIF(captured, ''), NULLIF(evaluated_at, '')::timestamptz
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::bool[], $5::text[], $6::text[], $7::text[], $8::text[], $9::text[], $10::text[])
+			AS v(instance, project_id, check_id, passed, status, reason, evidence, value, captured, evaluated_at)
+		ON CONFLICT (instance, project_id, check_id) DO UPDATE SET
+			passed = EXCLUDED.passed,
+			status = EXCLUDED.status,
+			reason = EXCLUDED.reason,
+			evidence = EXCLUDED.evidence,
+			value = EXCLUDED.value,
+			captured = EXCLUDED.captured,
+			evaluated_at = EXCLUDED.evaluated_at
+	`
+
+	ids := checks.IDs()
+	n := len(projects) * len(ids)
+	instances := make([]string, 0, n)
+	projectIDs := make([]string, 0, n)
+	checkIDs := make([]string, 0, n)
+	passed := make([]bool, 0, n)
+	statuses := make([]string, 0, n)
+	reasons := make([]string, 0, n)
+	evidence := make([]string, 0, n)
+	values := make([]string, 0, n)
+	captured := make([]string, 0, n)
+	evaluatedAt := make([]string, 0, n)
+	for _, project := range projects {
+		key := project.Key()
+		for _, id := range ids {
+			e := project.Evaluation(id)
+			instances = append(instances, key.Instance)
+			projectIDs = append(projectIDs, key.ProjectID)
+			checkIDs = append(checkIDs, id)
+			passed = append(passed, project.Result(id))
+			statuses = append(statuses, string(e.Status))
+			reasons = append(reasons, e.Reason)
+			evidence = append(evidence, string(e.Evidence))
+			value := ""
+			if e.Value != nil {
+				value = strconv.Itoa(*e.Value)
+			}
+			values = append(values, value)
+			captured = append(captured, e.Captured)
+			at := ""
+			if e.EvaluatedAt != nil {
+				at = e.EvaluatedAt.Format(time.RFC3339Nano)
+			}
+			evaluatedAt = append(evaluatedAt, at)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, query,
+		pq.Array(instances), pq.Array(projectIDs), pq.Array(checkIDs), pq.Array(passed),
+		pq.Array(statuses), pq.Array(reasons), pq.Array(evidence), pq.Array(values), pq.Array(captured), pq.Array(evaluatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save check results: %w", err)
+	}
+	return nil
+}
+
+// Import writes projects in a single transaction, returning one result per
+// project. Partial imports wrap each row in a savepoint so a failing row
+// does not abort the rest; otherwise the first failure rolls back every row
+// and is returned as an *ImportRowError.
+func (r *projectRepo) Import(ctx context.Context, projects []*models.Project, opts ImportOptions) ([]ImportResult, error) {
+	var conflict string
+	switch opts.OnConflict {
+	case ConflictSkip, ConflictFail, "":
+		conflict = `ON CONFLICT (instance, project_id) DO NOTHING RETURNING TRUE`
+	case ConflictUpdate:
+		conflict = `ON CONFLICT (instance, project_id) DO UPDATE SET
+			gitlab_id = EXCLUDED.gitlab_id,
+			name = EXCLUDED.name,
+			path_with_namespace = EXCLUDED.path_with_namespace,
+			default_branch = EXCLUDED.default_branch,
+			web_url = EXCLUDED.web_url,
+			visibility = EXCLUDED.visibility,
+			archived = EXCLUDED.archived,
+			updated_at = EXCLUDED.updated_at,
+			profile = EXCLUDED.profile
+		RETURNING (xmax = 0)`
+	default:
+		return nil, fmt.Errorf("unknown conflict action: %s", opts.OnConflict)
+	}
+
+	query := `
+		INSERT INTO gitlab_projects (
+			instance, project_id, gitlab_id, name, path_with_namespace,
+			default_branch, web_url, visibility, archived, created_at, updated_at, profile
+		) VALUES (
+			$1, $2, NULLIF($3, 0), $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)
+		` + conflict
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin import: %w", err)
+	}
+	defer tx.Rollback()
+
+	keys := make([]models.ProjectKey, len(projects))
+	for i, project := range projects {
+		keys[i] = project.Key()
+		project.Instance = keys[i].Instance
+	}
+	current, err := lockProjects(ctx, tx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare import: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	results := make([]ImportResult, len(projects))
+	var changes []models.Event
+
+	for i, project := range projects {
+		if opts.Partial {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT import_row`); err != nil {
+				return nil, fmt.Errorf("failed to create savepoint: %w", err)
+			}
+		}
+
+		project.CreatedAt = now
+		project.UpdatedAt = now
+		// Rows without metadata, like most imports, keep what scans found,
+		// and rows without a profile keep the stored one. Imports do not
+		// write code owners or scan times.
+		project.CodeOwners, project.LastScannedAt = nil, nil
+		if previous, ok := current[keys[i]]; ok {
+			project.CodeOwners, project.LastScannedAt = previous.CodeOwners, previous.LastScannedAt
+			if project.ProjectMetadata.IsZero() {
+				project.ProjectMetadata = previous.ProjectMetadata
+			}
+			if project.Profile == "" {
+				project.Profile = previous.Profile
+			}
+		}
+
+		var inserted bool
+		err := stmt.QueryRowContext(ctx,
+			project.Instance,
+			project.ProjectID,
+			project.GitLabID,
+			project.Name,
+			project.PathWithNamespace,
+			project.DefaultBranch,
+			project.WebURL,
+			project.Visibility,
+			project.Archived,
+			project.CreatedAt,
+			project.UpdatedAt,
+			project.Profile,
+		).Scan(&inserted)
+
+		switch {
+		case err == sql.ErrNoRows && opts.OnConflict == ConflictFail:
+			results[i] = ImportResult{Outcome: ImportFailed, Err: ErrProjectExists}
+		case err == sql.ErrNoRows:
+			results[i] = ImportResult{Outcome: ImportSkipped}
+		case err != nil:
+			results[i] = ImportResult{Outcome: ImportFailed, Err: fmt.Errorf("failed to import project: %w", err)}
+		case inserted:
+			results[i] = ImportResult{Outcome: ImportCreated}
+		default:
+			results[i] = ImportResult{Outcome: ImportUpdated}
+		}
+
+		if outcome := results[i].Outcome; outcome == ImportCreated || outcome == ImportUpdated {
+			if err := saveResults(ctx, tx, []*models.Project{project}); err != nil {
+				results[i] = ImportResult{Outcome: ImportFailed, Err: err}
+			}
+		}
+
+		if results[i].Outcome == ImportFailed {
+			if !opts.Partial {
+				return nil, &ImportRowError{Index: i, Err: results[i].Err}
+			}
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT import_row`); err != nil {
+				return nil, fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			continue
+		}
+		if opts.Partial {
+			if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT import_row`); err != nil {
+				return nil, fmt.Errorf("failed to release savepoint: %w", err)
+			}
+		}
+
+		if results[i].Outcome == ImportSkipped {
+			continue
+		}
+		// A project created by another transaction since the rows were
+		// locked has no stored version to compare with
+		if previous, ok := current[keys[i]]; ok {
+			project.CreatedAt = previous.CreatedAt
+			changes = append(changes, events.Diff(previous, project)...)
+		} else {
+			changes = append(changes, events.Created(project)...)
+		}
+		current[keys[i]] = project
+	}
+
+	if err := writeEvents(ctx, tx, changes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+
+	return results, nil
+}
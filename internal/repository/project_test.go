@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -24,26 +25,28 @@ func setupTestDB(t *testing.T) *database.DB {
 		t.Skipf("Skipping test - PostgreSQL not available: %v", err)
 	}
 
+	_, _ = db.Exec("DROP TABLE IF EXISTS project_check_results")
 	_, _ = db.Exec("DROP TABLE IF EXISTS gitlab_projects")
+	_, _ = db.Exec("DROP TABLE IF EXISTS event_outbox")
+	_, _ = db.Exec("DROP SEQUENCE IF EXISTS event_outbox_sequence")
 
 	schema := `
 		CREATE TABLE gitlab_projects (
-			project_id VARCHAR(255) PRIMARY KEY,
-			project_present BOOLEAN DEFAULT FALSE,
-			app_name_set BOOLEAN DEFAULT FALSE,
-			moab_id_set BOOLEAN DEFAULT FALSE,
-			codeowners_exists BOOLEAN DEFAULT FALSE,
-			branch_protection_enabled BOOLEAN DEFAULT FALSE,
-			codeowner_approval_required BOOLEAN DEFAULT FALSE,
-			push_merge_restricted BOOLEAN DEFAULT FALSE,
-			force_push_disabled BOOLEAN DEFAULT FALSE,
-			push_rules_enabled BOOLEAN DEFAULT FALSE,
-			min_approvals_required BOOLEAN DEFAULT FALSE,
-			author_approval_prevented BOOLEAN DEFAULT FALSE,
-			committer_approval_prevented BOOLEAN DEFAULT FALSE,
-			approvals_removed_on_commit BOOLEAN DEFAULT FALSE,
+			instance VARCHAR(255) NOT NULL,
+			project_id VARCHAR(255) NOT NULL,
+			gitlab_id BIGINT,
+			name TEXT NOT NULL DEFAULT '',
+			path_with_namespace TEXT NOT NULL DEFAULT '',
+			default_branch TEXT NOT NULL DEFAULT '',
+			web_url TEXT NOT NULL DEFAULT '',
+			visibility TEXT NOT NULL DEFAULT '',
+			archived BOOLEAN NOT NULL DEFAULT FALSE,
+			profile TEXT NOT NULL DEFAULT '',
+			code_owners TEXT[] NOT NULL DEFAULT '{}',
 			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			last_scanned_at TIMESTAMPTZ,
+			PRIMARY KEY (instance, project_id)
 		)
 	`
 
@@ -51,6 +54,48 @@ func setupTestDB(t *testing.T) *database.DB {
 		t.Fatalf("failed to create schema: %v", err)
 	}
 
+	results := `
+		CREATE TABLE project_check_results (
+			instance VARCHAR(255) NOT NULL,
+			project_id VARCHAR(255) NOT NULL,
+			check_id TEXT NOT NULL,
+			passed BOOLEAN NOT NULL,
+			status TEXT NOT NULL DEFAULT 'unknown',
+			reason TEXT NOT NULL DEFAULT '',
+			evidence JSONB,
+			value INTEGER,
+			captured TEXT,
+			evaluated_at TIMESTAMPTZ,
+			PRIMARY KEY (instance, project_id, check_id),
+			FOREIGN KEY (instance, project_id) REFERENCES gitlab_projects(instance, project_id) ON DELETE CASCADE
+		)
+	`
+
+	if _, err := db.Exec(results); err != nil {
+		t.Fatalf("failed to create check results: %v", err)
+	}
+
+	outbox := `
+		CREATE TABLE event_outbox (
+			id BIGSERIAL PRIMARY KEY,
+			event_id TEXT NOT NULL,
+			event_type TEXT NOT NULL,
+			instance TEXT NOT NULL,
+			project_id TEXT NOT NULL,
+			payload JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			published_at TIMESTAMP,
+			sequence BIGINT UNIQUE
+		)
+	`
+
+	if _, err := db.Exec(outbox); err != nil {
+		t.Fatalf("failed to create outbox: %v", err)
+	}
+	if _, err := db.Exec("CREATE SEQUENCE event_outbox_sequence"); err != nil {
+		t.Fatalf("failed to create outbox sequence: %v", err)
+	}
+
 	return db
 }
 
@@ -62,11 +107,13 @@ func TestProjectRepository_Create(t *testing.T) {
 	ctx := context.Background()
 
 	project := &models.Project{
-		ProjectID:        "test-123",
-		ProjectPresent:   true,
-		AppNameSet:       true,
-		MoabIDSet:        false,
-		CodeownersExists: true,
+		ProjectID: "test-123",
+		Results: map[string]bool{
+			"project_present":   true,
+			"app_name_set":      true,
+			"moab_id_set":       false,
+			"codeowners_exists": true,
+		},
 	}
 
 	err := repo.Create(ctx, project)
@@ -74,7 +121,7 @@ func TestProjectRepository_Create(t *testing.T) {
 		t.Fatalf("failed to create project: %v", err)
 	}
 
-	retrieved, err := repo.GetByID(ctx, "test-123")
+	retrieved, err := repo.GetByID(ctx, models.NewProjectKey("", "test-123"))
 	if err != nil {
 		t.Fatalf("failed to retrieve project: %v", err)
 	}
@@ -82,14 +129,14 @@ func TestProjectRepository_Create(t *testing.T) {
 	if retrieved.ProjectID != project.ProjectID {
 		t.Errorf("ProjectID = %v, want %v", retrieved.ProjectID, project.ProjectID)
 	}
-	if retrieved.ProjectPresent != project.ProjectPresent {
-		t.Errorf("ProjectPresent = %v, want %v", retrieved.ProjectPresent, project.ProjectPresent)
+	if retrieved.Result("project_present") != project.Result("project_present") {
+		t.Errorf("project_present = %v, want %v", retrieved.Result("project_present"), project.Result("project_present"))
 	}
-	if retrieved.AppNameSet != project.AppNameSet {
-		t.Errorf("AppNameSet = %v, want %v", retrieved.AppNameSet, project.AppNameSet)
+	if retrieved.Result("app_name_set") != project.Result("app_name_set") {
+		t.Errorf("app_name_set = %v, want %v", retrieved.Result("app_name_set"), project.Result("app_name_set"))
 	}
-	if retrieved.MoabIDSet != project.MoabIDSet {
-		t.Errorf("MoabIDSet = %v, want %v", retrieved.MoabIDSet, project.MoabIDSet)
+	if retrieved.Result("moab_id_set") != project.Result("moab_id_set") {
+		t.Errorf("moab_id_set = %v, want %v", retrieved.Result("moab_id_set"), project.Result("moab_id_set"))
 	}
 
 	err = repo.Create(ctx, project)
@@ -106,33 +153,35 @@ func TestProjectRepository_Update(t *testing.T) {
 	ctx := context.Background()
 
 	project := &models.Project{
-		ProjectID:      "update-test",
-		ProjectPresent: true,
-		AppNameSet:     false,
-		MoabIDSet:      false,
+		ProjectID: "update-test",
+		Results: map[string]bool{
+			"project_present": true,
+			"app_name_set":    false,
+			"moab_id_set":     false,
+		},
 	}
 
 	if err := repo.Create(ctx, project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
 
-	project.AppNameSet = true
-	project.MoabIDSet = true
+	project.SetCheck("app_name_set", true)
+	project.SetCheck("moab_id_set", true)
 
 	if err := repo.Update(ctx, project); err != nil {
 		t.Fatalf("failed to update project: %v", err)
 	}
 
-	retrieved, err := repo.GetByID(ctx, "update-test")
+	retrieved, err := repo.GetByID(ctx, models.NewProjectKey("", "update-test"))
 	if err != nil {
 		t.Fatalf("failed to retrieve project: %v", err)
 	}
 
-	if !retrieved.AppNameSet {
-		t.Error("AppNameSet should be true after update")
+	if !retrieved.Result("app_name_set") {
+		t.Error("app_name_set should be true after update")
 	}
-	if !retrieved.MoabIDSet {
-		t.Error("MoabIDSet should be true after update")
+	if !retrieved.Result("moab_id_set") {
+		t.Error("moab_id_set should be true after update")
 	}
 
 	nonExistent := &models.Project{
@@ -152,29 +201,117 @@ func TestProjectRepository_Delete(t *testing.T) {
 	ctx := context.Background()
 
 	project := &models.Project{
-		ProjectID:      "delete-test",
-		ProjectPresent: true,
+		ProjectID: "delete-test",
+		Results:   map[string]bool{"project_present": true},
 	}
 
 	if err := repo.Create(ctx, project); err != nil {
 		t.Fatalf("failed to create project: %v", err)
 	}
 
-	if err := repo.Delete(ctx, "delete-test"); err != nil {
+	if err := repo.Delete(ctx, models.NewProjectKey("", "delete-test")); err != nil {
 		t.Fatalf("failed to delete project: %v", err)
 	}
 
-	_, err := repo.GetByID(ctx, "delete-test")
+	_, err := repo.GetByID(ctx, models.NewProjectKey("", "delete-test"))
 	if err == nil {
 		t.Error("expected error when getting deleted project")
 	}
 
-	err = repo.Delete(ctx, "does-not-exist")
+	err = repo.Delete(ctx, models.NewProjectKey("", "does-not-exist"))
 	if err == nil {
 		t.Error("expected error when deleting non-existent project")
 	}
 }
 
+func TestProjectRepository_Instances(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	// The same GitLab project ID on two instances is two projects
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Project{Instance: "onprem", ProjectID: "42", Results: map[string]bool{"project_present": true}}); err != nil {
+		t.Fatalf("failed to create project on second instance: %v", err)
+	}
+
+	saas, err := repo.GetByID(ctx, models.NewProjectKey("", "42"))
+	if err != nil {
+		t.Fatalf("failed to retrieve project: %v", err)
+	}
+	if saas.Instance != models.DefaultInstance || saas.Result("project_present") {
+		t.Errorf("default instance project = %+v", saas)
+	}
+
+	count, err := repo.Count(ctx, ProjectFilter{Instance: "onprem"})
+	if err != nil {
+		t.Fatalf("failed to count projects: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Count(onprem) = %d, want 1", count)
+	}
+
+	if err := repo.Delete(ctx, models.NewProjectKey("onprem", "42")); err != nil {
+		t.Fatalf("failed to delete project: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, models.NewProjectKey("", "42")); err != nil {
+		t.Errorf("deleting onprem/42 removed the default instance project: %v", err)
+	}
+}
+
+func TestProjectRepository_GetByRef(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	project := &models.Project{
+		ProjectID: "payments",
+		ProjectMetadata: models.ProjectMetadata{
+			GitLabID:          42,
+			Name:              "Payments API",
+			PathWithNamespace: "platform/payments-api",
+			DefaultBranch:     "main",
+		},
+	}
+	if err := repo.Create(ctx, project); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	for _, ref := range []string{"payments", "42", "Platform/Payments-API"} {
+		found, err := repo.GetByRef(ctx, models.DefaultInstance, ref)
+		if err != nil {
+			t.Errorf("GetByRef(%q) error = %v", ref, err)
+			continue
+		}
+		if found.ProjectID != "payments" || found.GitLabID != 42 {
+			t.Errorf("GetByRef(%q) = %+v", ref, found)
+		}
+	}
+	if _, err := repo.GetByRef(ctx, "onprem", "42"); err == nil || err.Error() != "project not found" {
+		t.Errorf("GetByRef on another instance error = %v, want project not found", err)
+	}
+
+	// Writes without metadata keep what was stored
+	project.ProjectMetadata = models.ProjectMetadata{}
+	project.SetCheck("app_name_set", true)
+	if err := repo.Update(ctx, project); err != nil {
+		t.Fatalf("failed to update project: %v", err)
+	}
+	updated, err := repo.GetByID(ctx, project.Key())
+	if err != nil {
+		t.Fatalf("failed to retrieve project: %v", err)
+	}
+	if updated.PathWithNamespace != "platform/payments-api" || !updated.Result("app_name_set") {
+		t.Errorf("updated project = %+v", updated)
+	}
+}
+
 func TestProjectRepository_List(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -183,11 +320,11 @@ func TestProjectRepository_List(t *testing.T) {
 	ctx := context.Background()
 
 	projects := []models.Project{
-		{ProjectID: "proj-1", ProjectPresent: true},
-		{ProjectID: "proj-2", ProjectPresent: true},
-		{ProjectID: "proj-3", ProjectPresent: true},
-		{ProjectID: "proj-4", ProjectPresent: true},
-		{ProjectID: "proj-5", ProjectPresent: true},
+		{ProjectID: "proj-1", Results: map[string]bool{"project_present": true}},
+		{ProjectID: "proj-2", Results: map[string]bool{"project_present": true}},
+		{ProjectID: "proj-3", Results: map[string]bool{"project_present": true}},
+		{ProjectID: "proj-4", Results: map[string]bool{"project_present": true}},
+		{ProjectID: "proj-5", Results: map[string]bool{"project_present": true}},
 	}
 
 	for i := range projects {
@@ -212,7 +349,7 @@ func TestProjectRepository_List(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, err := repo.List(ctx, tt.limit, tt.offset)
+			results, err := repo.List(ctx, ProjectFilter{}, tt.limit, tt.offset)
 			if err != nil {
 				t.Fatalf("failed to list projects: %v", err)
 			}
@@ -223,7 +360,7 @@ func TestProjectRepository_List(t *testing.T) {
 		})
 	}
 
-	count, err := repo.Count(ctx)
+	count, err := repo.Count(ctx, ProjectFilter{})
 	if err != nil {
 		t.Fatalf("failed to count projects: %v", err)
 	}
@@ -233,6 +370,290 @@ func TestProjectRepository_List(t *testing.T) {
 	}
 }
 
+func TestProjectRepository_ListFilter(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	ready := models.Project{
+		ProjectID: "ready",
+		Results: map[string]bool{
+			"project_present":              true,
+			"app_name_set":                 true,
+			"moab_id_set":                  true,
+			"codeowners_exists":            true,
+			"branch_protection_enabled":    true,
+			"codeowner_approval_required":  true,
+			"push_merge_restricted":        true,
+			"force_push_disabled":          true,
+			"push_rules_enabled":           true,
+			"min_approvals_required":       true,
+			"author_approval_prevented":    true,
+			"committer_approval_prevented": true,
+			"approvals_removed_on_commit":  true,
+		},
+	}
+	missingOwners := *ready.Clone()
+	missingOwners.ProjectID = "missing-owners"
+	missingOwners.SetCheck("codeowners_exists", false)
+
+	for _, p := range []models.Project{ready, missingOwners, {ProjectID: "empty"}} {
+		if err := repo.Create(ctx, &p); err != nil {
+			t.Fatalf("failed to create project %s: %v", p.ProjectID, err)
+		}
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter ProjectFilter
+		want   int
+	}{
+		{"no filter", ProjectFilter{}, 3},
+		{"ready", ProjectFilter{Ready: &yes}, 1},
+		{"not ready", ProjectFilter{Ready: &no}, 2},
+		{"failing codeowners", ProjectFilter{Failing: "codeowners_exists"}, 2},
+		{"not ready and failing codeowners", ProjectFilter{Ready: &no, Failing: "codeowners_exists"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.List(ctx, tt.filter, 10, 0)
+			if err != nil {
+				t.Fatalf("failed to list projects: %v", err)
+			}
+			if len(results) != tt.want {
+				t.Errorf("List() returned %d items, want %d", len(results), tt.want)
+			}
+
+			count, err := repo.Count(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("failed to count projects: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("Count() = %d, want %d", count, tt.want)
+			}
+		})
+	}
+
+	if _, err := repo.List(ctx, ProjectFilter{Failing: "bogus; DROP TABLE"}, 10, 0); err == nil {
+		t.Error("expected error for unknown check name")
+	}
+}
+
+func TestProjectRepository_Stream(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	for _, p := range []models.Project{{ProjectID: "b"}, {ProjectID: "a", Results: map[string]bool{"codeowners_exists": true}}, {ProjectID: "c"}} {
+		if err := repo.Create(ctx, &p); err != nil {
+			t.Fatalf("failed to create project %s: %v", p.ProjectID, err)
+		}
+	}
+
+	var ids []string
+	for project, err := range repo.Stream(ctx, ProjectFilter{Failing: "codeowners_exists"}) {
+		if err != nil {
+			t.Fatalf("Stream() error = %v", err)
+		}
+		ids = append(ids, project.ProjectID)
+	}
+	if len(ids) != 2 || ids[0] != "b" || ids[1] != "c" {
+		t.Errorf("Stream() yielded %v, want [b c]", ids)
+	}
+}
+
+func TestProjectRepository_Import(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "existing"}); err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+
+	batch := func() []*models.Project {
+		return []*models.Project{
+			{ProjectID: "new", Results: map[string]bool{"codeowners_exists": true}},
+			{ProjectID: "existing", Results: map[string]bool{"codeowners_exists": true}},
+		}
+	}
+
+	// A conflict under ConflictFail rolls back the new project too
+	_, err := repo.Import(ctx, batch(), ImportOptions{OnConflict: ConflictFail})
+	var rowErr *ImportRowError
+	if !errors.As(err, &rowErr) || rowErr.Index != 1 || !errors.Is(err, ErrProjectExists) {
+		t.Fatalf("Import(fail) error = %v, want ImportRowError at index 1", err)
+	}
+	if _, err := repo.GetByID(ctx, models.NewProjectKey("", "new")); err == nil {
+		t.Error("expected rolled back import to leave no new project")
+	}
+
+	tests := []struct {
+		name string
+		opts ImportOptions
+		want []ImportOutcome
+	}{
+		{"partial fail", ImportOptions{OnConflict: ConflictFail, Partial: true}, []ImportOutcome{ImportCreated, ImportFailed}},
+		{"skip", ImportOptions{OnConflict: ConflictSkip}, []ImportOutcome{ImportSkipped, ImportSkipped}},
+		{"update", ImportOptions{OnConflict: ConflictUpdate}, []ImportOutcome{ImportUpdated, ImportUpdated}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := repo.Import(ctx, batch(), tt.opts)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			for i, want := range tt.want {
+				if results[i].Outcome != want {
+					t.Errorf("results[%d] = %s, want %s", i, results[i].Outcome, want)
+				}
+			}
+		})
+	}
+
+	existing, err := repo.GetByID(ctx, models.NewProjectKey("", "existing"))
+	if err != nil {
+		t.Fatalf("failed to retrieve project: %v", err)
+	}
+	if !existing.Result("codeowners_exists") {
+		t.Error("expected update import to overwrite checks")
+	}
+}
+
+func TestProjectRepository_Batch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	for _, id := range []string{"existing", "doomed"} {
+		if err := repo.Create(ctx, &models.Project{ProjectID: id}); err != nil {
+			t.Fatalf("failed to create project %s: %v", id, err)
+		}
+	}
+
+	ops := []models.BatchOperation{
+		{Op: models.BatchCreate, ProjectID: "new", Project: &models.Project{ProjectID: "new"}},
+		{Op: models.BatchUpdate, ProjectID: "existing", Project: &models.Project{ProjectID: "existing", Results: map[string]bool{"codeowners_exists": true}}},
+		{Op: models.BatchUpsert, ProjectID: "upserted", Project: &models.Project{ProjectID: "upserted"}},
+		{Op: models.BatchDelete, ProjectID: "doomed"},
+		{Op: models.BatchDelete, ProjectID: "missing"},
+	}
+	want := []BatchOutcome{BatchCreated, BatchUpdated, BatchCreated, BatchDeleted, BatchNotFound}
+
+	outcomes, committed, err := repo.Batch(ctx, ops, true)
+	if err != nil {
+		t.Fatalf("Batch(atomic) error = %v", err)
+	}
+	if committed {
+		t.Error("expected atomic batch with a missing project to roll back")
+	}
+	if _, err := repo.GetByID(ctx, models.NewProjectKey("", "new")); err == nil {
+		t.Error("expected rolled back batch to leave no new project")
+	}
+
+	outcomes, committed, err = repo.Batch(ctx, ops, false)
+	if err != nil || !committed {
+		t.Fatalf("Batch(best effort) = %v, %v", committed, err)
+	}
+	for i, outcome := range outcomes {
+		if outcome != want[i] {
+			t.Errorf("outcomes[%d] = %s, want %s", i, outcome, want[i])
+		}
+	}
+
+	existing, err := repo.GetByID(ctx, models.NewProjectKey("", "existing"))
+	if err != nil || !existing.Result("codeowners_exists") {
+		t.Errorf("expected existing to be updated, got %+v, %v", existing, err)
+	}
+}
+
+func TestProjectRepository_Outbox(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewProjectRepository(db)
+	outbox := NewOutboxRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "1"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.Update(ctx, &models.Project{ProjectID: "1", Results: map[string]bool{"codeowners_exists": true}}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err := repo.Delete(ctx, models.NewProjectKey("", "1")); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	// A failed write stores no events
+	if err := repo.Update(ctx, &models.Project{ProjectID: "missing"}); err == nil {
+		t.Fatal("Update() of a missing project succeeded")
+	}
+
+	var types []string
+	n, err := outbox.Drain(ctx, 10, func(ctx context.Context, events []models.Event) error {
+		for _, event := range events {
+			types = append(types, event.Type)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	want := []string{models.EventProjectCreated, models.EventProjectUpdated, models.EventCheckChanged, models.EventProjectDeleted}
+	if n != len(want) || len(types) != len(want) {
+		t.Fatalf("Drain() published %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, types[i], want[i])
+		}
+	}
+
+	// A failing publish leaves events in the outbox
+	repo.Create(ctx, &models.Project{ProjectID: "2"})
+	if _, err := outbox.Drain(ctx, 10, func(context.Context, []models.Event) error { return errors.New("sink down") }); err == nil {
+		t.Error("Drain() with a failing publish returned nil")
+	}
+	n, err = outbox.Drain(ctx, 10, func(context.Context, []models.Event) error { return nil })
+	if err != nil || n != 1 {
+		t.Errorf("Drain() after failure = %d, %v, want the retried event", n, err)
+	}
+
+	// Published events read back in sequence order, skipping the numbers
+	// reserved by the failed drain
+	published, err := outbox.Since(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+	if len(published) != len(want)+1 {
+		t.Fatalf("Since() returned %d events, want %d", len(published), len(want)+1)
+	}
+	for i, event := range published {
+		if i > 0 && event.Sequence <= published[i-1].Sequence {
+			t.Errorf("event %d sequence = %d, want above %d", i, event.Sequence, published[i-1].Sequence)
+		}
+	}
+	last, err := outbox.LastSequence(ctx)
+	if err != nil || last != published[len(published)-1].Sequence {
+		t.Errorf("LastSequence() = %d, %v, want %d", last, err, published[len(published)-1].Sequence)
+	}
+	if rest, err := outbox.Since(ctx, published[1].Sequence, 10); err != nil || len(rest) != len(published)-2 {
+		t.Errorf("Since(second) = %d events, %v, want %d", len(rest), err, len(published)-2)
+	}
+}
+
 func TestProjectRepository_GetByID_NotFound(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -240,7 +661,7 @@ func TestProjectRepository_GetByID_NotFound(t *testing.T) {
 	repo := NewProjectRepository(db)
 	ctx := context.Background()
 
-	_, err := repo.GetByID(ctx, "does-not-exist")
+	_, err := repo.GetByID(ctx, models.NewProjectKey("", "does-not-exist"))
 	if err == nil {
 		t.Error("expected error when getting non-existent project")
 	}
@@ -0,0 +1,49 @@
+package repotest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.ApplicationRepository = (*ApplicationRepository)(nil)
+
+// ApplicationRepository is an in-memory repository.ApplicationRepository
+type ApplicationRepository struct {
+	mu   sync.Mutex
+	apps []models.Application
+}
+
+func NewApplicationRepository() *ApplicationRepository {
+	return &ApplicationRepository{}
+}
+
+func (m *ApplicationRepository) Replace(ctx context.Context, apps []*models.Application) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.apps = make([]models.Application, len(apps))
+	for i, app := range apps {
+		app.CreatedAt = now
+		m.apps[i] = *app
+	}
+	slices.SortFunc(m.apps, func(a, b models.Application) int { return cmp.Compare(a.Name, b.Name) })
+	return nil
+}
+
+func (m *ApplicationRepository) List(ctx context.Context) ([]*models.Application, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var apps []*models.Application
+	for _, app := range m.apps {
+		apps = append(apps, &app)
+	}
+	return apps, nil
+}
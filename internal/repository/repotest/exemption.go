@@ -0,0 +1,64 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.ExemptionRepository = (*ExemptionRepository)(nil)
+
+// ExemptionRepository is an in-memory repository.ExemptionRepository
+type ExemptionRepository struct {
+	mu         sync.Mutex
+	nextID     int64
+	exemptions []*models.Exemption
+}
+
+func NewExemptionRepository() *ExemptionRepository {
+	return &ExemptionRepository{}
+}
+
+func (m *ExemptionRepository) Create(ctx context.Context, exemption *models.Exemption) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	exemption.ID = m.nextID
+	exemption.Instance = models.NewProjectKey(exemption.Instance, exemption.ProjectID).Instance
+	exemption.CreatedAt = time.Now()
+	stored := *exemption
+	m.exemptions = append(m.exemptions, &stored)
+	return nil
+}
+
+func (m *ExemptionRepository) ListByProject(ctx context.Context, key models.ProjectKey) ([]*models.Exemption, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var exemptions []*models.Exemption
+	for _, e := range m.exemptions {
+		if e.Instance == key.Instance && e.ProjectID == key.ProjectID {
+			found := *e
+			exemptions = append(exemptions, &found)
+		}
+	}
+	return exemptions, nil
+}
+
+func (m *ExemptionRepository) Delete(ctx context.Context, key models.ProjectKey, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, e := range m.exemptions {
+		if e.Instance == key.Instance && e.ProjectID == key.ProjectID && e.ID == id {
+			m.exemptions = append(m.exemptions[:i], m.exemptions[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("exemption not found")
+}
@@ -0,0 +1,83 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.IdempotencyRepository = (*IdempotencyRepository)(nil)
+
+// IdempotencyRepository is an in-memory repository.IdempotencyRepository
+type IdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{records: make(map[string]*models.IdempotencyRecord)}
+}
+
+func (m *IdempotencyRepository) Claim(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := m.records[key]; ok {
+		abandoned := !existing.Completed() && existing.CreatedAt.Before(staleBefore)
+		if !existing.ExpiresAt.Before(now) && !abandoned {
+			found := *existing
+			return &found, nil
+		}
+	}
+
+	m.records[key] = &models.IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   expiresAt,
+	}
+	return nil, nil
+}
+
+func (m *IdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.records[record.Key]
+	if !ok {
+		return repository.ErrIdempotencyKeyNotFound
+	}
+	existing.StatusCode = record.StatusCode
+	existing.Headers = record.Headers.Clone()
+	existing.Body = append([]byte(nil), record.Body...)
+	return nil
+}
+
+func (m *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if existing, ok := m.records[key]; ok && !existing.Completed() {
+		delete(m.records, key)
+	}
+	return nil
+}
+
+func (m *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	now := time.Now()
+	for key, record := range m.records {
+		if record.ExpiresAt.Before(now) {
+			delete(m.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
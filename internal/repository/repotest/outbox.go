@@ -0,0 +1,151 @@
+package repotest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.OutboxRepository = (*OutboxRepository)(nil)
+
+type outboxEntry struct {
+	id          int64
+	event       models.Event
+	sequence    int64
+	publishedAt *time.Time
+}
+
+// OutboxRepository is an in-memory repository.OutboxRepository, filled by
+// the ProjectRepository that owns it
+type OutboxRepository struct {
+	mu       sync.Mutex
+	draining sync.Mutex
+	nextID   int64
+	sequence int64 // Last sequence number handed out
+	entries  []*outboxEntry
+}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{}
+}
+
+// write appends events as one atomic step, like the outbox insert that
+// shares the project write's transaction
+func (m *OutboxRepository) write(events []models.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, event := range events {
+		m.nextID++
+		m.entries = append(m.entries, &outboxEntry{id: m.nextID, event: event})
+	}
+}
+
+func (m *OutboxRepository) Drain(ctx context.Context, limit int, publish func(context.Context, []models.Event) error) (int, error) {
+	if !m.draining.TryLock() {
+		return 0, nil
+	}
+	defer m.draining.Unlock()
+
+	m.mu.Lock()
+	var pending []*outboxEntry
+	for _, entry := range m.entries {
+		if len(pending) == limit {
+			break
+		}
+		if entry.publishedAt == nil {
+			pending = append(pending, entry)
+		}
+	}
+	m.mu.Unlock()
+
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	m.mu.Lock()
+	events := make([]models.Event, len(pending))
+	for i, entry := range pending {
+		m.sequence++
+		events[i] = entry.event
+		events[i].Sequence = m.sequence
+	}
+	m.mu.Unlock()
+
+	if err := publish(ctx, events); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for i, entry := range pending {
+		entry.sequence = events[i].Sequence
+		entry.publishedAt = &now
+	}
+	return len(pending), nil
+}
+
+func (m *OutboxRepository) Since(ctx context.Context, sequence int64, limit int) ([]models.Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var published []*outboxEntry
+	for _, entry := range m.entries {
+		if entry.publishedAt != nil && entry.sequence > sequence {
+			published = append(published, entry)
+		}
+	}
+	slices.SortFunc(published, func(a, b *outboxEntry) int {
+		return cmp.Compare(a.sequence, b.sequence)
+	})
+
+	var events []models.Event
+	for _, entry := range published[:min(limit, len(published))] {
+		event := entry.event
+		event.Sequence = entry.sequence
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (m *OutboxRepository) LastSequence(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var last int64
+	for _, entry := range m.entries {
+		last = max(last, entry.sequence)
+	}
+	return last, nil
+}
+
+func (m *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(m.entries)
+	m.entries = slices.DeleteFunc(m.entries, func(entry *outboxEntry) bool {
+		return entry.publishedAt != nil && entry.publishedAt.Before(before)
+	})
+	return int64(n - len(m.entries)), nil
+}
+
+// Pending returns the events not yet published, oldest first
+func (m *OutboxRepository) Pending() []models.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []models.Event
+	for _, entry := range m.entries {
+		if entry.publishedAt == nil {
+			events = append(events, entry.event)
+		}
+	}
+	return events
+}
@@ -0,0 +1,351 @@
+// Package repotest provides in-memory repository implementations for tests.
+package repotest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/events"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.ProjectRepository = (*ProjectRepository)(nil)
+
+// ProjectRepository is an in-memory repository.ProjectRepository for tests
+// that exercise handlers or clients without PostgreSQL. Like the PostgreSQL
+// implementation it records the events for every write in its outbox.
+type ProjectRepository struct {
+	mu       sync.Mutex
+	projects map[models.ProjectKey]*models.Project
+	outbox   *OutboxRepository
+}
+
+func NewProjectRepository() *ProjectRepository {
+	return &ProjectRepository{
+		projects: make(map[models.ProjectKey]*models.Project),
+		outbox:   NewOutboxRepository(),
+	}
+}
+
+// Outbox returns the outbox the repository writes its events to
+func (m *ProjectRepository) Outbox() *OutboxRepository {
+	return m.outbox
+}
+
+func (m *ProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := project.Key()
+	if _, ok := m.projects[key]; ok {
+		return fmt.Errorf("failed to create project: duplicate key")
+	}
+	now := time.Now()
+	project.Instance = key.Instance
+	project.CreatedAt = now
+	project.UpdatedAt = now
+	project.CodeOwners = nil // Filled in by scans
+	project.LastScannedAt = nil
+	m.projects[key] = project.Clone()
+	m.outbox.write(events.Created(project))
+	return nil
+}
+
+func (m *ProjectRepository) GetByID(ctx context.Context, key models.ProjectKey) (*models.Project, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	project, ok := m.projects[key]
+	if !ok {
+		return nil, fmt.Errorf("project not found")
+	}
+	return project.Clone(), nil
+}
+
+// GetByRef mirrors the PostgreSQL implementation's order of preference
+func (m *ProjectRepository) GetByRef(ctx context.Context, instance, ref string) (*models.Project, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if project, ok := m.projects[models.NewProjectKey(instance, ref)]; ok {
+		return project.Clone(), nil
+	}
+
+	var byPath *models.Project
+	for _, key := range m.matching(repository.ProjectFilter{Instance: instance}) {
+		project := m.projects[key]
+		if project.GitLabID != 0 && strconv.Itoa(project.GitLabID) == ref {
+			return project.Clone(), nil
+		}
+		if byPath == nil && project.PathWithNamespace != "" && strings.EqualFold(project.PathWithNamespace, ref) {
+			byPath = project
+		}
+	}
+	if byPath == nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	return byPath.Clone(), nil
+}
+
+func (m *ProjectRepository) Update(ctx context.Context, project *models.Project) error {
+	return m.update(project, events.Diff, nil)
+}
+
+func (m *ProjectRepository) SaveScan(ctx context.Context, project *models.Project, scanned []string) error {
+	return m.update(project, events.Scanned, func(old *models.Project) {
+		project.Profile = old.Profile
+		project.KeepResults(old, scanned)
+		if len(scanned) == 0 {
+			scannedAt := project.UpdatedAt
+			project.LastScannedAt = &scannedAt
+		}
+	})
+}
+
+func (m *ProjectRepository) update(project *models.Project, derive func(old, new *models.Project) []models.Event, merge func(old *models.Project)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := project.Key()
+	existing, ok := m.projects[key]
+	if !ok {
+		return fmt.Errorf("project not found")
+	}
+	project.Instance = key.Instance
+	project.CreatedAt = existing.CreatedAt
+	project.UpdatedAt = time.Now()
+	if project.ProjectMetadata.IsZero() {
+		project.ProjectMetadata = existing.ProjectMetadata
+	}
+	if project.Profile == "" {
+		project.Profile = existing.Profile
+	}
+	if project.CodeOwners == nil {
+		project.CodeOwners = existing.CodeOwners
+	}
+	project.LastScannedAt = existing.LastScannedAt
+	if merge != nil {
+		merge(existing)
+	}
+	m.projects[key] = project.Clone()
+	m.outbox.write(derive(existing, project))
+	return nil
+}
+
+func (m *ProjectRepository) Delete(ctx context.Context, key models.ProjectKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.projects[key]
+	if !ok {
+		return fmt.Errorf("project not found")
+	}
+	delete(m.projects, key)
+	m.outbox.write(events.Deleted(existing))
+	return nil
+}
+
+func (m *ProjectRepository) List(ctx context.Context, filter repository.ProjectFilter, limit, offset int) ([]*models.Project, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := m.matching(filter)
+
+	var projects []*models.Project
+	for i := offset; i < len(keys) && i < offset+limit; i++ {
+		projects = append(projects, m.projects[keys[i]].Clone())
+	}
+	return projects, nil
+}
+
+func (m *ProjectRepository) Count(ctx context.Context, filter repository.ProjectFilter) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.matching(filter)), nil
+}
+
+func (m *ProjectRepository) Stream(ctx context.Context, filter repository.ProjectFilter) iter.Seq2[*models.Project, error] {
+	return func(yield func(*models.Project, error) bool) {
+		m.mu.Lock()
+		var projects []*models.Project
+		for _, key := range m.matching(filter) {
+			projects = append(projects, m.projects[key].Clone())
+		}
+		m.mu.Unlock()
+
+		for _, project := range projects {
+			if !yield(project, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Import mirrors the PostgreSQL implementation, staging writes so that a
+// failing row in a non-partial import leaves the repository untouched
+func (m *ProjectRepository) Import(ctx context.Context, projects []*models.Project, opts repository.ImportOptions) ([]repository.ImportResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	staged := maps.Clone(m.projects)
+	now := time.Now()
+	results := make([]repository.ImportResult, len(projects))
+	var changes []models.Event
+
+	for i, project := range projects {
+		key := project.Key()
+		project.Instance = key.Instance
+		existing, exists := staged[key]
+		switch {
+		case !exists:
+			results[i].Outcome = repository.ImportCreated
+		case opts.OnConflict == repository.ConflictUpdate:
+			results[i].Outcome = repository.ImportUpdated
+		case opts.OnConflict == repository.ConflictFail:
+			results[i] = repository.ImportResult{Outcome: repository.ImportFailed, Err: repository.ErrProjectExists}
+			if !opts.Partial {
+				return nil, &repository.ImportRowError{Index: i, Err: results[i].Err}
+			}
+			continue
+		default:
+			results[i].Outcome = repository.ImportSkipped
+			continue
+		}
+
+		project.CreatedAt = now
+		project.UpdatedAt = now
+		project.CodeOwners, project.LastScannedAt = nil, nil
+		if exists {
+			project.CreatedAt = existing.CreatedAt
+			project.CodeOwners, project.LastScannedAt = existing.CodeOwners, existing.LastScannedAt
+			if project.ProjectMetadata.IsZero() {
+				project.ProjectMetadata = existing.ProjectMetadata
+			}
+			if project.Profile == "" {
+				project.Profile = existing.Profile
+			}
+			changes = append(changes, events.Diff(existing, project)...)
+		} else {
+			changes = append(changes, events.Created(project)...)
+		}
+		staged[key] = project.Clone()
+	}
+
+	m.projects = staged
+	m.outbox.write(changes)
+	return results, nil
+}
+
+// Batch mirrors the PostgreSQL implementation's outcomes, discarding every
+// write when an atomic batch has a failure
+func (m *ProjectRepository) Batch(ctx context.Context, ops []models.BatchOperation, atomic bool) ([]repository.BatchOutcome, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	staged := maps.Clone(m.projects)
+	now := time.Now()
+	outcomes := make([]repository.BatchOutcome, len(ops))
+	failed := false
+	var changes []models.Event
+
+	for i, op := range ops {
+		key := models.NewProjectKey(op.Instance, op.ProjectID)
+		if op.Project != nil {
+			key = op.Project.Key()
+			op.Project.Instance = key.Instance
+		}
+		existing, exists := staged[key]
+
+		switch {
+		case op.Op == models.BatchDelete && exists:
+			delete(staged, key)
+			outcomes[i] = repository.BatchDeleted
+			changes = append(changes, events.Deleted(existing)...)
+			continue
+		case op.Op == models.BatchCreate && exists:
+			outcomes[i] = repository.BatchConflict
+		case (op.Op == models.BatchUpdate || op.Op == models.BatchDelete) && !exists:
+			outcomes[i] = repository.BatchNotFound
+		case exists:
+			outcomes[i] = repository.BatchUpdated
+		default:
+			outcomes[i] = repository.BatchCreated
+		}
+		if outcomes[i].Failed() {
+			failed = true
+			continue
+		}
+
+		// Batches write checks only, so written projects keep any stored
+		// metadata, profile, code owners and scan time
+		op.Project.CreatedAt, op.Project.UpdatedAt = now, now
+		op.Project.ProjectMetadata = models.ProjectMetadata{}
+		op.Project.Profile = ""
+		op.Project.CodeOwners = nil
+		op.Project.LastScannedAt = nil
+		if exists {
+			op.Project.CreatedAt = existing.CreatedAt
+			op.Project.ProjectMetadata = existing.ProjectMetadata
+			op.Project.Profile = existing.Profile
+			op.Project.CodeOwners = existing.CodeOwners
+			op.Project.LastScannedAt = existing.LastScannedAt
+			changes = append(changes, events.Diff(existing, op.Project)...)
+		} else {
+			changes = append(changes, events.Created(op.Project)...)
+		}
+		staged[key] = op.Project.Clone()
+	}
+
+	if atomic && failed {
+		return outcomes, false, nil
+	}
+	m.projects = staged
+	m.outbox.write(changes)
+	return outcomes, true, nil
+}
+
+// matching returns the sorted keys of projects passing the filter
+func (m *ProjectRepository) matching(filter repository.ProjectFilter) []models.ProjectKey {
+	var keys []models.ProjectKey
+	for key, project := range m.projects {
+		if filter.Instance != "" && key.Instance != filter.Instance {
+			continue
+		}
+		if filter.Ready != nil && project.Ready() != *filter.Ready {
+			continue
+		}
+		if filter.Failing != "" && !slices.ContainsFunc(project.FailingChecks(), func(c models.CheckResult) bool {
+			return c.Name == filter.Failing
+		}) {
+			continue
+		}
+		if filter.CodeOwner != "" && !slices.Contains(project.CodeOwners, filter.CodeOwner) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b models.ProjectKey) int {
+		return cmp.Or(cmp.Compare(a.Instance, b.Instance), cmp.Compare(a.ProjectID, b.ProjectID))
+	})
+	return keys
+}
+
+// SetLastScannedAt backdates a project's last full scan so tests can
+// exercise freshness rules
+func (m *ProjectRepository) SetLastScannedAt(key models.ProjectKey, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if project, ok := m.projects[key]; ok {
+		project.LastScannedAt = &at
+	}
+}
@@ -0,0 +1,121 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.SyncRepository = (*SyncRepository)(nil)
+
+// SyncRepository is an in-memory repository.SyncRepository
+type SyncRepository struct {
+	mu      sync.Mutex
+	nextID  int64
+	runs    []*models.GroupSyncRun
+	members map[[2]string][]string // By instance and group
+}
+
+func NewSyncRepository() *SyncRepository {
+	return &SyncRepository{members: make(map[[2]string][]string)}
+}
+
+func (m *SyncRepository) StartRun(ctx context.Context, run *models.GroupSyncRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	run.ID = m.nextID
+	run.Status = models.SyncRunning
+	run.StartedAt = time.Now()
+	m.runs = append(m.runs, cloneRun(run))
+	return nil
+}
+
+func (m *SyncRepository) FinishRun(ctx context.Context, run *models.GroupSyncRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, stored := range m.runs {
+		if stored.ID == run.ID {
+			now := time.Now()
+			run.FinishedAt = &now
+			m.runs[i] = cloneRun(run)
+			return nil
+		}
+	}
+	return fmt.Errorf("sync run not found")
+}
+
+func (m *SyncRepository) LatestRun(ctx context.Context, instance string) (*models.GroupSyncRun, error) {
+	runs, _ := m.ListRuns(ctx, instance, 1, 0)
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("sync run not found")
+	}
+	return runs[0], nil
+}
+
+func (m *SyncRepository) ListRuns(ctx context.Context, instance string, limit, offset int) ([]*models.GroupSyncRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var runs []*models.GroupSyncRun
+	for _, run := range slices.Backward(m.matching(instance)) {
+		if len(runs) == limit {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		runs = append(runs, cloneRun(run))
+	}
+	return runs, nil
+}
+
+func (m *SyncRepository) CountRuns(ctx context.Context, instance string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.matching(instance)), nil
+}
+
+// matching returns the runs on instance, or every run, oldest first
+func (m *SyncRepository) matching(instance string) []*models.GroupSyncRun {
+	var runs []*models.GroupSyncRun
+	for _, run := range m.runs {
+		if instance == "" || run.Instance == instance {
+			runs = append(runs, run)
+		}
+	}
+	return runs
+}
+
+func (m *SyncRepository) Members(ctx context.Context, instance, group string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.members[[2]string{instance, group}]), nil
+}
+
+func (m *SyncRepository) SetMembers(ctx context.Context, instance, group string, projectIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := slices.Clone(projectIDs)
+	slices.Sort(ids)
+	m.members[[2]string{instance, group}] = slices.Compact(ids)
+	return nil
+}
+
+func cloneRun(run *models.GroupSyncRun) *models.GroupSyncRun {
+	c := *run
+	c.Groups = slices.Clone(run.Groups)
+	c.Created = slices.Clone(run.Created)
+	c.MarkedAbsent = slices.Clone(run.MarkedAbsent)
+	c.Errors = slices.Clone(run.Errors)
+	return &c
+}
@@ -0,0 +1,198 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+var _ repository.WebhookRepository = (*WebhookRepository)(nil)
+
+// WebhookRepository is an in-memory repository.WebhookRepository
+type WebhookRepository struct {
+	mu             sync.Mutex
+	nextID         int64
+	nextDeliveryID int64
+	subscriptions  []*models.WebhookSubscription
+	deliveries     []*models.WebhookDelivery
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{}
+}
+
+func (m *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	subscription.ID = m.nextID
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	stored := *subscription
+	stored.Events = slices.Clone(subscription.Events)
+	m.subscriptions = append(m.subscriptions, &stored)
+	return nil
+}
+
+func (m *WebhookRepository) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, subscription := range m.subscriptions {
+		if subscription.ID == id {
+			found := *subscription
+			found.Events = slices.Clone(subscription.Events)
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook not found")
+}
+
+func (m *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subscriptions := make([]*models.WebhookSubscription, 0, len(m.subscriptions))
+	for _, subscription := range m.subscriptions {
+		found := *subscription
+		found.Events = slices.Clone(subscription.Events)
+		subscriptions = append(subscriptions, &found)
+	}
+	return subscriptions, nil
+}
+
+func (m *WebhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, existing := range m.subscriptions {
+		if existing.ID == subscription.ID {
+			subscription.CreatedAt = existing.CreatedAt
+			subscription.UpdatedAt = time.Now()
+			stored := *subscription
+			stored.Events = slices.Clone(subscription.Events)
+			m.subscriptions[i] = &stored
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook not found")
+}
+
+func (m *WebhookRepository) DeleteSubscription(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, subscription := range m.subscriptions {
+		if subscription.ID == id {
+			m.subscriptions = slices.Delete(m.subscriptions, i, i+1)
+			m.deliveries = slices.DeleteFunc(m.deliveries, func(d *models.WebhookDelivery) bool {
+				return d.SubscriptionID == id
+			})
+			return nil
+		}
+	}
+	return fmt.Errorf("webhook not found")
+}
+
+func (m *WebhookRepository) Enqueue(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		m.nextDeliveryID++
+		delivery.ID = m.nextDeliveryID
+		delivery.Status = models.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = &now
+		delivery.CreatedAt = now
+		stored := *delivery
+		m.deliveries = append(m.deliveries, &stored)
+	}
+	return nil
+}
+
+func (m *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	leased := now.Add(lease)
+	var claimed []*models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		if delivery.Status != models.DeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.NextAttemptAt = &leased
+		found := *delivery
+		claimed = append(claimed, &found)
+	}
+	return claimed, nil
+}
+
+func (m *WebhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, existing := range m.deliveries {
+		if existing.ID == delivery.ID {
+			stored := *delivery
+			m.deliveries[i] = &stored
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *WebhookRepository) GetDelivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, delivery := range m.deliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.ID == id {
+			found := *delivery
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("delivery not found")
+}
+
+func (m *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit, offset int) ([]*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	matching := m.matchingDeliveries(subscriptionID, status)
+	var deliveries []*models.WebhookDelivery
+	for i := offset; i < len(matching) && i < offset+limit; i++ {
+		found := *matching[i]
+		deliveries = append(deliveries, &found)
+	}
+	return deliveries, nil
+}
+
+func (m *WebhookRepository) CountDeliveries(ctx context.Context, subscriptionID int64, status string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.matchingDeliveries(subscriptionID, status)), nil
+}
+
+// matchingDeliveries returns a subscription's deliveries, newest first
+func (m *WebhookRepository) matchingDeliveries(subscriptionID int64, status string) []*models.WebhookDelivery {
+	var deliveries []*models.WebhookDelivery
+	for _, delivery := range slices.Backward(m.deliveries) {
+		if delivery.SubscriptionID == subscriptionID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/models"
+)
+
+type SyncRepository interface {
+	// StartRun records a run as running, setting its ID and start time
+	StartRun(ctx context.Context, run *models.GroupSyncRun) error
+
+	// FinishRun stores a run's outcome and sets its finish time
+	FinishRun(ctx context.Context, run *models.GroupSyncRun) error
+
+	LatestRun(ctx context.Context, instance string) (*models.GroupSyncRun, error)
+
+	// ListRuns returns runs newest first, across every instance when
+	// instance is empty
+	ListRuns(ctx context.Context, instance string, limit, offset int) ([]*models.GroupSyncRun, error)
+
+	CountRuns(ctx context.Context, instance string) (int, error)
+
+	// Members returns the projects seen in an instance's group by the last
+	// run that listed it
+	Members(ctx context.Context, instance, group string) ([]string, error)
+
+	// SetMembers replaces the projects seen in an instance's group
+	SetMembers(ctx context.Context, instance, group string, projectIDs []string) error
+}
+
+type syncRepo struct {
+	db *database.DB
+}
+
+func NewSyncRepository(db *database.DB) SyncRepository {
+	return &syncRepo{db: db}
+}
+
+const syncRunColumns = `id, instance, groups, trigger, status, projects_seen, created, marked_absent, errors, started_at, finished_at`
+
+func (r *syncRepo) StartRun(ctx context.Context, run *models.GroupSyncRun) error {
+	query := `
+		INSERT INTO group_sync_runs (instance, groups, trigger, status, started_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+
+	run.Status = models.SyncRunning
+	run.StartedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, query, run.Instance, pq.Array(run.Groups), run.Trigger, run.Status, run.StartedAt).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to start sync run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *syncRepo) FinishRun(ctx context.Context, run *models.GroupSyncRun) error {
+	query := `
+		UPDATE group_sync_runs SET
+			status = $2,
+			projects_seen = $3,
+			created = $4,
+			marked_absent = $5,
+			errors = $6,
+			finished_at = $7
+		WHERE id = $1
+	`
+
+	errs, err := json.Marshal(run.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to encode sync errors: %w", err)
+	}
+	now := time.Now()
+	run.FinishedAt = &now
+
+	result, err := r.db.ExecContext(ctx, query,
+		run.ID,
+		run.Status,
+		run.ProjectsSeen,
+		pq.Array(run.Created),
+		pq.Array(run.MarkedAbsent),
+		errs,
+		run.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish sync run: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("sync run not found")
+	}
+
+	return nil
+}
+
+func (r *syncRepo) LatestRun(ctx context.Context, instance string) (*models.GroupSyncRun, error) {
+	runs, err := r.ListRuns(ctx, instance, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("sync run not found")
+	}
+	return runs[0], nil
+}
+
+func (r *syncRepo) ListRuns(ctx context.Context, instance string, limit, offset int) ([]*models.GroupSyncRun, error) {
+	query := `
+		SELECT ` + syncRunColumns + ` FROM group_sync_runs
+		WHERE $1 = '' OR instance = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, instance, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*models.GroupSyncRun
+	for rows.Next() {
+		var run models.GroupSyncRun
+		var errs []byte
+		var finishedAt sql.NullTime
+		err := rows.Scan(
+			&run.ID,
+			&run.Instance,
+			pq.Array(&run.Groups),
+			&run.Trigger,
+			&run.Status,
+			&run.ProjectsSeen,
+			pq.Array(&run.Created),
+			pq.Array(&run.MarkedAbsent),
+			&errs,
+			&run.StartedAt,
+			&finishedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sync run: %w", err)
+		}
+		if err := json.Unmarshal(errs, &run.Errors); err != nil {
+			return nil, fmt.Errorf("failed to decode sync errors: %w", err)
+		}
+		if finishedAt.Valid {
+			run.FinishedAt = &finishedAt.Time
+		}
+		runs = append(runs, &run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (r *syncRepo) CountRuns(ctx context.Context, instance string) (int, error) {
+	query := `SELECT COUNT(*) FROM group_sync_runs WHERE $1 = '' OR instance = $1`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, instance).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count sync runs: %w", err)
+	}
+	return count, nil
+}
+
+func (r *syncRepo) Members(ctx context.Context, instance, group string) ([]string, error) {
+	query := `SELECT project_id FROM group_sync_members WHERE instance = $1 AND group_path = $2 ORDER BY project_id`
+
+	rows, err := r.db.QueryContext(ctx, query, instance, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list group members: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan group member: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *syncRepo) SetMembers(ctx context.Context, instance, group string, projectIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM group_sync_members WHERE instance = $1 AND group_path = $2`, instance, group); err != nil {
+		return fmt.Errorf("failed to clear group members: %w", err)
+	}
+
+	query := `
+		INSERT INTO group_sync_members (instance, group_path, project_id)
+		SELECT $1, $2, id FROM unnest($3::text[]) AS id
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, instance, group, pq.Array(projectIDs)); err != nil {
+		return fmt.Errorf("failed to store group members: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit group members: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/user/go-backend/internal/database"
+	"github.com/user/go-backend/internal/models"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+
+	GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error)
+
+	ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error)
+
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	// Enqueue stores new pending deliveries, due immediately
+	Enqueue(ctx context.Context, deliveries []*models.WebhookDelivery) error
+
+	// ClaimDue returns up to limit pending deliveries whose next attempt is
+	// due, postponing them by lease so other instances skip them while they
+	// are being sent
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+
+	// RecordAttempt stores the outcome of sending a delivery
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+
+	GetDelivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error)
+
+	// ListDeliveries returns a subscription's deliveries, newest first. An
+	// empty status matches every delivery.
+	ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit, offset int) ([]*models.WebhookDelivery, error)
+
+	CountDeliveries(ctx context.Context, subscriptionID int64, status string) (int, error)
+}
+
+// encryptedSecretPrefix marks a secret stored encrypted. The base64 of the
+// nonce and the sealed secret follows it.
+const encryptedSecretPrefix = "aes256gcm:"
+
+type webhookRepo struct {
+	db      *database.DB
+	secrets cipher.AEAD // Nil stores secrets unencrypted
+}
+
+// NewWebhookRepository creates the repository. With a 32-byte secretKey,
+// subscription secrets are encrypted with AES-256-GCM before they are
+// stored; without one they are stored as sent. Secrets stored unencrypted
+// are read either way, and encrypted when their subscription is next
+// updated.
+func NewWebhookRepository(db *database.DB, secretKey []byte) (WebhookRepository, error) {
+	r := &webhookRepo{db: db}
+	if len(secretKey) > 0 {
+		block, err := aes.NewCipher(secretKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook secret key: %w", err)
+		}
+		if r.secrets, err = cipher.NewGCM(block); err != nil {
+			return nil, fmt.Errorf("invalid webhook secret key: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// sealSecret returns the secret as it is stored
+func (r *webhookRepo) sealSecret(secret string) (string, error) {
+	if r.secrets == nil {
+		return secret, nil
+	}
+	nonce := make([]byte, r.secrets.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	sealed := r.secrets.Seal(nonce, nonce, []byte(secret), nil)
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// openSecret returns a stored secret as it was sent
+func (r *webhookRepo) openSecret(stored string) (string, error) {
+	encoded, ok := strings.CutPrefix(stored, encryptedSecretPrefix)
+	if !ok {
+		return stored, nil
+	}
+	if r.secrets == nil {
+		return "", fmt.Errorf("webhook secret is encrypted but no key is configured")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < r.secrets.NonceSize() {
+		return "", fmt.Errorf("failed to decrypt webhook secret: malformed")
+	}
+	nonce, sealed := sealed[:r.secrets.NonceSize()], sealed[r.secrets.NonceSize():]
+	secret, err := r.secrets.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (
+			url, secret, events, active, description, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		RETURNING id
+	`
+
+	secret, err := r.sealSecret(subscription.Secret)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+
+	err = r.db.QueryRowContext(ctx, query,
+		subscription.URL,
+		secret,
+		pq.Array(subscription.Events),
+		subscription.Active,
+		subscription.Description,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	).Scan(&subscription.ID)
+
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, id int64) (*models.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, active, description, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription := &models.WebhookSubscription{}
+	var secret string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&secret,
+		(*pq.StringArray)(&subscription.Events),
+		&subscription.Active,
+		&subscription.Description,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if subscription.Secret, err = r.openSecret(secret); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context) ([]*models.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, secret, events, active, description, created_at, updated_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*models.WebhookSubscription
+	for rows.Next() {
+		subscription := &models.WebhookSubscription{}
+		var secret string
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.URL,
+			&secret,
+			(*pq.StringArray)(&subscription.Events),
+			&subscription.Active,
+			&subscription.Description,
+			&subscription.CreatedAt,
+			&subscription.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		if subscription.Secret, err = r.openSecret(secret); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepo) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions SET
+			url = $2,
+			secret = $3,
+			events = $4,
+			active = $5,
+			description = $6,
+			updated_at = $7
+		WHERE id = $1
+		RETURNING created_at
+	`
+
+	secret, err := r.sealSecret(subscription.Secret)
+	if err != nil {
+		return err
+	}
+
+	subscription.UpdatedAt = time.Now()
+
+	err = r.db.QueryRowContext(ctx, query,
+		subscription.ID,
+		subscription.URL,
+		secret,
+		pq.Array(subscription.Events),
+		subscription.Active,
+		subscription.Description,
+		subscription.UpdatedAt,
+	).Scan(&subscription.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("webhook not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	return nil
+}
+
+func (r *webhookRepo) Enqueue(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			subscription_id, event_id, event_type, instance, project_id, payload,
+			status, next_attempt_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $8
+		)
+		RETURNING id
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare enqueue: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		delivery.Status = models.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = &now
+		delivery.CreatedAt = now
+
+		err := stmt.QueryRowContext(ctx,
+			delivery.SubscriptionID,
+			delivery.EventID,
+			delivery.EventType,
+			delivery.Instance,
+			delivery.ProjectID,
+			[]byte(delivery.Payload),
+			delivery.Status,
+			now,
+		).Scan(&delivery.ID)
+		if err != nil {
+			return fmt.Errorf("failed to enqueue delivery: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit deliveries: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	// SKIP LOCKED lets several instances claim disjoint batches at once
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+
+	now := time.Now()
+	rows, err := r.db.QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+	return scanDeliveries(rows)
+}
+
+func (r *webhookRepo) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = $2,
+			attempts = $3,
+			response_status = $4,
+			error = $5,
+			next_attempt_at = $6,
+			last_attempt_at = $7
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.Error,
+		delivery.NextAttemptAt,
+		delivery.LastAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, subscriptionID, id int64) (*models.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1 AND id = $2`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+	deliveries, err := scanDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, fmt.Errorf("delivery not found")
+	}
+
+	return deliveries[0], nil
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, status string, limit, offset int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	return scanDeliveries(rows)
+}
+
+func (r *webhookRepo) CountDeliveries(ctx context.Context, subscriptionID int64, status string) (int, error) {
+	query := `SELECT COUNT(*) FROM webhook_deliveries WHERE subscription_id = $1 AND ($2 = '' OR status = $2)`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, subscriptionID, status).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count deliveries: %w", err)
+	}
+
+	return count, nil
+}
+
+const deliveryColumns = `
+	id, subscription_id, event_id, event_type, instance, project_id, payload, status,
+	attempts, response_status, error, next_attempt_at, last_attempt_at, created_at
+`
+
+// scanDeliveries reads and closes rows selected with deliveryColumns
+func scanDeliveries(rows *sql.Rows) ([]*models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		var (
+			responseStatus sql.NullInt64
+			nextAttemptAt  sql.NullTime
+			lastAttemptAt  sql.NullTime
+		)
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Instance,
+			&delivery.ProjectID,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&responseStatus,
+			&delivery.Error,
+			&nextAttemptAt,
+			&lastAttemptAt,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		if responseStatus.Valid {
+			status := int(responseStatus.Int64)
+			delivery.ResponseStatus = &status
+		}
+		if nextAttemptAt.Valid {
+			delivery.NextAttemptAt = &nextAttemptAt.Time
+		}
+		if lastAttemptAt.Valid {
+			delivery.LastAttemptAt = &lastAttemptAt.Time
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return deliveries, nil
+}
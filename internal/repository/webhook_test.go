@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWebhookRepository_Secrets(t *testing.T) {
+	repo, err := NewWebhookRepository(nil, bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatalf("NewWebhookRepository() error = %v", err)
+	}
+	r := repo.(*webhookRepo)
+
+	stored, err := r.sealSecret("s3cret")
+	if err != nil {
+		t.Fatalf("sealSecret() error = %v", err)
+	}
+	if !strings.HasPrefix(stored, encryptedSecretPrefix) || strings.Contains(stored, "s3cret") {
+		t.Errorf("sealSecret() = %q, want it encrypted", stored)
+	}
+	if again, _ := r.sealSecret("s3cret"); again == stored {
+		t.Error("sealSecret() reused a nonce")
+	}
+	if secret, err := r.openSecret(stored); err != nil || secret != "s3cret" {
+		t.Errorf("openSecret() = %q, %v", secret, err)
+	}
+
+	// Secrets stored before a key was configured are read as they are
+	if secret, err := r.openSecret("legacy"); err != nil || secret != "legacy" {
+		t.Errorf("openSecret(legacy) = %q, %v", secret, err)
+	}
+
+	other, _ := NewWebhookRepository(nil, bytes.Repeat([]byte{8}, 32))
+	if _, err := other.(*webhookRepo).openSecret(stored); err == nil {
+		t.Error("openSecret() with another key succeeded")
+	}
+	plain, _ := NewWebhookRepository(nil, nil)
+	if _, err := plain.(*webhookRepo).openSecret(stored); err == nil {
+		t.Error("openSecret() without a key succeeded")
+	}
+
+	if _, err := NewWebhookRepository(nil, []byte("short")); err == nil {
+		t.Error("NewWebhookRepository() accepted a 5-byte key")
+	}
+}
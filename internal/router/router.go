@@ -15,28 +15,109 @@ import (
 	_ "github.com/user/go-backend/docs" // This is required for Swagger
 )
 
-func New(projectHandler *handlers.ProjectHandler, logger *slog.Logger) http.Handler {
+// Handlers holds the HTTP handlers to mount. Nil handlers other than
+// Project leave their routes unregistered, which keeps tests small.
+type Handlers struct {
+	Idempotency *handlers.IdempotencyMiddleware // Optional; replays retried writes
+
+	Project     *handlers.ProjectHandler
+	Gate        *handlers.GateHandler
+	Scan        *handlers.ScanHandler
+	Member      *handlers.MemberHandler
+	Exemption   *handlers.ExemptionHandler
+	Report      *handlers.ReportHandler
+	Import      *handlers.ImportHandler
+	Export      *handlers.ExportHandler
+	Batch       *handlers.BatchHandler
+	Webhook     *handlers.WebhookHandler
+	Events      *handlers.EventsHandler
+	GitLabHook  *handlers.GitLabHookHandler
+	Sync        *handlers.SyncHandler
+	Instance    *handlers.InstanceHandler
+	Check       *handlers.CheckHandler
+	Application *handlers.ApplicationHandler
+}
+
+func New(h Handlers, logger *slog.Logger) http.Handler {
 	r := chi.NewRouter()
 
 	// Middleware stack
-	r.Use(middleware.RequestID)                 // Add request ID for tracing
-	r.Use(middleware.RealIP)                    // Get real IP from headers
-	r.Use(middleware.Recoverer)                 // Recover from panics
-	r.Use(LoggerMiddleware(logger))             // Custom logging middleware
-	r.Use(middleware.Timeout(60 * time.Second)) // Request timeout
-
-	r.Get("/swagger/*", httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"), // Use relative URL instead of absolute
-	))
-
-	r.Get("/api/v1/health", projectHandler.HealthCheck)
-
-	r.Route("/api/v1/gitlab/projects", func(r chi.Router) {
-		r.Get("/", projectHandler.ListProjects)         // GET /api/v1/gitlab/projects
-		r.Post("/", projectHandler.CreateProject)       // POST /api/v1/gitlab/projects
-		r.Get("/{id}", projectHandler.GetProject)       // GET /api/v1/gitlab/projects/{id}
-		r.Put("/{id}", projectHandler.UpdateProject)    // PUT /api/v1/gitlab/projects/{id}
-		r.Delete("/{id}", projectHandler.DeleteProject) // DELETE /api/v1/gitlab/projects/{id}
+	r.Use(middleware.RequestID)     // Add request ID for tracing
+	r.Use(RequestIDHeader)          // Echo request ID back to the caller
+	r.Use(middleware.RealIP)        // Get real IP from headers
+	r.Use(middleware.Recoverer)     // Recover from panics
+	r.Use(LoggerMiddleware(logger)) // Custom logging middleware
+
+	if h.Events != nil {
+		// Event streams stay open indefinitely, so they sit outside the
+		// request timeout
+		r.Get("/api/v1/events", h.Events.StreamEvents) // GET /api/v1/events
+	}
+	if h.Export != nil {
+		// Exports stream every matching project, which can take longer than
+		// the request timeout allows, so they have a limit of their own
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(30 * time.Minute))                                           // Export timeout
+			r.Get("/api/v1/gitlab/projects/export", h.Export.ExportProjects)                      // GET /api/v1/gitlab/projects/export
+			r.Get("/api/v1/gitlab/instances/{instance}/projects/export", h.Export.ExportProjects) // GET /api/v1/gitlab/instances/{instance}/projects/export
+		})
+	}
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second)) // Request timeout
+		if h.Idempotency != nil {
+			r.Use(h.Idempotency.Wrap) // Replay writes retried with an Idempotency-Key
+		}
+
+		r.Get("/swagger/*", httpSwagger.Handler(
+			httpSwagger.URL("/swagger/doc.json"), // Use relative URL instead of absolute
+		))
+
+		r.Get("/api/v1/health", h.Project.HealthCheck)
+
+		if h.Batch != nil {
+			r.Post("/api/v1/gitlab/projects:batch", h.Batch.Batch)                      // POST /api/v1/gitlab/projects:batch
+			r.Post("/api/v1/gitlab/instances/{instance}/projects:batch", h.Batch.Batch) // POST /api/v1/gitlab/instances/{instance}/projects:batch
+		}
+		if h.GitLabHook != nil {
+			r.Post("/api/v1/gitlab/hooks", h.GitLabHook.ReceiveHook)                      // POST /api/v1/gitlab/hooks
+			r.Post("/api/v1/gitlab/instances/{instance}/hooks", h.GitLabHook.ReceiveHook) // POST /api/v1/gitlab/instances/{instance}/hooks
+		}
+		if h.Check != nil {
+			r.Get("/api/v1/checks", h.Check.ListChecks)     // GET /api/v1/checks
+			r.Get("/api/v1/profiles", h.Check.ListProfiles) // GET /api/v1/profiles
+		}
+		if h.Application != nil {
+			r.Get("/api/v1/applications", h.Application.ListApplications)                       // GET /api/v1/applications
+			r.Put("/api/v1/applications", h.Application.ReplaceApplications)                    // PUT /api/v1/applications
+			r.Get("/api/v1/reports/duplicate-moab-ids", h.Application.DuplicateMoabIDs)         // GET /api/v1/reports/duplicate-moab-ids
+			r.Get("/api/v1/reports/unregistered-app-names", h.Application.UnregisteredAppNames) // GET /api/v1/reports/unregistered-app-names
+		}
+		if h.Instance != nil {
+			r.Get("/api/v1/gitlab/instances", h.Instance.ListInstances) // GET /api/v1/gitlab/instances
+		}
+		if h.Sync != nil {
+			r.Get("/api/v1/gitlab/sync", h.Sync.ListSyncRuns)         // GET /api/v1/gitlab/sync
+			r.Post("/api/v1/gitlab/sync", h.Sync.TriggerSync)         // POST /api/v1/gitlab/sync
+			r.Get("/api/v1/gitlab/sync/latest", h.Sync.LatestSyncRun) // GET /api/v1/gitlab/sync/latest
+		}
+
+		// Projects on the default instance, listed across every instance,
+		// and the same routes scoped to one instance
+		r.Route("/api/v1/gitlab/projects", projectRoutes(h))
+		r.Route("/api/v1/gitlab/instances/{instance}/projects", projectRoutes(h))
+
+		if h.Webhook != nil {
+			r.Route("/api/v1/webhooks", func(r chi.Router) {
+				r.Get("/", h.Webhook.ListWebhooks)                                     // GET /api/v1/webhooks
+				r.Post("/", h.Webhook.CreateWebhook)                                   // POST /api/v1/webhooks
+				r.Get("/{id}", h.Webhook.GetWebhook)                                   // GET /api/v1/webhooks/{id}
+				r.Put("/{id}", h.Webhook.UpdateWebhook)                                // PUT /api/v1/webhooks/{id}
+				r.Delete("/{id}", h.Webhook.DeleteWebhook)                             // DELETE /api/v1/webhooks/{id}
+				r.Get("/{id}/deliveries", h.Webhook.ListDeliveries)                    // GET /api/v1/webhooks/{id}/deliveries
+				r.Post("/{id}/deliveries/{deliveryID}/redeliver", h.Webhook.Redeliver) // POST /api/v1/webhooks/{id}/deliveries/{deliveryID}/redeliver
+			})
+		}
 	})
 
 	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
@@ -49,6 +130,43 @@ func New(projectHandler *handlers.ProjectHandler, logger *slog.Logger) http.Hand
 	return r
 }
 
+// projectRoutes mounts the project routes relative to a projects prefix
+func projectRoutes(h Handlers) func(chi.Router) {
+	getProject := h.Project.GetProject
+	if h.Report != nil {
+		getProject = h.Report.Negotiate(getProject) // JUnit/SARIF via Accept header
+	}
+
+	return func(r chi.Router) {
+		r.Get("/", h.Project.ListProjects)         // GET .../projects
+		r.Post("/", h.Project.CreateProject)       // POST .../projects
+		r.Get("/{id}", getProject)                 // GET .../projects/{id}
+		r.Put("/{id}", h.Project.UpdateProject)    // PUT .../projects/{id}
+		r.Delete("/{id}", h.Project.DeleteProject) // DELETE .../projects/{id}
+
+		if h.Import != nil {
+			r.Post("/import", h.Import.ImportProjects) // POST .../projects/import
+		}
+		if h.Gate != nil {
+			r.Get("/{id}/gate", h.Gate.Gate) // GET .../projects/{id}/gate
+		}
+		if h.Report != nil {
+			r.Get("/{id}/report", h.Report.ProjectReport) // GET .../projects/{id}/report
+		}
+		if h.Scan != nil {
+			r.Post("/{id}/scan", h.Scan.ScanProject) // POST .../projects/{id}/scan
+		}
+		if h.Member != nil {
+			r.Get("/{id}/members", h.Member.ListMembers) // GET .../projects/{id}/members
+		}
+		if h.Exemption != nil {
+			r.Get("/{id}/exemptions", h.Exemption.ListExemptions)                   // GET .../projects/{id}/exemptions
+			r.Post("/{id}/exemptions", h.Exemption.CreateExemption)                 // POST .../projects/{id}/exemptions
+			r.Delete("/{id}/exemptions/{exemptionID}", h.Exemption.DeleteExemption) // DELETE .../projects/{id}/exemptions/{exemptionID}
+		}
+	}
+}
+
 func LoggerMiddleware(logger *slog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -73,6 +191,17 @@ func LoggerMiddleware(logger *slog.Logger) func(next http.Handler) http.Handler
 	}
 }
 
+// RequestIDHeader echoes the request ID assigned by middleware.RequestID back
+// in the response so clients can correlate their logs with ours
+func RequestIDHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reqID := middleware.GetReqID(r.Context()); reqID != "" {
+			w.Header().Set(middleware.RequestIDHeader, reqID)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -82,3 +211,9 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
 	rw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush streamed exports
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
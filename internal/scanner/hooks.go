@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+)
+
+// HookAction is what a GitLab hook event calls for
+type HookAction string
+
+const (
+	HookIgnore HookAction = "ignore"
+	HookRescan HookAction = "rescan"
+	HookCreate HookAction = "create" // Register the project, then scan it
+	HookRemove HookAction = "remove" // Mark the project absent from GitLab
+)
+
+// HookPlan is the response to one hook event
+type HookPlan struct {
+	Action HookAction
+	Checks []string // Checks to rescan; empty means every check
+	Reason string   // Why the event needs no action
+
+	// Files lists the files a push changed, when which checks they affect
+	// depends on the project's CI/CD configuration. ForProject settles it.
+	Files []string
+}
+
+// PlanHook maps a GitLab project, group or system hook event to the checks
+// it may have changed. Pushes only affect the checks reading files on the
+// default branch, finished pipelines those reading the latest pipeline, and
+// changes to the project's members those reading its members; project
+// settings changes, renames and transfers may affect any check.
+// Events the checks do not depend on are ignored. Push plans are only
+// final once narrowed to the stored project with ForProject.
+func PlanHook(event *gitlab.HookEvent) HookPlan {
+	switch event.Kind() {
+	case "push":
+		return planPush(event)
+	case "repository_update":
+		return planRepositoryUpdate(event)
+	case "pipeline":
+		return planPipeline(event)
+	case "user_add_to_team", "user_remove_from_team", "user_update_for_team":
+		return HookPlan{Action: HookRescan, Checks: checks.MemberChecks()}
+	case "project_create":
+		return HookPlan{Action: HookCreate}
+	case "project_destroy":
+		return HookPlan{Action: HookRemove}
+	case "project_update", "project_rename", "project_transfer":
+		return HookPlan{Action: HookRescan}
+	default:
+		return HookPlan{Action: HookIgnore, Reason: "unsupported event " + event.Kind()}
+	}
+}
+
+func planPush(event *gitlab.HookEvent) HookPlan {
+	branch, ok := strings.CutPrefix(event.Ref, "refs/heads/")
+	if !ok {
+		return HookPlan{Action: HookIgnore, Reason: "not a branch push"}
+	}
+	if event.Project != nil && event.Project.DefaultBranch != "" && branch != event.Project.DefaultBranch {
+		return HookPlan{Action: HookIgnore, Reason: "push to a branch other than the default"}
+	}
+
+	// GitLab lists at most 20 commits, and none when a branch is created or
+	// deleted, so the files touched are only known when every commit is
+	// listed
+	if len(event.Commits) == 0 || event.TotalCommitsCount > len(event.Commits) {
+		return HookPlan{Action: HookRescan, Checks: checks.FileChecks()}
+	}
+
+	var files []string
+	for _, commit := range event.Commits {
+		files = append(files, slices.Concat(commit.Added, commit.Modified, commit.Removed)...)
+	}
+	if len(files) == 0 {
+		return HookPlan{Action: HookIgnore, Reason: "no files changed"}
+	}
+	slices.Sort(files)
+	return HookPlan{Action: HookRescan, Files: slices.Compact(files)}
+}
+
+// ForProject settles a push plan for the stored project: files read by the
+// CI/CD checks count if they are the project's configuration file or files
+// its last evaluation found included. Other plans are returned unchanged.
+func (p HookPlan) ForProject(project *models.Project) HookPlan {
+	if p.Files == nil {
+		return p
+	}
+
+	ciFiles := checks.CIConfigFiles(project.Evaluations[checks.ProjectPresent].Evidence, project.Evaluations[checks.AppNameSet].Evidence)
+	ciChecks := checks.ReadingFile(checks.CIConfigPath)
+	var ids []string
+	for _, path := range p.Files {
+		for _, id := range checks.ReadingFile(path) {
+			if !slices.Contains(ciChecks, id) {
+				ids = append(ids, id)
+			}
+		}
+		if slices.Contains(ciFiles, path) {
+			ids = append(ids, ciChecks...)
+		}
+	}
+	if len(ids) == 0 {
+		return HookPlan{Action: HookIgnore, Reason: "no files read by the checks changed"}
+	}
+	slices.Sort(ids)
+	return HookPlan{Action: HookRescan, Checks: slices.Compact(ids)}
+}
+
+// planRepositoryUpdate handles the system hook sent for pushes, which names
+// the refs updated but not the files
+func planRepositoryUpdate(event *gitlab.HookEvent) HookPlan {
+	defaultRef := ""
+	if event.Project != nil && event.Project.DefaultBranch != "" {
+		defaultRef = "refs/heads/" + event.Project.DefaultBranch
+	}
+
+	for _, change := range event.Changes {
+		if defaultRef == "" || change.Ref == defaultRef {
+			return HookPlan{Action: HookRescan, Checks: checks.FileChecks()}
+		}
+	}
+	return HookPlan{Action: HookIgnore, Reason: "default branch not updated"}
+}
+
+// planPipeline handles pipeline events, which GitLab sends as a pipeline's
+// status changes. Only finished pipelines on the default branch count.
+func planPipeline(event *gitlab.HookEvent) HookPlan {
+	p := event.ObjectAttributes
+	switch {
+	case p == nil:
+		return HookPlan{Action: HookIgnore, Reason: "pipeline event without a pipeline"}
+	case p.Tag || (event.Project != nil && event.Project.DefaultBranch != "" && p.Ref != event.Project.DefaultBranch):
+		return HookPlan{Action: HookIgnore, Reason: "pipeline for a ref other than the default branch"}
+	}
+	switch p.Status {
+	case "success", "failed", "canceled", "skipped":
+		return HookPlan{Action: HookRescan, Checks: checks.PipelineChecks()}
+	default:
+		return HookPlan{Action: HookIgnore, Reason: "pipeline has not finished"}
+	}
+}
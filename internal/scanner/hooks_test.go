@@ -0,0 +1,200 @@
+package scanner
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+)
+
+func TestPlanHook(t *testing.T) {
+	project := &gitlab.HookProject{ID: 42, PathWithNamespace: "platform/payments", DefaultBranch: "main"}
+	files := []string{"app_name_set", "moab_id_set", "codeowners_exists", "codeowners_valid", "codeowners_catch_all",
+		"sast_enabled", "secret_detection_enabled", "dependency_scanning_enabled", "container_scanning_enabled"}
+	ciChecks := []string{"app_name_set", "container_scanning_enabled", "dependency_scanning_enabled", "moab_id_set", "sast_enabled", "secret_detection_enabled"}
+
+	// The last scan found .gitlab-ci.yml including ci/variables.yml
+	stored := &models.Project{ProjectID: "42", Evaluations: map[string]models.Evaluation{
+		"project_present": {Status: checks.StatusPass, Evidence: json.RawMessage(`{"id": 42, "ci_config_path": ""}`)},
+		"app_name_set": {Status: checks.StatusPass, Evidence: json.RawMessage(`{"files": [
+			{"kind": "file", "path": ".gitlab-ci.yml"},
+			{"kind": "file", "path": "ci/variables.yml"},
+			{"kind": "file", "project": "platform/ci", "path": "ci/shared.yml"},
+			{"kind": "template", "path": "Jobs/SAST.gitlab-ci.yml"}
+		]}`)},
+	}}
+	// Never scanned, with the configuration at a custom path
+	custom := &models.Project{ProjectID: "42", Evaluations: map[string]models.Evaluation{
+		"project_present": {Status: checks.StatusPass, Evidence: json.RawMessage(`{"id": 42, "ci_config_path": "build/pipeline.yml"}`)},
+	}}
+
+	tests := []struct {
+		name       string
+		event      gitlab.HookEvent
+		stored     *models.Project // Defaults to stored
+		wantAction HookAction
+		wantChecks []string
+	}{
+		{
+			name: "push touching the CI file",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 2, Commits: []gitlab.HookCommit{
+				{Modified: []string{"main.go"}},
+				{Modified: []string{".gitlab-ci.yml"}},
+			}},
+			wantAction: HookRescan,
+			wantChecks: ciChecks,
+		},
+		{
+			name: "push touching only an included CI file",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 1, Commits: []gitlab.HookCommit{
+				{Modified: []string{"ci/variables.yml"}},
+			}},
+			wantAction: HookRescan,
+			wantChecks: ciChecks,
+		},
+		{
+			name: "push touching a YAML file the CI configuration does not read",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 1, Commits: []gitlab.HookCommit{
+				{Modified: []string{"deploy/app.yaml", "ci/shared.yml"}},
+			}},
+			wantAction: HookIgnore,
+		},
+		{
+			name: "push touching a custom CI configuration path",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 1, Commits: []gitlab.HookCommit{
+				{Added: []string{"build/pipeline.yml"}},
+			}},
+			stored:     custom,
+			wantAction: HookRescan,
+			wantChecks: ciChecks,
+		},
+		{
+			name: "push touching .gitlab-ci.yml beside a custom CI configuration path",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 1, Commits: []gitlab.HookCommit{
+				{Modified: []string{".gitlab-ci.yml"}},
+			}},
+			stored:     custom,
+			wantAction: HookIgnore,
+		},
+		{
+			name: "push removing CODEOWNERS",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 1, Commits: []gitlab.HookCommit{
+				{Removed: []string{".gitlab/CODEOWNERS"}},
+			}},
+			wantAction: HookRescan,
+			wantChecks: []string{"codeowners_catch_all", "codeowners_exists", "codeowners_valid"},
+		},
+		{
+			name: "push touching neither",
+			event: gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 1, Commits: []gitlab.HookCommit{
+				{Added: []string{"README.md"}},
+			}},
+			wantAction: HookIgnore,
+		},
+		{
+			name: "push with truncated commit list",
+			event: gitlab.HookEvent{EventName: "push", Ref: "refs/heads/main", Project: project, TotalCommitsCount: 30, Commits: []gitlab.HookCommit{
+				{Added: []string{"README.md"}},
+			}},
+			wantAction: HookRescan,
+			wantChecks: files,
+		},
+		{
+			name:       "push to a feature branch",
+			event:      gitlab.HookEvent{ObjectKind: "push", Ref: "refs/heads/feature", Project: project},
+			wantAction: HookIgnore,
+		},
+		{
+			name:       "tag push",
+			event:      gitlab.HookEvent{ObjectKind: "tag_push", Ref: "refs/tags/v1", Project: project},
+			wantAction: HookIgnore,
+		},
+		{
+			name:       "repository update of the default branch",
+			event:      gitlab.HookEvent{EventName: "repository_update", Project: project, Changes: []gitlab.HookChange{{Ref: "refs/heads/main"}}},
+			wantAction: HookRescan,
+			wantChecks: files,
+		},
+		{
+			name:       "repository update of another branch",
+			event:      gitlab.HookEvent{EventName: "repository_update", Project: project, Changes: []gitlab.HookChange{{Ref: "refs/heads/feature"}}},
+			wantAction: HookIgnore,
+		},
+		{
+			name:       "project update",
+			event:      gitlab.HookEvent{EventName: "project_update", ProjectID: 42},
+			wantAction: HookRescan,
+		},
+		{
+			name:       "project create",
+			event:      gitlab.HookEvent{EventName: "project_create", ProjectID: 42},
+			wantAction: HookCreate,
+		},
+		{
+			name:       "project destroy",
+			event:      gitlab.HookEvent{EventName: "project_destroy", ProjectID: 42},
+			wantAction: HookRemove,
+		},
+		{
+			name:       "finished pipeline on the default branch",
+			event:      gitlab.HookEvent{ObjectKind: "pipeline", Project: project, ObjectAttributes: &gitlab.HookPipeline{Ref: "main", Status: "success"}},
+			wantAction: HookRescan,
+			wantChecks: []string{"sast_enabled", "secret_detection_enabled", "dependency_scanning_enabled", "container_scanning_enabled"},
+		},
+		{
+			name:       "running pipeline",
+			event:      gitlab.HookEvent{ObjectKind: "pipeline", Project: project, ObjectAttributes: &gitlab.HookPipeline{Ref: "main", Status: "running"}},
+			wantAction: HookIgnore,
+		},
+		{
+			name:       "pipeline on a feature branch",
+			event:      gitlab.HookEvent{ObjectKind: "pipeline", Project: project, ObjectAttributes: &gitlab.HookPipeline{Ref: "feature", Status: "failed"}},
+			wantAction: HookIgnore,
+		},
+		{
+			name:       "member added to the project",
+			event:      gitlab.HookEvent{EventName: "user_add_to_team", ProjectID: 42, ProjectPathWithNamespace: "platform/payments"},
+			wantAction: HookRescan,
+			wantChecks: []string{"codeowners_valid", "owners_limited", "maintainers_limited", "external_writers_absent", "blocked_members_absent"},
+		},
+		{
+			name:       "merge request",
+			event:      gitlab.HookEvent{ObjectKind: "merge_request"},
+			wantAction: HookIgnore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlanHook(&tt.event).ForProject(cmp.Or(tt.stored, stored))
+			if got.Action != tt.wantAction || !slices.Equal(got.Checks, tt.wantChecks) {
+				t.Errorf("PlanHook() = %s %v, want %s %v", got.Action, got.Checks, tt.wantAction, tt.wantChecks)
+			}
+			if got.Action == HookIgnore && got.Reason == "" {
+				t.Error("PlanHook() ignored the event without a reason")
+			}
+		})
+	}
+}
+
+func TestHookEvent_ProjectKeys(t *testing.T) {
+	event := gitlab.HookEvent{
+		EventName:            "project_rename",
+		ProjectID:            42,
+		PathWithNamespace:    "platform/payments-api",
+		OldPathWithNamespace: "platform/payments",
+	}
+	want := []string{"42", "platform/payments-api", "platform/payments"}
+	if got := event.ProjectKeys(); !slices.Equal(got, want) {
+		t.Errorf("ProjectKeys() = %v, want %v", got, want)
+	}
+
+	team := gitlab.HookEvent{EventName: "user_add_to_team", ProjectID: 42, ProjectPathWithNamespace: "platform/payments"}
+	if got := team.ProjectKeys(); !slices.Equal(got, []string{"42", "platform/payments"}) {
+		t.Errorf("ProjectKeys() for a membership event = %v", got)
+	}
+}
@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+// Queue runs rescans in the background for callers that cannot wait, such as
+// GitLab hooks. Requests for a project that arrive before its rescan starts
+// are merged into one, and those arriving while it runs into one more
+// rescan after it, so a project is never rescanned twice at once.
+type Queue struct {
+	scanner *Scanner
+	workers int
+	timeout time.Duration
+	logger  *slog.Logger
+
+	mu       sync.Mutex
+	pending  map[models.ProjectKey][]string // Checks to rescan per project; nil means every check
+	running  map[models.ProjectKey]bool     // Projects being rescanned
+	deferred map[models.ProjectKey][]string // Checks requested during a project's rescan, like pending
+	ready    chan models.ProjectKey
+}
+
+// NewQueue returns a queue running up to workers rescans at once and
+// holding up to capacity projects waiting for one
+func NewQueue(scanner *Scanner, workers, capacity int, logger *slog.Logger) *Queue {
+	return &Queue{
+		scanner:  scanner,
+		workers:  max(workers, 1),
+		timeout:  2 * time.Minute,
+		logger:   logger,
+		pending:  make(map[models.ProjectKey][]string),
+		running:  make(map[models.ProjectKey]bool),
+		deferred: make(map[models.ProjectKey][]string),
+		ready:    make(chan models.ProjectKey, max(capacity, 1)),
+	}
+}
+
+// Enqueue requests a rescan of the named checks, or every check when none
+// are named. It reports false when the queue is full.
+func (q *Queue) Enqueue(key models.ProjectKey, checks []string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.running[key] {
+		if queued, ok := q.deferred[key]; ok {
+			q.deferred[key] = mergeChecks(queued, checks)
+		} else {
+			q.deferred[key] = normalizeChecks(checks)
+		}
+		return true
+	}
+	if queued, ok := q.pending[key]; ok {
+		q.pending[key] = mergeChecks(queued, checks)
+		return true
+	}
+	return q.push(key, checks)
+}
+
+// push queues a project that is neither queued nor running. The caller
+// holds q.mu.
+func (q *Queue) push(key models.ProjectKey, checks []string) bool {
+	select {
+	case q.ready <- key:
+	default:
+		return false
+	}
+	q.pending[key] = normalizeChecks(checks)
+	return true
+}
+
+// mergeChecks combines two requests' checks, where nil means every check
+func mergeChecks(queued, checks []string) []string {
+	if queued == nil || len(checks) == 0 {
+		return nil
+	}
+	merged := slices.Concat(queued, checks)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+func normalizeChecks(checks []string) []string {
+	if len(checks) == 0 {
+		return nil
+	}
+	return slices.Clone(checks)
+}
+
+// Run processes queued rescans until ctx is cancelled
+func (q *Queue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (q *Queue) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case key := <-q.ready:
+			q.mu.Lock()
+			checks := q.pending[key]
+			delete(q.pending, key)
+			q.running[key] = true
+			q.mu.Unlock()
+
+			scanCtx, cancel := context.WithTimeout(ctx, q.timeout)
+			if _, err := q.scanner.ScanChecks(scanCtx, key, checks); err != nil && ctx.Err() == nil {
+				q.logger.Error("queued rescan failed", "instance", key.Instance, "project_id", key.ProjectID, "checks", checks, "error", err)
+			}
+			cancel()
+
+			q.finish(key)
+		}
+	}
+}
+
+// finish ends a project's rescan, queueing the checks requested while it
+// ran
+func (q *Queue) finish(key models.ProjectKey) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.running, key)
+	checks, ok := q.deferred[key]
+	if !ok {
+		return
+	}
+	delete(q.deferred, key)
+	if !q.push(key, checks) {
+		q.logger.Warn("rescan queue full, dropped rescan requested during a scan", "instance", key.Instance, "project_id", key.ProjectID, "checks", checks)
+	}
+}
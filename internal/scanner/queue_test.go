@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+func TestQueue(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id":42,"default_branch":"main"}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": "variables:\n  APP_NAME: payments\n",
+		"/api/v4/projects/42/repository/files/CODEOWNERS/raw":     "* @team",
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queue := NewQueue(s, 1, 1, logger)
+
+	// Requests for a waiting project merge; others need room in the queue
+	if !queue.Enqueue(models.NewProjectKey("", "42"), []string{"app_name_set"}) || !queue.Enqueue(models.NewProjectKey("", "42"), []string{"codeowners_exists"}) {
+		t.Fatal("Enqueue() for a waiting project = false, want merged")
+	}
+	if queue.Enqueue(models.NewProjectKey("", "43"), nil) {
+		t.Error("Enqueue() on a full queue = true")
+	}
+
+	go queue.Run(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		project, err := repo.GetByID(ctx, models.NewProjectKey("", "42"))
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		if project.Result("app_name_set") && project.Result("codeowners_exists") {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("queued rescans did not update both checks")
+}
+
+func TestQueue_RequestsDuringScan(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	queue := NewQueue(nil, 1, 1, logger)
+	key := models.NewProjectKey("", "42")
+
+	// A running project is not queued again until its rescan finishes
+	queue.running[key] = true
+	if !queue.Enqueue(key, []string{"codeowners_exists"}) || !queue.Enqueue(key, []string{"app_name_set"}) {
+		t.Fatal("Enqueue() during a rescan = false, want deferred")
+	}
+	if len(queue.ready) != 0 {
+		t.Fatalf("queued %d projects during a rescan, want 0", len(queue.ready))
+	}
+
+	queue.finish(key)
+	if len(queue.ready) != 1 || queue.running[key] {
+		t.Fatalf("after finish: queued = %d, running = %v, want 1 queued", len(queue.ready), queue.running[key])
+	}
+	if got, want := queue.pending[key], []string{"app_name_set", "codeowners_exists"}; !slices.Equal(got, want) {
+		t.Errorf("pending checks = %v, want %v", got, want)
+	}
+
+	// Asking for every check during a rescan widens the deferred one
+	queue.running[key] = true
+	delete(queue.pending, key)
+	<-queue.ready
+	queue.Enqueue(key, []string{"app_name_set"})
+	queue.Enqueue(key, nil)
+	queue.finish(key)
+	if checks, ok := queue.pending[key]; !ok || checks != nil {
+		t.Errorf("pending checks = %v (queued %v), want every check", checks, ok)
+	}
+}
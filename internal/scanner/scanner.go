@@ -0,0 +1,170 @@
+// Package scanner evaluates readiness checks against the GitLab API.
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+type Scanner struct {
+	instances gitlab.Instances
+	profiles  checks.Profiles
+	repo      repository.ProjectRepository
+	logger    *slog.Logger
+}
+
+// New returns a scanner evaluating each project against the client for its
+// instance, held to the thresholds of its readiness profile. Projects of
+// instances missing from instances fail to scan with
+// gitlab.ErrUnknownInstance, and of unknown profiles with
+// checks.ErrUnknownProfile.
+func New(instances gitlab.Instances, profiles checks.Profiles, repo repository.ProjectRepository, logger *slog.Logger) *Scanner {
+	return &Scanner{
+		instances: instances,
+		profiles:  profiles,
+		repo:      repo,
+		logger:    logger,
+	}
+}
+
+// Scan re-evaluates every check for a stored project and saves the result
+func (s *Scanner) Scan(ctx context.Context, key models.ProjectKey) (*models.Project, error) {
+	return s.ScanChecks(ctx, key, nil)
+}
+
+// ScanChecks re-evaluates the named checks for a stored project, keeping the
+// stored results of the others, and saves the result. No names means every
+// check.
+func (s *Scanner) ScanChecks(ctx context.Context, key models.ProjectKey, names []string) (*models.Project, error) {
+	project, err := s.repo.GetByID(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	scanned, err := s.evaluate(ctx, project, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan project %s on %s: %w", key.ProjectID, key.Instance, err)
+	}
+
+	// Only the checks evaluated are saved, so writes made while GitLab was
+	// being read keep the other results
+	if err := s.repo.SaveScan(ctx, project, scanned); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("project scanned", "instance", key.Instance, "project_id", key.ProjectID, "checks", names, "ready", project.Ready())
+	return project, nil
+}
+
+// Evaluate fills in the project's check results and metadata from its
+// GitLab instance without saving them. A project GitLab does not know about
+// fails every check.
+func (s *Scanner) Evaluate(ctx context.Context, project *models.Project) error {
+	_, err := s.evaluate(ctx, project, nil)
+	return err
+}
+
+// ReviewMembers reads the members of a stored project from its GitLab
+// instance as the access review checks do, without saving anything. A
+// project GitLab does not know about returns gitlab.ErrNotFound.
+func (s *Scanner) ReviewMembers(ctx context.Context, key models.ProjectKey) (*checks.MemberReview, error) {
+	project, err := s.repo.GetByID(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.instances.Get(key.Instance)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.profiles.Get(project.Profile)
+	if err != nil {
+		return nil, err
+	}
+	gl, err := client.GetProject(ctx, project.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return checks.NewTarget(client, project.ProjectID, gl, profile).MemberReview(ctx)
+}
+
+// evaluate runs the named checks, or all of them. Project presence is always
+// refreshed. Errors GitLab answers a check with are recorded against the
+// check; failing to reach GitLab fails the scan. It returns the IDs of the
+// checks evaluated, or none when it evaluated every check.
+func (s *Scanner) evaluate(ctx context.Context, project *models.Project, names []string) ([]string, error) {
+	client, err := s.instances.Get(project.Key().Instance)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.profiles.Get(project.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	gl, err := client.GetProject(ctx, project.ProjectID)
+	if errors.Is(err, gitlab.ErrNotFound) {
+		project.Results, project.Evaluations = nil, nil
+		for _, id := range checks.IDs() {
+			e := models.Evaluation{Status: checks.StatusUnknown, Reason: "Project not found in GitLab", EvaluatedAt: &now}
+			if id == checks.ProjectPresent {
+				e.Status = checks.StatusFail
+			}
+			project.SetEvaluation(id, e)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	project.ProjectMetadata = models.NewProjectMetadata(gl)
+
+	target := checks.NewTarget(client, project.ProjectID, gl, profile)
+	for _, c := range checks.All() {
+		id := c.Definition().ID
+		if len(names) > 0 && id != checks.ProjectPresent && !slices.Contains(names, id) {
+			continue
+		}
+
+		outcome, err := c.Evaluate(ctx, target)
+		var apiErr *gitlab.APIError
+		switch {
+		case errors.As(err, &apiErr):
+			outcome = checks.Outcome{Status: checks.StatusError, Reason: fmt.Sprintf("GitLab API returned %d for %s", apiErr.StatusCode, apiErr.Path)}
+		case err != nil:
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+		project.SetEvaluation(id, models.Evaluation{
+			Status:      outcome.Status,
+			Reason:      outcome.Reason,
+			Evidence:    outcome.Evidence,
+			Value:       outcome.Value,
+			Captured:    outcome.Captured,
+			EvaluatedAt: &now,
+		})
+	}
+
+	// Scans reading CODEOWNERS refresh the stored owners; others keep them
+	if co, ok := target.ScannedCodeowners(); ok {
+		project.CodeOwners = []string{}
+		if co != nil {
+			project.CodeOwners = append(project.CodeOwners, co.Owners()...)
+		}
+	} else {
+		project.CodeOwners = nil
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	return append(slices.Clone(names), checks.ProjectPresent), nil
+}
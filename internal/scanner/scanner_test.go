@@ -0,0 +1,740 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/gitlab"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+	"github.com/user/go-backend/internal/repository/repotest"
+)
+
+// fakeGitLab serves canned responses keyed by escaped request path; any
+// other path returns 404
+func fakeGitLab(t *testing.T, responses map[string]string) *gitlab.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			http.Error(w, `{"message":"404 Not Found"}`, http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	gl, err := gitlab.NewClient(gitlab.Config{BaseURL: srv.URL, Token: "test"})
+	if err != nil {
+		t.Fatalf("failed to create gitlab client: %v", err)
+	}
+	return gl
+}
+
+func newTestScanner(t *testing.T, responses map[string]string) (*Scanner, *repotest.ProjectRepository) {
+	t.Helper()
+
+	repo := repotest.NewProjectRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	profiles := checks.NewProfiles([]checks.Profile{{
+		Name: "tier-1",
+		Thresholds: map[string]checks.Threshold{
+			checks.MinApprovalsRequired: {Op: ">=", Value: 2},
+			checks.CodeownersExists:     {Op: ">=", Value: 2},
+		},
+		Formats: map[string]checks.Pattern{
+			checks.MoabIDSet: {Regexp: regexp.MustCompile(`^MOAB-\d{6}$`)},
+		},
+	}, {
+		Name:              "releases",
+		ProtectedBranches: []string{"release/*", "hotfix/*"},
+		ProtectedTags:     []string{"v*", "release-*"},
+	}})
+	return New(gitlab.Instances{models.DefaultInstance: fakeGitLab(t, responses)}, profiles, repo, logger), repo
+}
+
+func TestScanner_Scan(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{
+			"id": 42,
+			"name": "Payments",
+			"path_with_namespace": "platform/payments",
+			"default_branch": "main",
+			"web_url": "https://gitlab.example.com/platform/payments",
+			"visibility": "internal",
+			"archived": false,
+			"only_allow_merge_if_pipeline_succeeds": true,
+			"only_allow_merge_if_all_discussions_are_resolved": false
+		}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": `
+variables:
+  APP_NAME: payments
+build:
+  script: make
+`,
+		"/api/v4/projects/42/repository/files/.gitlab%2FCODEOWNERS/raw": "* @team",
+		"/api/v4/projects/42/protected_branches/main": `{
+			"name": "main",
+			"allow_force_push": false,
+			"code_owner_approval_required": true,
+			"push_access_levels": [{"access_level": 40}],
+			"merge_access_levels": [{"access_level": 30}]
+		}`,
+		"/api/v4/projects/42/protected_tags": `[{"name": "v*", "create_access_levels": [{"access_level": 40}]}]`,
+		"/api/v4/projects/42/push_rule":      `{"commit_message_regex": "^JIRA-\\d+", "reject_unsigned_commits": false}`,
+		"/api/v4/projects/42/approvals": `{
+			"approvals_before_merge": 0,
+			"reset_approvals_on_push": true,
+			"merge_requests_author_approval": false,
+			"merge_requests_disable_committers_approval": false
+		}`,
+		"/api/v4/projects/42/approval_rules": `[{"id":1,"name":"All","approvals_required":2}]`,
+		"/api/v4/projects/42/members/all": `[
+			{"id": 7, "username": "team", "state": "active", "access_level": 40},
+			{"id": 8, "username": "contractor", "state": "active", "access_level": 30},
+			{"id": 9, "username": "leaver", "state": "blocked", "access_level": 30}
+		]`,
+		"/api/v4/projects/42/members":     `[{"id": 8, "username": "contractor", "state": "active", "access_level": 30}]`,
+		"/api/v4/users/7":                 `{"id": 7, "username": "team", "external": false}`,
+		"/api/v4/users/8":                 `{"id": 8, "username": "contractor", "external": true}`,
+		"/api/v4/projects/42/deploy_keys": `[{"id": 1, "title": "deploy", "expires_at": "2027-01-01T00:00:00Z"}]`,
+		"/api/v4/projects/42/access_tokens": `[
+			{"id": 2, "name": "bot", "active": true, "expires_at": null},
+			{"id": 3, "name": "old", "active": false, "revoked": true, "expires_at": null}
+		]`,
+	})
+
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.Scan(ctx, models.NewProjectKey("", "42"))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if project.LastScannedAt == nil {
+		t.Error("Scan() did not set LastScannedAt")
+	}
+
+	want := map[string]bool{
+		"project_present":              true,
+		"app_name_set":                 true,
+		"moab_id_set":                  false,
+		"codeowners_exists":            true,
+		"codeowners_valid":             true,
+		"codeowners_catch_all":         true,
+		"branch_protection_enabled":    true,
+		"codeowner_approval_required":  true,
+		"push_merge_restricted":        false, // developers can merge
+		"force_push_disabled":          true,
+		"release_tags_protected":       true,
+		"push_rules_enabled":           true,
+		"signed_commits_required":      false,
+		"min_approvals_required":       true,
+		"author_approval_prevented":    true,
+		"committer_approval_prevented": false,
+		"approvals_removed_on_commit":  true,
+		"pipeline_success_required":    true,
+		"threads_resolved_required":    false,
+		"visibility_not_public":        true,
+		"credentials_expire":           false, // bot never expires
+		"owners_limited":               true,
+		"maintainers_limited":          true,
+		"external_writers_absent":      false,
+		"blocked_members_absent":       false,
+	}
+	for _, check := range project.Checks() {
+		if check.Passed != want[check.Name] {
+			t.Errorf("%s = %v, want %v", check.Name, check.Passed, want[check.Name])
+		}
+	}
+
+	if e := project.Evaluation(checks.CredentialsExpire); e.Reason != `No expiry is set on access token "bot"` {
+		t.Errorf("credentials_expire reason = %q", e.Reason)
+	}
+
+	stored, err := repo.GetByID(ctx, models.NewProjectKey("", "42"))
+	if err != nil {
+		t.Fatalf("failed to retrieve project: %v", err)
+	}
+	if !stored.Result("app_name_set") || !stored.UpdatedAt.After(stored.CreatedAt) {
+		t.Errorf("scan results were not stored: %+v", stored)
+	}
+	if stored.GitLabID != 42 || stored.PathWithNamespace != "platform/payments" || stored.DefaultBranch != "main" || stored.Visibility != "internal" {
+		t.Errorf("metadata was not stored: %+v", stored.ProjectMetadata)
+	}
+	if !slices.Equal(stored.CodeOwners, []string{"@team"}) {
+		t.Errorf("code owners = %v, want [@team]", stored.CodeOwners)
+	}
+	owned, err := repo.List(ctx, repository.ProjectFilter{CodeOwner: "@team"}, 10, 0)
+	if err != nil || len(owned) != 1 {
+		t.Errorf("List() by code owner = %d projects, %v", len(owned), err)
+	}
+}
+
+func TestScanner_ProjectMissingFromGitLab(t *testing.T) {
+	s, repo := newTestScanner(t, nil)
+	ctx := context.Background()
+
+	seed := &models.Project{ProjectID: "gone", Results: map[string]bool{"project_present": true, "app_name_set": true}}
+	seed.PathWithNamespace = "platform/gone"
+	if err := repo.Create(ctx, seed); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.Scan(ctx, models.NewProjectKey("", "gone"))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(project.FailingChecks()) != len(checks.IDs()) {
+		t.Errorf("expected every check to fail, got %+v", project.FailingChecks())
+	}
+	if project.PathWithNamespace != "platform/gone" {
+		t.Errorf("metadata was not kept: %+v", project.ProjectMetadata)
+	}
+	if got := project.Evaluation("app_name_set").Status; got != checks.StatusUnknown {
+		t.Errorf("app_name_set status = %q, want unknown", got)
+	}
+	if got := project.Evaluation("project_present").Status; got != checks.StatusFail {
+		t.Errorf("project_present status = %q, want fail", got)
+	}
+}
+
+func TestScanner_RecordsAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/42":
+			io.WriteString(w, `{"id": 42, "path_with_namespace": "platform/payments", "default_branch": "main"}`)
+		case "/api/v4/projects/42/approvals":
+			http.Error(w, `{"message":"403 Forbidden"}`, http.StatusForbidden)
+		default:
+			http.Error(w, `{"message":"404 Not Found"}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	gl, err := gitlab.NewClient(gitlab.Config{BaseURL: srv.URL, Token: "test"})
+	if err != nil {
+		t.Fatalf("failed to create gitlab client: %v", err)
+	}
+
+	repo := repotest.NewProjectRepository()
+	s := New(gitlab.Instances{models.DefaultInstance: gl}, nil, repo, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.Scan(ctx, models.NewProjectKey("", "42"))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	e := project.Evaluation("author_approval_prevented")
+	if e.Status != checks.StatusError || e.Reason != "GitLab API returned 403 for /projects/42/approvals" || e.EvaluatedAt == nil {
+		t.Errorf("author_approval_prevented = %+v, want a recorded 403", e)
+	}
+	if project.Result("author_approval_prevented") {
+		t.Error("a check GitLab refused must not pass")
+	}
+
+	// The checks after the refused one still ran
+	e = project.Evaluation("codeowners_exists")
+	if e.Status != checks.StatusFail || e.Reason == "" {
+		t.Errorf("codeowners_exists = %+v, want a failure with a reason", e)
+	}
+	if got := project.Evaluation("project_present").Status; got != checks.StatusPass {
+		t.Errorf("project_present status = %q, want pass", got)
+	}
+}
+
+func TestScanner_ProfileThresholds(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/repository/files/CODEOWNERS/raw": "[Backend]\n* @backend\n\n^[Docs]\n/docs/ @writers\n",
+		"/api/v4/projects/42/approvals":                       `{"approvals_before_merge": 0}`,
+		"/api/v4/projects/42/approval_rules": `[
+			{"id": 1, "name": "Backend", "approvals_required": 1},
+			{"id": 2, "name": "Optional", "approvals_required": 0}
+		]`,
+		"/api/v4/projects/42/members/all": `[{"id": 7, "username": "backend", "state": "active", "access_level": 40}]`,
+	})
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		profile      string
+		wantApproval bool
+	}{
+		{"", true},
+		{"tier-1", false},
+	} {
+		t.Run("profile "+tt.profile, func(t *testing.T) {
+			if err := repo.Create(ctx, &models.Project{ProjectID: "42", Profile: tt.profile}); err != nil {
+				t.Fatalf("failed to seed project: %v", err)
+			}
+			t.Cleanup(func() { repo.Delete(ctx, models.NewProjectKey("", "42")) })
+
+			project, err := s.Scan(ctx, models.NewProjectKey("", "42"))
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			approvals := project.Evaluation(checks.MinApprovalsRequired)
+			if project.Result(checks.MinApprovalsRequired) != tt.wantApproval || approvals.Value == nil || *approvals.Value != 1 {
+				t.Errorf("min_approvals_required = %v, %+v", project.Result(checks.MinApprovalsRequired), approvals)
+			}
+			var evidence struct {
+				ApproverRules []string `json:"approver_rules"`
+			}
+			if err := json.Unmarshal(approvals.Evidence, &evidence); err != nil || !slices.Equal(evidence.ApproverRules, []string{"Backend"}) {
+				t.Errorf("approver rules = %v (%v), want [Backend]", evidence.ApproverRules, err)
+			}
+
+			codeowners := project.Evaluation(checks.CodeownersExists)
+			if !project.Result(checks.CodeownersExists) || codeowners.Value == nil || *codeowners.Value != 2 {
+				t.Errorf("codeowners_exists = %+v, want 2 sections passing", codeowners)
+			}
+			if valid := project.Evaluation(checks.CodeownersValid); project.Result(checks.CodeownersValid) || !strings.Contains(valid.Reason, "@writers") {
+				t.Errorf("codeowners_valid = %+v, want @writers reported without access", valid)
+			}
+			if !project.Result(checks.CodeownersCatchAll) {
+				t.Errorf("codeowners_catch_all = %+v, want the Backend * rule passing", project.Evaluation(checks.CodeownersCatchAll))
+			}
+		})
+	}
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42", Profile: "tier-9"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+	if _, err := s.Scan(ctx, models.NewProjectKey("", "42")); !errors.Is(err, checks.ErrUnknownProfile) {
+		t.Errorf("Scan() with an unknown profile error = %v, want ErrUnknownProfile", err)
+	}
+}
+
+func TestScanner_CapturedValues(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": `
+variables:
+  APP_NAME: "payments" # registered in the CMDB
+  MOAB_ID: 7
+`,
+	})
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		profile  string
+		wantMoab bool
+	}{
+		{"", true},
+		{"tier-1", false}, // tier-1 requires MOAB-NNNNNN
+	} {
+		t.Run("profile "+tt.profile, func(t *testing.T) {
+			if err := repo.Create(ctx, &models.Project{ProjectID: "42", Profile: tt.profile}); err != nil {
+				t.Fatalf("failed to seed project: %v", err)
+			}
+			t.Cleanup(func() { repo.Delete(ctx, models.NewProjectKey("", "42")) })
+
+			if _, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), []string{checks.AppNameSet, checks.MoabIDSet}); err != nil {
+				t.Fatalf("ScanChecks() error = %v", err)
+			}
+			stored, err := repo.GetByID(ctx, models.NewProjectKey("", "42"))
+			if err != nil {
+				t.Fatalf("failed to retrieve project: %v", err)
+			}
+
+			if e := stored.Evaluation(checks.AppNameSet); e.Captured != "payments" || e.Status != checks.StatusPass {
+				t.Errorf("app_name_set = %+v, want payments captured", e)
+			}
+			// The value is captured even when it fails the format
+			moab := stored.Evaluation(checks.MoabIDSet)
+			if moab.Captured != "7" || stored.Result(checks.MoabIDSet) != tt.wantMoab {
+				t.Errorf("moab_id_set = %+v, want 7 captured and passing %v", moab, tt.wantMoab)
+			}
+		})
+	}
+}
+
+func TestScanner_CodeownersOrder(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/repository/files/docs%2FCODEOWNERS/raw":    "* @docs-team",
+		"/api/v4/projects/42/repository/files/.gitlab%2FCODEOWNERS/raw": "* @gitlab-team",
+	})
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), []string{checks.CodeownersExists})
+	if err != nil {
+		t.Fatalf("ScanChecks() error = %v", err)
+	}
+
+	// GitLab reads docs/ before .gitlab/
+	e := project.Evaluation(checks.CodeownersExists)
+	if want := "CODEOWNERS found at docs/CODEOWNERS with 0 sections"; e.Reason != want {
+		t.Errorf("codeowners_exists reason = %q, want %q", e.Reason, want)
+	}
+	var evidence struct {
+		Path    string   `json:"path"`
+		Ignored []string `json:"ignored"`
+	}
+	if err := json.Unmarshal(e.Evidence, &evidence); err != nil {
+		t.Fatalf("failed to decode evidence: %v", err)
+	}
+	if evidence.Path != "docs/CODEOWNERS" || !slices.Equal(evidence.Ignored, []string{".gitlab/CODEOWNERS"}) {
+		t.Errorf("codeowners_exists evidence = %+v, want docs/CODEOWNERS with .gitlab/CODEOWNERS ignored", evidence)
+	}
+	if !slices.Equal(project.CodeOwners, []string{"@docs-team"}) {
+		t.Errorf("CodeOwners = %v, want [@docs-team]", project.CodeOwners)
+	}
+}
+
+func TestScanner_CIIncludes(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main", "namespace": {"kind": "group", "full_path": "platform/payments"}}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": "include:\n  - local: ci/app.yml\n",
+		"/api/v4/projects/42/repository/files/ci%2Fapp.yml/raw":   "variables:\n  APP_NAME: payments\n",
+		"/api/v4/groups/platform/variables":                       `[{"key": "MOAB_ID", "value": "MOAB-000001", "environment_scope": "*"}]`,
+		"/api/v4/groups/platform%2Fpayments/variables":            `[{"key": "MOAB_ID", "value": "MOAB-123456", "environment_scope": "*"}]`,
+	})
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), []string{checks.AppNameSet, checks.MoabIDSet})
+	if err != nil {
+		t.Fatalf("ScanChecks() error = %v", err)
+	}
+
+	if e := project.Evaluation(checks.AppNameSet); e.Captured != "payments" || e.Reason != `APP_NAME is "payments" in ci/app.yml` {
+		t.Errorf("app_name_set = %+v", e)
+	}
+
+	// The closest group's variable wins
+	moab := project.Evaluation(checks.MoabIDSet)
+	if moab.Captured != "MOAB-123456" || !project.Result(checks.MoabIDSet) {
+		t.Errorf("moab_id_set = %+v", moab)
+	}
+	var evidence struct {
+		Variable struct {
+			Overrides []struct {
+				Group string `json:"group"`
+			} `json:"overrides"`
+		} `json:"variable"`
+		Unresolved []struct {
+			Source struct {
+				Kind string `json:"kind"`
+			} `json:"source"`
+		} `json:"unresolved"`
+	}
+	if err := json.Unmarshal(moab.Evidence, &evidence); err != nil {
+		t.Fatalf("failed to decode evidence: %v", err)
+	}
+	if len(evidence.Variable.Overrides) != 1 || evidence.Variable.Overrides[0].Group != "platform" {
+		t.Errorf("MOAB_ID overrides = %+v", evidence.Variable.Overrides)
+	}
+	// The fake has no project variables, so they are listed as unresolved
+	if len(evidence.Unresolved) != 1 || evidence.Unresolved[0].Source.Kind != "project_settings" {
+		t.Errorf("unresolved = %+v", evidence.Unresolved)
+	}
+}
+
+func TestScanner_ProtectedBranches(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/protected_branches/main": `{
+			"name": "main",
+			"push_access_levels": [{"access_level": 40}],
+			"merge_access_levels": [{"access_level": 40}]
+		}`,
+		"/api/v4/projects/42/protected_branches": `[
+			{"name": "main", "push_access_levels": [{"access_level": 40}], "merge_access_levels": [{"access_level": 40}]},
+			{"name": "release/*", "push_access_levels": [{"access_level": 0}], "merge_access_levels": [{"access_level": 40}]},
+			{"name": "*", "allow_force_push": true, "push_access_levels": [{"access_level": 30}], "merge_access_levels": [{"access_level": 30}]}
+		]`,
+	})
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42", Profile: "releases"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), []string{checks.BranchProtectionEnabled, checks.ForcePushDisabled, checks.PushMergeRestricted})
+	if err != nil {
+		t.Fatalf("ScanChecks() error = %v", err)
+	}
+
+	// The * rule protects every branch, but lets developers push to them,
+	// and force push is only disabled where a stricter rule also applies
+	want := map[string]map[string]string{
+		checks.BranchProtectionEnabled: {"main": "pass", "release/*": "pass", "hotfix/*": "pass"},
+		checks.ForcePushDisabled:       {"main": "pass", "release/*": "pass", "hotfix/*": "fail"},
+		checks.PushMergeRestricted:     {"main": "pass", "release/*": "fail", "hotfix/*": "fail"},
+	}
+	for id, branches := range want {
+		e := project.Evaluation(id)
+		var evidence struct {
+			Branches []struct {
+				Branch string   `json:"branch"`
+				Status string   `json:"status"`
+				Rules  []string `json:"rules"`
+			} `json:"branches"`
+		}
+		if err := json.Unmarshal(e.Evidence, &evidence); err != nil || len(evidence.Branches) != 3 {
+			t.Fatalf("%s evidence = %s (%v)", id, e.Evidence, err)
+		}
+		got := map[string]string{}
+		for _, b := range evidence.Branches {
+			got[b.Branch] = b.Status
+		}
+		if !maps.Equal(got, branches) {
+			t.Errorf("%s branches = %v, want %v", id, got, branches)
+		}
+		if rules := evidence.Branches[1].Rules; !slices.Equal(rules, []string{"release/*", "*"}) {
+			t.Errorf("%s rules for release/* = %v", id, rules)
+		}
+		if passed := !slices.Contains(slices.Collect(maps.Values(branches)), "fail"); project.Result(id) != passed {
+			t.Errorf("%s = %+v, want passed %v", id, e, passed)
+		}
+	}
+	if e := project.Evaluation(checks.ForcePushDisabled); e.Reason != "Force push is allowed on hotfix/*" {
+		t.Errorf("force_push_disabled reason = %q", e.Reason)
+	}
+}
+
+func TestScanner_ReleaseTags(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/protected_tags": `[
+			{"name": "v*", "create_access_levels": [{"access_level": 40}]},
+			{"name": "*-*", "create_access_levels": [{"access_level": 30}]}
+		]`,
+	})
+	ctx := context.Background()
+
+	// Without a profile listing tags, v* tags must be protected
+	for _, tt := range []struct {
+		profile string
+		passed  bool
+		reason  string
+	}{
+		{"", true, "Only Maintainers can create tags matching v*"},
+		{"releases", false, "Roles below Maintainer can create tags matching release-*"},
+	} {
+		t.Run("profile "+tt.profile, func(t *testing.T) {
+			if err := repo.Create(ctx, &models.Project{ProjectID: "42", Profile: tt.profile}); err != nil {
+				t.Fatalf("failed to seed project: %v", err)
+			}
+			t.Cleanup(func() { repo.Delete(ctx, models.NewProjectKey("", "42")) })
+
+			project, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), []string{checks.ReleaseTagsProtected})
+			if err != nil {
+				t.Fatalf("ScanChecks() error = %v", err)
+			}
+			if e := project.Evaluation(checks.ReleaseTagsProtected); project.Result(checks.ReleaseTagsProtected) != tt.passed || e.Reason != tt.reason {
+				t.Errorf("release_tags_protected = %+v, want passed %v with %q", e, tt.passed, tt.reason)
+			}
+		})
+	}
+}
+
+func TestScanner_ReviewMembers(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/members/all": `[
+			{"id": 1, "username": "lead", "state": "active", "access_level": 50},
+			{"id": 2, "username": "dev", "state": "active", "access_level": 40},
+			{"id": 3, "username": "project_42_bot", "state": "active", "access_level": 40},
+			{"id": 4, "username": "viewer", "state": "active", "access_level": 20},
+			{"id": 5, "username": "gone", "state": "deactivated", "access_level": 30}
+		]`,
+		"/api/v4/projects/42/members": `[{"id": 2, "username": "dev", "state": "active", "access_level": 30}]`,
+		"/api/v4/users/1":             `{"id": 1, "username": "lead", "external": false}`,
+		"/api/v4/users/2":             `{"id": 2, "username": "dev"}`,
+		"/api/v4/users/3":             `{"id": 3, "username": "project_42_bot", "bot": true, "external": false}`,
+	})
+	ctx := context.Background()
+	key := models.NewProjectKey("", "42")
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42", Profile: "tier-1"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	review, err := s.ReviewMembers(ctx, key)
+	if err != nil {
+		t.Fatalf("ReviewMembers() error = %v", err)
+	}
+	var usernames []string
+	for _, m := range review.Members {
+		usernames = append(usernames, m.Username)
+	}
+	if !slices.Equal(usernames, []string{"lead", "dev", "project_42_bot", "gone", "viewer"}) {
+		t.Errorf("members = %v, want highest role first", usernames)
+	}
+	// dev is a direct Developer, but a group makes them a Maintainer
+	if dev := review.Members[1]; !dev.Inherited || dev.Role != "Maintainer" || dev.External != nil {
+		t.Errorf("dev = %+v", dev)
+	}
+	if !slices.Equal(review.Owners, []string{"lead"}) || !slices.Equal(review.Maintainers, []string{"dev"}) {
+		t.Errorf("owners = %v, maintainers = %v; bots are not counted", review.Owners, review.Maintainers)
+	}
+	if !slices.Equal(review.Unverified, []string{"dev"}) || !slices.Equal(review.Blocked, []string{"gone (deactivated)"}) {
+		t.Errorf("unverified = %v, blocked = %v", review.Unverified, review.Blocked)
+	}
+
+	project, err := s.ScanChecks(ctx, key, checks.MemberChecks())
+	if err != nil {
+		t.Fatalf("ScanChecks() error = %v", err)
+	}
+	want := map[string]string{
+		checks.OwnersLimited:         "Project has 1 owner, <= 3 required",
+		checks.ExternalWritersAbsent: "No external user is known to have write access; 1 member could not be checked",
+		checks.BlockedMembersAbsent:  "Members with inactive accounts keep their access: gone (deactivated)",
+	}
+	for id, reason := range want {
+		if e := project.Evaluation(id); e.Reason != reason {
+			t.Errorf("%s reason = %q, want %q", id, e.Reason, reason)
+		}
+	}
+
+	if _, err := s.ReviewMembers(ctx, models.NewProjectKey("", "nope")); err == nil {
+		t.Error("ReviewMembers() found a project that is not stored")
+	}
+}
+
+func TestScanner_SecurityScanning(t *testing.T) {
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id": 42, "default_branch": "main"}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": `
+include:
+  - template: Jobs/SAST.gitlab-ci.yml
+  - template: Jobs/Dependency-Scanning.gitlab-ci.yml
+variables:
+  CONTAINER_SCANNING_DISABLED: "true"
+`,
+		"/api/v4/templates/gitlab_ci_ymls/Jobs%2FSAST":                `{"content": ".sast-analyzer:\n  artifacts:\n    reports:\n      sast: gl-sast-report.json\nsemgrep-sast:\n  extends: .sast-analyzer\n"}`,
+		"/api/v4/templates/gitlab_ci_ymls/Jobs%2FDependency-Scanning": `{"content": "gemnasium-dependency_scanning:\n  artifacts:\n    reports:\n      dependency_scanning: gl-dependency-scanning-report.json\n"}`,
+		"/api/v4/projects/42/pipelines":                               `[{"id": 7, "ref": "main", "status": "success"}]`,
+		"/api/v4/projects/42/pipelines/7/jobs": `[
+			{"id": 1, "name": "semgrep-sast", "status": "success", "artifacts": [{"file_type": "sast", "filename": "gl-sast-report.json"}]},
+			{"id": 2, "name": "build", "status": "success", "artifacts": [{"file_type": "trace"}]}
+		]`,
+	})
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), checks.PipelineChecks())
+	if err != nil {
+		t.Fatalf("ScanChecks() error = %v", err)
+	}
+
+	want := map[string]string{
+		checks.SASTEnabled:               "SAST reported in pipeline #7 by semgrep-sast",
+		checks.SecretDetectionEnabled:    "Secret detection has no job, and pipeline #7 uploaded no secret_detection report",
+		checks.DependencyScanningEnabled: "Dependency scanning is configured in gemnasium-dependency_scanning, but pipeline #7 uploaded no dependency_scanning report",
+		checks.ContainerScanningEnabled:  "Container scanning is turned off by CONTAINER_SCANNING_DISABLED in .gitlab-ci.yml",
+	}
+	for id, reason := range want {
+		if e := project.Evaluation(id); e.Reason != reason || project.Result(id) != (id == checks.SASTEnabled) {
+			t.Errorf("%s = %+v, want %q", id, e, reason)
+		}
+	}
+	if got := project.Checks()[len(project.Checks())-1]; got.Category != checks.CategorySecurityScanning {
+		t.Errorf("last check category = %q, want %s", got.Category, checks.CategorySecurityScanning)
+	}
+}
+
+func TestScanner_UnknownProject(t *testing.T) {
+	s, _ := newTestScanner(t, nil)
+
+	_, err := s.Scan(context.Background(), models.NewProjectKey("", "nope"))
+	if err == nil || err.Error() != "project not found" {
+		t.Errorf("Scan() error = %v, want project not found", err)
+	}
+}
+
+func TestScanner_ScanChecks(t *testing.T) {
+	// Only the CI file is served, so evaluating any other check would fail
+	// or clear it
+	s, repo := newTestScanner(t, map[string]string{
+		"/api/v4/projects/42": `{"id":42,"default_branch":"main"}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": "variables:\n  APP_NAME: payments\n  MOAB_ID: 7\n",
+	})
+
+	ctx := context.Background()
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42", Results: map[string]bool{"branch_protection_enabled": true, "force_push_disabled": true}}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	project, err := s.ScanChecks(ctx, models.NewProjectKey("", "42"), []string{"moab_id_set"})
+	if err != nil {
+		t.Fatalf("ScanChecks() error = %v", err)
+	}
+	if !project.Result("project_present") || !project.Result("moab_id_set") {
+		t.Errorf("ScanChecks() did not refresh the named check: %+v", project)
+	}
+	if project.Result("app_name_set") || !project.Result("branch_protection_enabled") || !project.Result("force_push_disabled") {
+		t.Errorf("ScanChecks() changed checks it was not asked for: %+v", project)
+	}
+	if project.LastScannedAt != nil {
+		t.Errorf("ScanChecks() of one check set LastScannedAt = %v, want unset", project.LastScannedAt)
+	}
+}
+
+func TestScanner_Instances(t *testing.T) {
+	onPrem := fakeGitLab(t, map[string]string{
+		"/api/v4/projects/42": `{"id":42,"default_branch":"main"}`,
+		"/api/v4/projects/42/repository/files/.gitlab-ci.yml/raw": "variables:\n  APP_NAME: payments\n",
+		"/api/v4/projects/42/push_rule":                           `null`,
+		"/api/v4/projects/42/approvals":                           `{}`,
+	})
+	repo := repotest.NewProjectRepository()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := New(gitlab.Instances{
+		models.DefaultInstance: fakeGitLab(t, nil),
+		"onprem":               onPrem,
+	}, nil, repo, logger)
+
+	ctx := context.Background()
+	for _, instance := range []string{models.DefaultInstance, "onprem", "unknown"} {
+		if err := repo.Create(ctx, &models.Project{Instance: instance, ProjectID: "42"}); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+
+	// The same project ID is a different project on each instance
+	project, err := s.Scan(ctx, models.NewProjectKey("onprem", "42"))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !project.Result("project_present") || !project.Result("app_name_set") {
+		t.Errorf("Scan() on onprem = %+v, want present with APP_NAME", project)
+	}
+
+	project, err = s.Scan(ctx, models.NewProjectKey("", "42"))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if project.Result("project_present") || project.Instance != models.DefaultInstance {
+		t.Errorf("Scan() on default = %+v, want absent", project)
+	}
+
+	if _, err := s.Scan(ctx, models.NewProjectKey("unknown", "42")); !errors.Is(err, gitlab.ErrUnknownInstance) {
+		t.Errorf("Scan() on an unregistered instance error = %v, want ErrUnknownInstance", err)
+	}
+}
@@ -0,0 +1,294 @@
+// Package webhooks delivers readiness events to subscribed HTTP endpoints.
+//
+// Events relayed from the outbox are stored as one delivery per matching
+// subscription and sent by a background worker, so a slow or failing
+// receiver never holds up the relay or other subscribers. Failed attempts
+// are retried with exponential backoff until MaxAttempts is reached.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+)
+
+// Headers sent with every delivery. The signature is "sha256=" followed by
+// the hex HMAC-SHA256 of the timestamp, a period and the body, keyed with
+// the subscription's secret.
+const (
+	EventHeader     = "X-Readiness-Event"
+	EventIDHeader   = "X-Readiness-Event-Id"
+	DeliveryHeader  = "X-Readiness-Delivery"
+	TimestampHeader = "X-Readiness-Timestamp"
+	SignatureHeader = "X-Readiness-Signature"
+)
+
+type Config struct {
+	HTTPClient   *http.Client  // Defaults to a client with a 10s timeout
+	MaxAttempts  int           // Attempts before a delivery fails, default 8
+	MinBackoff   time.Duration // Delay before the first retry, default 30s
+	MaxBackoff   time.Duration // Upper bound for a single delay, default 6h
+	PollInterval time.Duration // How often to look for due retries, default 5s
+	BatchSize    int           // Deliveries claimed at once, default 20
+}
+
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	cfg    Config
+	logger *slog.Logger
+	wake   chan struct{}
+}
+
+func New(repo repository.WebhookRepository, cfg Config, logger *slog.Logger) *Dispatcher {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 6 * time.Hour
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 20
+	}
+
+	return &Dispatcher{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Publish queues a delivery of each event for every subscription that wants
+// it. It is the webhook sink of the outbox relay, so an error leaves the
+// events in the outbox to be published again.
+func (d *Dispatcher) Publish(ctx context.Context, events []models.Event) error {
+	subscriptions, err := d.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+		}
+		for _, subscription := range subscriptions {
+			if !subscription.Matches(event.Type) {
+				continue
+			}
+			deliveries = append(deliveries, &models.WebhookDelivery{
+				SubscriptionID: subscription.ID,
+				EventID:        event.ID,
+				EventType:      event.Type,
+				Instance:       event.Instance,
+				ProjectID:      event.ProjectID,
+				Payload:        payload,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	if err := d.repo.Enqueue(ctx, deliveries); err != nil {
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+// Redeliver queues a new delivery of the same event, leaving the original
+// and its attempts in the log
+func (d *Dispatcher) Redeliver(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	redelivery := &models.WebhookDelivery{
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Instance:       delivery.Instance,
+		ProjectID:      delivery.ProjectID,
+		Payload:        delivery.Payload,
+	}
+	if err := d.repo.Enqueue(ctx, []*models.WebhookDelivery{redelivery}); err != nil {
+		return nil, err
+	}
+	d.notify()
+	return redelivery, nil
+}
+
+// notify wakes Run without blocking when it is already awake
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := d.deliverDue(ctx)
+			if err != nil {
+				d.logger.Error("failed to deliver webhooks", "error", err)
+				break
+			}
+			if sent < d.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// deliverDue sends one batch of due deliveries, returning how many it claimed
+func (d *Dispatcher) deliverDue(ctx context.Context) (int, error) {
+	// The lease outlasts every attempt in the batch, so a crashed instance's
+	// deliveries are picked up again once it expires
+	lease := time.Duration(d.cfg.BatchSize)*d.cfg.HTTPClient.Timeout + time.Minute
+	deliveries, err := d.repo.ClaimDue(ctx, d.cfg.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	subscriptions, err := d.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[int64]*models.WebhookSubscription, len(subscriptions))
+	for _, subscription := range subscriptions {
+		byID[subscription.ID] = subscription
+	}
+
+	for _, delivery := range deliveries {
+		subscription, ok := byID[delivery.SubscriptionID]
+		if !ok {
+			continue // Deleted since the delivery was claimed
+		}
+		d.attempt(ctx, subscription, delivery)
+		if err := d.repo.RecordAttempt(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery once and updates it with the outcome
+func (d *Dispatcher) attempt(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = nil
+	delivery.Error = ""
+
+	status, err := d.send(ctx, subscription, delivery, now)
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	if err == nil {
+		delivery.Status = models.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		d.logger.Debug("webhook delivered", "webhook_id", subscription.ID, "delivery_id", delivery.ID, "event", delivery.EventType)
+		return
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		d.logger.Warn("webhook delivery failed",
+			"webhook_id", subscription.ID,
+			"delivery_id", delivery.ID,
+			"attempts", delivery.Attempts,
+			"error", err,
+		)
+		return
+	}
+
+	next := now.Add(d.backoff(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// send posts the payload and returns the receiver's status code. Anything
+// but a 2xx response is an error.
+func (d *Dispatcher) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, at time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	timestamp := at.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "readiness-webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given number of failed attempts, with
+// jitter over the upper half so retries to a recovering receiver spread out
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.MinBackoff << (attempts - 1)
+	if delay <= 0 || delay > d.cfg.MaxBackoff {
+		delay = d.cfg.MaxBackoff
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// Sign returns the SignatureHeader value for a payload sent at timestamp
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository/repotest"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	w.WriteHeader(rc.status)
+}
+
+func setup(t *testing.T, status int, events []string) (*Dispatcher, *repotest.WebhookRepository, *receiver, *models.WebhookSubscription) {
+	t.Helper()
+
+	rc := &receiver{status: status}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+
+	repo := repotest.NewWebhookRepository()
+	subscription := &models.WebhookSubscription{URL: srv.URL, Secret: "s3cret", Events: events, Active: true}
+	if err := repo.CreateSubscription(context.Background(), subscription); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	d := New(repo, Config{MaxAttempts: 2, MinBackoff: time.Hour}, logger)
+	return d, repo, rc, subscription
+}
+
+func testEvent(eventType string) models.Event {
+	return models.Event{
+		ID:        "evt_1",
+		Type:      eventType,
+		ProjectID: "42",
+		Data:      models.EventData{Project: &models.Project{ProjectID: "42"}},
+	}
+}
+
+func TestDispatcher_DeliversSignedEvents(t *testing.T) {
+	ctx := context.Background()
+	d, repo, rc, subscription := setup(t, http.StatusOK, []string{models.EventProjectRegressed})
+
+	if err := d.Publish(ctx, []models.Event{testEvent(models.EventProjectUpdated), testEvent(models.EventProjectRegressed)}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if sent, err := d.deliverDue(ctx); err != nil || sent != 1 {
+		t.Fatalf("deliverDue() = %d, %v, want 1 filtered delivery", sent, err)
+	}
+
+	if len(rc.requests) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(rc.requests))
+	}
+	req, body := rc.requests[0], rc.bodies[0]
+	if got := req.Header.Get(EventHeader); got != models.EventProjectRegressed {
+		t.Errorf("%s = %q", EventHeader, got)
+	}
+	timestamp, err := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("%s = %q", TimestampHeader, req.Header.Get(TimestampHeader))
+	}
+	if got, want := req.Header.Get(SignatureHeader), Sign("s3cret", timestamp, body); got != want {
+		t.Errorf("%s = %q, want %q", SignatureHeader, got, want)
+	}
+
+	deliveries, _ := repo.ListDeliveries(ctx, subscription.ID, "", 10, 0)
+	if len(deliveries) != 1 || deliveries[0].Status != models.DeliverySucceeded || *deliveries[0].ResponseStatus != http.StatusOK {
+		t.Errorf("deliveries = %+v, want one succeeded", deliveries)
+	}
+}
+
+func TestDispatcher_RetriesThenFails(t *testing.T) {
+	ctx := context.Background()
+	d, repo, rc, subscription := setup(t, http.StatusInternalServerError, nil)
+
+	if err := d.Publish(ctx, []models.Event{testEvent(models.EventProjectCreated)}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if _, err := d.deliverDue(ctx); err != nil {
+		t.Fatalf("deliverDue() error = %v", err)
+	}
+
+	deliveries, _ := repo.ListDeliveries(ctx, subscription.ID, "", 10, 0)
+	delivery := deliveries[0]
+	if delivery.Status != models.DeliveryPending || delivery.Attempts != 1 || delivery.Error == "" {
+		t.Fatalf("after first attempt delivery = %+v, want pending with an error", delivery)
+	}
+	if wait := time.Until(*delivery.NextAttemptAt); wait < 29*time.Minute || wait > time.Hour {
+		t.Errorf("next attempt in %v, want 30m-1h", wait)
+	}
+
+	// The retry is not due yet
+	if sent, _ := d.deliverDue(ctx); sent != 0 {
+		t.Errorf("deliverDue() sent %d deliveries before the backoff elapsed", sent)
+	}
+
+	now := time.Now()
+	delivery.NextAttemptAt = &now
+	repo.RecordAttempt(ctx, delivery)
+	if _, err := d.deliverDue(ctx); err != nil {
+		t.Fatalf("deliverDue() error = %v", err)
+	}
+
+	deliveries, _ = repo.ListDeliveries(ctx, subscription.ID, models.DeliveryFailed, 10, 0)
+	if len(deliveries) != 1 || deliveries[0].Attempts != 2 || deliveries[0].NextAttemptAt != nil {
+		t.Fatalf("after last attempt deliveries = %+v, want one failed", deliveries)
+	}
+	if len(rc.requests) != 2 {
+		t.Errorf("receiver got %d requests, want 2", len(rc.requests))
+	}
+
+	redelivery, err := d.Redeliver(ctx, deliveries[0])
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if redelivery.ID == deliveries[0].ID || redelivery.Status != models.DeliveryPending || redelivery.EventID != "evt_1" {
+		t.Errorf("Redeliver() = %+v, want a new pending delivery of the same event", redelivery)
+	}
+}
+
+func TestDispatcher_SkipsInactiveSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	d, repo, _, subscription := setup(t, http.StatusOK, nil)
+
+	subscription.Active = false
+	repo.UpdateSubscription(ctx, subscription)
+
+	if err := d.Publish(ctx, []models.Event{testEvent(models.EventProjectCreated)}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if count, _ := repo.CountDeliveries(ctx, subscription.ID, ""); count != 0 {
+		t.Errorf("inactive subscription got %d deliveries", count)
+	}
+}
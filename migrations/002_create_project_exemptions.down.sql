@@ -0,0 +1,3 @@
+-- Drop the project_exemptions table and its associated index
+DROP INDEX IF EXISTS idx_project_exemptions_project_id;
+DROP TABLE IF EXISTS project_exemptions;
@@ -0,0 +1,18 @@
+-- Create the project_exemptions table
+-- An exemption waives a single readiness check for a project, optionally
+-- scoped to one deployment environment and until an expiry date
+CREATE TABLE IF NOT EXISTS project_exemptions (
+    id BIGSERIAL PRIMARY KEY,
+    project_id TEXT NOT NULL REFERENCES gitlab_projects(project_id) ON DELETE CASCADE,
+    check_name TEXT NOT NULL,
+
+    -- NULL applies the exemption to every environment
+    environment TEXT,
+
+    reason TEXT NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_project_exemptions_project_id ON project_exemptions(project_id);
@@ -0,0 +1,3 @@
+-- Drop the idempotency_keys table and its associated index
+DROP INDEX IF EXISTS idx_idempotency_keys_expires_at;
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,19 @@
+-- Create the idempotency_keys table
+-- Stores the response to each write request sent with an Idempotency-Key
+-- header so retries replay it instead of applying the write again
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key TEXT PRIMARY KEY,
+
+    -- SHA-256 of the method, path and body the key was first used with
+    request_hash TEXT NOT NULL,
+
+    -- NULL while the original request is still being processed
+    status_code INTEGER,
+    response_headers JSONB NOT NULL DEFAULT '{}',
+    response_body BYTEA,
+
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
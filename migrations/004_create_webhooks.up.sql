@@ -0,0 +1,42 @@
+-- Create the webhook tables
+-- Subscriptions receive readiness events over HTTP; every event sent to a
+-- subscription is recorded as a delivery so failures can be retried,
+-- inspected and redelivered
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+
+    -- Empty delivers every event type
+    events TEXT[] NOT NULL DEFAULT '{}',
+
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    project_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+
+    -- pending, succeeded or failed
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+
+    -- Outcome of the latest attempt
+    response_status INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+
+    -- NULL once the delivery has succeeded or failed for good
+    next_attempt_at TIMESTAMP,
+    last_attempt_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, id DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,4 @@
+-- Drop the event_outbox table and its associated indexes
+DROP INDEX IF EXISTS idx_event_outbox_published_at;
+DROP INDEX IF EXISTS idx_event_outbox_unpublished;
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,19 @@
+-- Create the event_outbox table
+-- Project writes add their change events here in the same transaction, so
+-- an event is stored if and only if its change is. A relay publishes rows
+-- in id order, which matches commit order for any one project because
+-- writes lock the project row before adding their events.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    project_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- NULL until every sink has accepted the event
+    published_at TIMESTAMP
+);
+
+CREATE INDEX idx_event_outbox_unpublished ON event_outbox(id) WHERE published_at IS NULL;
+CREATE INDEX idx_event_outbox_published_at ON event_outbox(published_at);
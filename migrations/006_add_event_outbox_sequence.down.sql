@@ -0,0 +1,4 @@
+-- Drop the event_outbox sequence column and its associated index
+DROP INDEX IF EXISTS idx_event_outbox_sequence;
+ALTER TABLE event_outbox DROP COLUMN IF EXISTS sequence;
+DROP SEQUENCE IF EXISTS event_outbox_sequence;
@@ -0,0 +1,11 @@
+-- Number outbox events in the order they are published
+-- Ids follow insert order, which can differ from commit order across
+-- projects, so the relay assigns each event a sequence number as it
+-- publishes it. Only one relay publishes at a time, so sequence numbers
+-- never commit out of order and clients can resume a stream after the last
+-- one they saw.
+CREATE SEQUENCE IF NOT EXISTS event_outbox_sequence;
+
+ALTER TABLE event_outbox ADD COLUMN IF NOT EXISTS sequence BIGINT;
+
+CREATE UNIQUE INDEX idx_event_outbox_sequence ON event_outbox(sequence);
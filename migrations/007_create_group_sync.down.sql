@@ -0,0 +1,4 @@
+-- Drop the group sync tables and their associated indexes
+DROP INDEX IF EXISTS idx_group_sync_runs_started_at;
+DROP TABLE IF EXISTS group_sync_members;
+DROP TABLE IF EXISTS group_sync_runs;
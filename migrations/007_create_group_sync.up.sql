@@ -0,0 +1,33 @@
+-- Create the group sync tables
+-- A sync enumerates the projects in the configured GitLab groups, registers
+-- new ones and marks those that left the groups absent. Each run is kept as
+-- a report, and the projects last seen in each group are remembered so the
+-- next run can tell which disappeared.
+CREATE TABLE IF NOT EXISTS group_sync_runs (
+    id BIGSERIAL PRIMARY KEY,
+    groups TEXT[] NOT NULL,
+
+    -- schedule or manual
+    trigger TEXT NOT NULL,
+
+    -- running, succeeded, partial (some groups failed) or failed
+    status TEXT NOT NULL DEFAULT 'running',
+
+    projects_seen INTEGER NOT NULL DEFAULT 0,
+    created TEXT[] NOT NULL DEFAULT '{}',
+    marked_absent TEXT[] NOT NULL DEFAULT '{}',
+
+    -- [{"group": ..., "error": ...}]
+    errors JSONB NOT NULL DEFAULT '[]',
+
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS group_sync_members (
+    group_path TEXT NOT NULL,
+    project_id TEXT NOT NULL,
+    PRIMARY KEY (group_path, project_id)
+);
+
+CREATE INDEX idx_group_sync_runs_started_at ON group_sync_runs(started_at DESC);
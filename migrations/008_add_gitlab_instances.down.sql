@@ -0,0 +1,28 @@
+-- Drop the instance columns and restore keying by project ID alone
+-- Projects on other instances cannot be told apart without their instance,
+-- so they are deleted along with their exemptions and sync membership.
+DROP INDEX IF EXISTS idx_group_sync_runs_instance;
+CREATE INDEX IF NOT EXISTS idx_group_sync_runs_started_at ON group_sync_runs(started_at DESC);
+
+DELETE FROM group_sync_members WHERE instance <> 'default';
+ALTER TABLE group_sync_members DROP CONSTRAINT group_sync_members_pkey;
+ALTER TABLE group_sync_members DROP COLUMN IF EXISTS instance;
+ALTER TABLE group_sync_members ADD PRIMARY KEY (group_path, project_id);
+
+DELETE FROM group_sync_runs WHERE instance <> 'default';
+ALTER TABLE group_sync_runs DROP COLUMN IF EXISTS instance;
+
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS instance;
+
+DROP INDEX IF EXISTS idx_project_exemptions_project;
+DELETE FROM gitlab_projects WHERE instance <> 'default';
+ALTER TABLE project_exemptions DROP CONSTRAINT project_exemptions_instance_project_id_fkey;
+ALTER TABLE project_exemptions DROP COLUMN IF EXISTS instance;
+
+ALTER TABLE gitlab_projects DROP CONSTRAINT gitlab_projects_pkey;
+ALTER TABLE gitlab_projects DROP COLUMN IF EXISTS instance;
+ALTER TABLE gitlab_projects ADD PRIMARY KEY (project_id);
+
+ALTER TABLE project_exemptions
+    ADD FOREIGN KEY (project_id) REFERENCES gitlab_projects(project_id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_project_exemptions_project_id ON project_exemptions(project_id);
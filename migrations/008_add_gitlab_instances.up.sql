@@ -0,0 +1,29 @@
+-- Key projects by GitLab instance as well as project ID
+-- GitLab project IDs are only unique within an instance. Every existing
+-- project, and everything referring to one, is assigned to the default
+-- instance.
+ALTER TABLE gitlab_projects ADD COLUMN instance TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE gitlab_projects ALTER COLUMN instance DROP DEFAULT;
+
+ALTER TABLE project_exemptions DROP CONSTRAINT project_exemptions_project_id_fkey;
+ALTER TABLE gitlab_projects DROP CONSTRAINT gitlab_projects_pkey;
+ALTER TABLE gitlab_projects ADD PRIMARY KEY (instance, project_id);
+
+ALTER TABLE project_exemptions ADD COLUMN instance TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE project_exemptions ALTER COLUMN instance DROP DEFAULT;
+ALTER TABLE project_exemptions
+    ADD FOREIGN KEY (instance, project_id) REFERENCES gitlab_projects(instance, project_id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_project_exemptions_project_id;
+CREATE INDEX idx_project_exemptions_project ON project_exemptions(instance, project_id);
+
+ALTER TABLE webhook_deliveries ADD COLUMN instance TEXT NOT NULL DEFAULT 'default';
+
+-- Groups are synced per instance
+ALTER TABLE group_sync_runs ADD COLUMN instance TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE group_sync_members ADD COLUMN instance TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE group_sync_members DROP CONSTRAINT group_sync_members_pkey;
+ALTER TABLE group_sync_members ADD PRIMARY KEY (instance, group_path, project_id);
+
+DROP INDEX IF EXISTS idx_group_sync_runs_started_at;
+CREATE INDEX idx_group_sync_runs_instance ON group_sync_runs(instance, started_at DESC);
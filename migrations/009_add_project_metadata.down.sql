@@ -0,0 +1,12 @@
+-- Drop the GitLab project metadata columns and their indexes
+DROP INDEX IF EXISTS idx_gitlab_projects_path;
+DROP INDEX IF EXISTS idx_gitlab_projects_gitlab_id;
+
+ALTER TABLE gitlab_projects
+    DROP COLUMN IF EXISTS archived,
+    DROP COLUMN IF EXISTS visibility,
+    DROP COLUMN IF EXISTS web_url,
+    DROP COLUMN IF EXISTS default_branch,
+    DROP COLUMN IF EXISTS path_with_namespace,
+    DROP COLUMN IF EXISTS name,
+    DROP COLUMN IF EXISTS gitlab_id;
@@ -0,0 +1,14 @@
+-- Describe each project as GitLab last reported it
+-- Filled in by scans; gitlab_id is NULL until the project is first found.
+ALTER TABLE gitlab_projects
+    ADD COLUMN gitlab_id BIGINT,
+    ADD COLUMN name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN path_with_namespace TEXT NOT NULL DEFAULT '',
+    ADD COLUMN default_branch TEXT NOT NULL DEFAULT '',
+    ADD COLUMN web_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN visibility TEXT NOT NULL DEFAULT '',
+    ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Lookups by numeric ID or path, which GitLab treats case-insensitively
+CREATE INDEX idx_gitlab_projects_gitlab_id ON gitlab_projects(instance, gitlab_id);
+CREATE INDEX idx_gitlab_projects_path ON gitlab_projects(instance, lower(path_with_namespace));
@@ -0,0 +1,52 @@
+-- Restore a column per check and drop the results table
+-- Results of checks added since have no column and are lost.
+ALTER TABLE gitlab_projects
+    ADD COLUMN project_present BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN app_name_set BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN moab_id_set BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN codeowners_exists BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN branch_protection_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN codeowner_approval_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN push_merge_restricted BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN force_push_disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN push_rules_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN min_approvals_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN author_approval_prevented BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN committer_approval_prevented BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN approvals_removed_on_commit BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE gitlab_projects p SET
+    project_present = COALESCE(r.project_present, FALSE),
+    app_name_set = COALESCE(r.app_name_set, FALSE),
+    moab_id_set = COALESCE(r.moab_id_set, FALSE),
+    codeowners_exists = COALESCE(r.codeowners_exists, FALSE),
+    branch_protection_enabled = COALESCE(r.branch_protection_enabled, FALSE),
+    codeowner_approval_required = COALESCE(r.codeowner_approval_required, FALSE),
+    push_merge_restricted = COALESCE(r.push_merge_restricted, FALSE),
+    force_push_disabled = COALESCE(r.force_push_disabled, FALSE),
+    push_rules_enabled = COALESCE(r.push_rules_enabled, FALSE),
+    min_approvals_required = COALESCE(r.min_approvals_required, FALSE),
+    author_approval_prevented = COALESCE(r.author_approval_prevented, FALSE),
+    committer_approval_prevented = COALESCE(r.committer_approval_prevented, FALSE),
+    approvals_removed_on_commit = COALESCE(r.approvals_removed_on_commit, FALSE)
+FROM (
+    SELECT instance, project_id,
+        bool_or(passed) FILTER (WHERE check_id = 'project_present') AS project_present,
+        bool_or(passed) FILTER (WHERE check_id = 'app_name_set') AS app_name_set,
+        bool_or(passed) FILTER (WHERE check_id = 'moab_id_set') AS moab_id_set,
+        bool_or(passed) FILTER (WHERE check_id = 'codeowners_exists') AS codeowners_exists,
+        bool_or(passed) FILTER (WHERE check_id = 'branch_protection_enabled') AS branch_protection_enabled,
+        bool_or(passed) FILTER (WHERE check_id = 'codeowner_approval_required') AS codeowner_approval_required,
+        bool_or(passed) FILTER (WHERE check_id = 'push_merge_restricted') AS push_merge_restricted,
+        bool_or(passed) FILTER (WHERE check_id = 'force_push_disabled') AS force_push_disabled,
+        bool_or(passed) FILTER (WHERE check_id = 'push_rules_enabled') AS push_rules_enabled,
+        bool_or(passed) FILTER (WHERE check_id = 'min_approvals_required') AS min_approvals_required,
+        bool_or(passed) FILTER (WHERE check_id = 'author_approval_prevented') AS author_approval_prevented,
+        bool_or(passed) FILTER (WHERE check_id = 'committer_approval_prevented') AS committer_approval_prevented,
+        bool_or(passed) FILTER (WHERE check_id = 'approvals_removed_on_commit') AS approvals_removed_on_commit
+    FROM project_check_results
+    GROUP BY instance, project_id
+) r
+WHERE p.instance = r.instance AND p.project_id = r.project_id;
+
+DROP TABLE IF EXISTS project_check_results;
@@ -0,0 +1,48 @@
+-- Store check results as one row per project and check
+-- Checks are defined in code, so adding one no longer needs a column. A
+-- project without a row for a check has not passed it.
+CREATE TABLE IF NOT EXISTS project_check_results (
+    instance TEXT NOT NULL,
+    project_id TEXT NOT NULL,
+    check_id TEXT NOT NULL,
+    passed BOOLEAN NOT NULL,
+    PRIMARY KEY (instance, project_id, check_id),
+    FOREIGN KEY (instance, project_id) REFERENCES gitlab_projects(instance, project_id) ON DELETE CASCADE
+);
+
+-- Readiness filters look up passing results by check
+CREATE INDEX idx_project_check_results_check ON project_check_results(check_id, passed);
+
+INSERT INTO project_check_results (instance, project_id, check_id, passed)
+SELECT p.instance, p.project_id, c.check_id, c.passed
+FROM gitlab_projects p
+CROSS JOIN LATERAL (VALUES
+    ('project_present', p.project_present),
+    ('app_name_set', p.app_name_set),
+    ('moab_id_set', p.moab_id_set),
+    ('codeowners_exists', p.codeowners_exists),
+    ('branch_protection_enabled', p.branch_protection_enabled),
+    ('codeowner_approval_required', p.codeowner_approval_required),
+    ('push_merge_restricted', p.push_merge_restricted),
+    ('force_push_disabled', p.force_push_disabled),
+    ('push_rules_enabled', p.push_rules_enabled),
+    ('min_approvals_required', p.min_approvals_required),
+    ('author_approval_prevented', p.author_approval_prevented),
+    ('committer_approval_prevented', p.committer_approval_prevented),
+    ('approvals_removed_on_commit', p.approvals_removed_on_commit)
+) AS c(check_id, passed);
+
+ALTER TABLE gitlab_projects
+    DROP COLUMN project_present,
+    DROP COLUMN app_name_set,
+    DROP COLUMN moab_id_set,
+    DROP COLUMN codeowners_exists,
+    DROP COLUMN branch_protection_enabled,
+    DROP COLUMN codeowner_approval_required,
+    DROP COLUMN push_merge_restricted,
+    DROP COLUMN force_push_disabled,
+    DROP COLUMN push_rules_enabled,
+    DROP COLUMN min_approvals_required,
+    DROP COLUMN author_approval_prevented,
+    DROP COLUMN committer_approval_prevented,
+    DROP COLUMN approvals_removed_on_commit;
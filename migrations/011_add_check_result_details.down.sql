@@ -0,0 +1,6 @@
+-- Drop the check result details
+ALTER TABLE project_check_results
+    DROP COLUMN IF EXISTS evaluated_at,
+    DROP COLUMN IF EXISTS evidence,
+    DROP COLUMN IF EXISTS reason,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,11 @@
+-- Record how each check result was reached
+-- status tells a verified failure from a GitLab error or a check never
+-- evaluated; passed stays the readiness outcome. Existing results predate
+-- the detail, so they keep their bare pass or fail.
+ALTER TABLE project_check_results
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'unknown',
+    ADD COLUMN reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN evidence JSONB,
+    ADD COLUMN evaluated_at TIMESTAMPTZ;
+
+UPDATE project_check_results SET status = CASE WHEN passed THEN 'pass' ELSE 'fail' END;
@@ -0,0 +1,6 @@
+-- Drop readiness profiles and measured values
+ALTER TABLE project_check_results
+    DROP COLUMN IF EXISTS value;
+
+ALTER TABLE gitlab_projects
+    DROP COLUMN IF EXISTS profile;
@@ -0,0 +1,9 @@
+-- Hold projects to the thresholds of a readiness profile
+-- An empty profile is the default one. value is what a measured check
+-- counted, such as the approvals merge requests need; it is NULL for other
+-- checks and for results recorded before checks were measured.
+ALTER TABLE gitlab_projects
+    ADD COLUMN profile TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE project_check_results
+    ADD COLUMN value INTEGER;
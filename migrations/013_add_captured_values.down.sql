@@ -0,0 +1,6 @@
+-- Drop the applications table and captured values
+DROP TABLE IF EXISTS applications;
+
+DROP INDEX IF EXISTS idx_project_check_results_captured;
+ALTER TABLE project_check_results
+    DROP COLUMN IF EXISTS captured;
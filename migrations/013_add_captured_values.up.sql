@@ -0,0 +1,16 @@
+-- Record the values capturing checks read, and the applications they may name
+-- captured holds, say, the MOAB_ID a project's .gitlab-ci.yml sets, so
+-- reports can find values shared by several projects.
+ALTER TABLE project_check_results
+    ADD COLUMN captured TEXT;
+
+CREATE INDEX idx_project_check_results_captured ON project_check_results(check_id, captured)
+    WHERE captured IS NOT NULL;
+
+-- The registered applications APP_NAME values are expected to name,
+-- replaced as a whole by each import
+CREATE TABLE IF NOT EXISTS applications (
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- Drop stored code owners
+DROP INDEX IF EXISTS idx_gitlab_projects_code_owners;
+ALTER TABLE gitlab_projects
+    DROP COLUMN IF EXISTS code_owners;
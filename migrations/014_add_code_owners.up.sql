@@ -0,0 +1,7 @@
+-- Store the owners each project's CODEOWNERS file names
+-- Filled in by scans, so notifications and access rules can find the
+-- projects a user or group owns.
+ALTER TABLE gitlab_projects
+    ADD COLUMN code_owners TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_gitlab_projects_code_owners ON gitlab_projects USING GIN (code_owners);
@@ -0,0 +1,2 @@
+-- Drop the instance of outbox events
+ALTER TABLE event_outbox DROP COLUMN IF EXISTS instance;
@@ -0,0 +1,5 @@
+-- Record the GitLab instance of each outbox event's project
+-- Project IDs are only unique within an instance. Events written before
+-- instances existed belong to the default instance.
+ALTER TABLE event_outbox ADD COLUMN instance TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE event_outbox ALTER COLUMN instance DROP DEFAULT;
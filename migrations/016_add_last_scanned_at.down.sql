@@ -0,0 +1,2 @@
+-- Drop the full scan time
+ALTER TABLE gitlab_projects DROP COLUMN IF EXISTS last_scanned_at;
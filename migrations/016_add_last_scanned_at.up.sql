@@ -0,0 +1,5 @@
+-- Record when each project was last scanned in full
+-- Deploy gates judge freshness by it rather than updated_at, which every
+-- write moves. Projects start unscanned, so their next gated deploy with a
+-- max_age rescans them.
+ALTER TABLE gitlab_projects ADD COLUMN last_scanned_at TIMESTAMPTZ;
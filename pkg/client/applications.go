@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Application is an application registered on the server
+type Application struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProjectRef names a project in a report
+type ProjectRef struct {
+	Instance          string `json:"instance"`
+	ProjectID         string `json:"project_id"`
+	PathWithNamespace string `json:"path_with_namespace,omitempty"`
+}
+
+// DuplicateMoabID is a MOAB ID set by more than one project
+type DuplicateMoabID struct {
+	MoabID   string       `json:"moab_id"`
+	Projects []ProjectRef `json:"projects"`
+}
+
+// UnregisteredAppName is a project whose APP_NAME names no registered
+// application
+type UnregisteredAppName struct {
+	ProjectRef
+	AppName string `json:"app_name"`
+}
+
+// ListApplications calls GET /applications
+func (c *Client) ListApplications(ctx context.Context) ([]*Application, error) {
+	env, err := c.do(ctx, http.MethodGet, "/applications", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []*Application
+	if err := decodeData(env, &apps); err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+// ReplaceApplications calls PUT /applications with a CSV file, returning how
+// many applications are now registered
+func (c *Client) ReplaceApplications(ctx context.Context, data []byte) (int, error) {
+	env, err := c.do(ctx, http.MethodPut, "/applications", nil, rawBody{data: data, contentType: "text/csv"})
+	if err != nil {
+		return 0, err
+	}
+
+	var summary struct {
+		Applications int `json:"applications"`
+	}
+	if err := decodeData(env, &summary); err != nil {
+		return 0, err
+	}
+	return summary.Applications, nil
+}
+
+// DuplicateMoabIDs calls GET /reports/duplicate-moab-ids. A client with an
+// instance reports on that instance only.
+func (c *Client) DuplicateMoabIDs(ctx context.Context) ([]DuplicateMoabID, error) {
+	env, err := c.do(ctx, http.MethodGet, "/reports/duplicate-moab-ids", c.reportQuery(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var duplicates []DuplicateMoabID
+	if err := decodeData(env, &duplicates); err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+// UnregisteredAppNames calls GET /reports/unregistered-app-names. A client
+// with an instance reports on that instance only.
+func (c *Client) UnregisteredAppNames(ctx context.Context) ([]UnregisteredAppName, error) {
+	env, err := c.do(ctx, http.MethodGet, "/reports/unregistered-app-names", c.reportQuery(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var unregistered []UnregisteredAppName
+	if err := decodeData(env, &unregistered); err != nil {
+		return nil, err
+	}
+	return unregistered, nil
+}
+
+func (c *Client) reportQuery() url.Values {
+	if c.instance == "" {
+		return nil
+	}
+	return url.Values{"instance": {c.instance}}
+}
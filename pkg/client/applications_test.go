@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+func TestClient_Applications(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	n, err := c.ReplaceApplications(ctx, []byte("\ufeffName,Description\npayments,Card payments\nledger,\n"))
+	if err != nil {
+		t.Fatalf("ReplaceApplications() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("ReplaceApplications() = %d, want 2", n)
+	}
+
+	apps, err := c.ListApplications(ctx)
+	if err != nil {
+		t.Fatalf("ListApplications() error = %v", err)
+	}
+	if len(apps) != 2 || apps[0].Name != "ledger" || apps[1].Description != "Card payments" || apps[0].CreatedAt.IsZero() {
+		t.Errorf("ListApplications() = %+v", apps)
+	}
+
+	// An invalid file leaves the list as it was
+	_, err = c.ReplaceApplications(ctx, []byte("name\npayments\npayments\n"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("ReplaceApplications(duplicate) error = %v, want 400", err)
+	}
+	if apps, _ := c.ListApplications(ctx); len(apps) != 2 {
+		t.Errorf("applications after rejected file = %+v", apps)
+	}
+}
+
+func TestClient_CapturedValueReports(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	seed := func(instance, id, appName, moabID string) {
+		t.Helper()
+		p := &models.Project{Instance: instance, ProjectID: id}
+		p.SetEvaluation(checks.AppNameSet, models.Evaluation{Status: checks.StatusPass, Captured: appName})
+		p.SetEvaluation(checks.MoabIDSet, models.Evaluation{Status: checks.StatusPass, Captured: moabID})
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+	seed("", "1", "payments", "MOAB-1")
+	seed("", "2", "paymnets", "MOAB-1")
+	seed("", "3", "", "MOAB-2")
+	seed("onprem", "4", "ledger", "MOAB-2")
+
+	if _, err := c.ReplaceApplications(ctx, []byte("name\npayments\nledger\n")); err != nil {
+		t.Fatalf("ReplaceApplications() error = %v", err)
+	}
+
+	duplicates, err := c.DuplicateMoabIDs(ctx)
+	if err != nil {
+		t.Fatalf("DuplicateMoabIDs() error = %v", err)
+	}
+	if len(duplicates) != 2 || duplicates[0].MoabID != "MOAB-1" || len(duplicates[0].Projects) != 2 || duplicates[1].Projects[1].Instance != "onprem" {
+		t.Errorf("DuplicateMoabIDs() = %+v", duplicates)
+	}
+
+	unregistered, err := c.UnregisteredAppNames(ctx)
+	if err != nil {
+		t.Fatalf("UnregisteredAppNames() error = %v", err)
+	}
+	if len(unregistered) != 1 || unregistered[0].ProjectID != "2" || unregistered[0].AppName != "paymnets" {
+		t.Errorf("UnregisteredAppNames() = %+v", unregistered)
+	}
+
+	// Reports from a client for one instance cover only that instance
+	onPrem, err := New(Config{BaseURL: c.baseURL.String(), Instance: "onprem"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if duplicates, err := onPrem.DuplicateMoabIDs(ctx); err != nil || len(duplicates) != 0 {
+		t.Errorf("onprem DuplicateMoabIDs() = %+v, %v", duplicates, err)
+	}
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// ImportSummary reports the outcome of ImportProjects
+type ImportSummary struct {
+	Mode       string `json:"mode"`        // atomic or partial
+	OnConflict string `json:"on_conflict"` // skip, update or fail
+	Committed  bool   `json:"committed"`   // False when an atomic import was rolled back
+
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+
+	Errors []ImportRowError `json:"errors"`
+}
+
+// ImportRowError is a row ImportProjects rejected
+type ImportRowError struct {
+	Line      int    `json:"line"`
+	ProjectID string `json:"project_id,omitempty"`
+	Error     string `json:"error"`
+}
+
+// Import file formats
+const (
+	ImportCSV    = "csv"
+	ImportNDJSON = "ndjson"
+)
+
+type ImportOptions struct {
+	Format     string // ImportCSV or ImportNDJSON
+	Partial    bool   // Commit valid rows even when others fail
+	OnConflict string // skip (server default), update or fail
+}
+
+// ImportProjects calls POST /gitlab/projects/import with a CSV or NDJSON
+// file. When an atomic import is rejected the summary describing the failed
+// rows is returned alongside an error matching ErrInvalid.
+func (c *Client) ImportProjects(ctx context.Context, data []byte, opts ImportOptions) (*ImportSummary, error) {
+	contentType := "text/csv"
+	if opts.Format == ImportNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	q := url.Values{}
+	if opts.Partial {
+		q.Set("mode", "partial")
+	}
+	if opts.OnConflict != "" {
+		q.Set("on_conflict", opts.OnConflict)
+	}
+
+	env, err := c.do(ctx, http.MethodPost, c.projectsPath()+"/import", q, rawBody{data: data, contentType: contentType})
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity && len(apiErr.Details) > 0 {
+			var summary ImportSummary
+			if json.Unmarshal(apiErr.Details, &summary) == nil {
+				return &summary, err
+			}
+		}
+		return nil, err
+	}
+
+	var summary ImportSummary
+	if err := decodeData(env, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Export file formats
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+	ExportXLSX   = "xlsx"
+)
+
+type ExportOptions struct {
+	Format string // ExportCSV (server default), ExportNDJSON or ExportXLSX
+
+	Ready     *bool  // Only projects that pass (or fail) every check
+	Failing   string // Only projects failing the named check
+	CodeOwner string // Only projects whose CODEOWNERS file names the owner
+}
+
+// ExportProjects calls GET /gitlab/projects/export, copying the file to w
+// as it streams in. It returns the number of bytes written.
+func (c *Client) ExportProjects(ctx context.Context, w io.Writer, opts ExportOptions) (int64, error) {
+	q := ListOptions{Ready: opts.Ready, Failing: opts.Failing, CodeOwner: opts.CodeOwner}.values()
+	if opts.Format != "" {
+		q.Set("format", opts.Format)
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, c.projectsPath()+"/export", q, nil, "*/*")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		data, _ := io.ReadAll(resp.Body)
+		return 0, newAPIError(resp.StatusCode, resp.Header.Get(requestIDHeader), data)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("export interrupted: %w", err)
+	}
+	return n, nil
+}
+
+// BatchOperation is one write in a Batch call
+type BatchOperation struct {
+	Op        string   `json:"op"`
+	Instance  string   `json:"instance,omitempty"`
+	ProjectID string   `json:"project_id,omitempty"`
+	Project   *Project `json:"project,omitempty"`
+}
+
+// BatchResult reports the outcome of every operation in a Batch call
+type BatchResult struct {
+	Mode      string            `json:"mode"`
+	Committed bool              `json:"committed"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult is the outcome of one operation, by its index in the batch
+type BatchItemResult struct {
+	Index     int    `json:"index"`
+	Op        string `json:"op"`
+	Instance  string `json:"instance,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Batch operation kinds and modes
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchUpsert = "upsert"
+	BatchDelete = "delete"
+
+	BatchAtomic     = "atomic"      // All operations succeed or none are applied
+	BatchBestEffort = "best_effort" // Each operation succeeds or fails on its own
+)
+
+// Batch calls POST /gitlab/projects:batch. When an atomic batch is rolled
+// back the per-operation results are returned alongside an error matching
+// ErrInvalid.
+func (c *Client) Batch(ctx context.Context, mode string, ops []BatchOperation) (*BatchResult, error) {
+	req := struct {
+		Mode       string           `json:"mode"`
+		Operations []BatchOperation `json:"operations"`
+	}{Mode: mode, Operations: ops}
+
+	env, err := c.do(ctx, http.MethodPost, c.projectsPath()+":batch", nil, req)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity && len(apiErr.Details) > 0 {
+			var result BatchResult
+			if json.Unmarshal(apiErr.Details, &result) == nil {
+				return &result, err
+			}
+		}
+		return nil, err
+	}
+
+	var result BatchResult
+	if err := decodeData(env, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+func TestClient_ImportProjects(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "existing"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	csv := []byte("project_id,codeowners_exists\nnew,true\nexisting,true\nbroken,maybe\n")
+
+	// Atomic mode rejects the file because of the invalid row
+	summary, err := c.ImportProjects(ctx, csv, ImportOptions{})
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("ImportProjects(atomic) error = %v, want ErrInvalid", err)
+	}
+	if summary == nil || summary.Committed || summary.Failed != 1 || summary.Errors[0].Line != 4 {
+		t.Errorf("ImportProjects(atomic) summary = %+v", summary)
+	}
+	if _, err := c.GetProject(ctx, "new"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected atomic import to write nothing, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		opts ImportOptions
+		want models.ImportSummary
+	}{
+		{
+			name: "partial skip",
+			data: csv,
+			opts: ImportOptions{Partial: true},
+			want: models.ImportSummary{Total: 3, Created: 1, Skipped: 1, Failed: 1},
+		},
+		{
+			name: "partial update",
+			data: csv,
+			opts: ImportOptions{Partial: true, OnConflict: "update"},
+			want: models.ImportSummary{Total: 3, Updated: 2, Failed: 1},
+		},
+		{
+			name: "ndjson",
+			data: []byte(`{"project_id":"json","force_push_disabled":true}` + "\n"),
+			opts: ImportOptions{Format: ImportNDJSON},
+			want: models.ImportSummary{Total: 1, Created: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.ImportProjects(ctx, tt.data, tt.opts)
+			if err != nil {
+				t.Fatalf("ImportProjects() error = %v", err)
+			}
+			if !got.Committed || got.Total != tt.want.Total || got.Created != tt.want.Created ||
+				got.Updated != tt.want.Updated || got.Skipped != tt.want.Skipped || got.Failed != tt.want.Failed {
+				t.Errorf("ImportProjects() = %+v, want counts %+v", got, tt.want)
+			}
+		})
+	}
+
+	project, err := c.GetProject(ctx, "existing")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if !project.Result("codeowners_exists") {
+		t.Error("expected on_conflict=update to overwrite checks")
+	}
+
+	if _, err := c.ImportProjects(ctx, []byte("project_id,bogus\n1,true\n"), ImportOptions{}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("ImportProjects(bad header) error = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestClient_ExportProjects(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	for _, id := range []string{"b", "a", "c"} {
+		if err := repo.Create(ctx, &models.Project{ProjectID: id, Results: map[string]bool{"codeowners_exists": id != "c"}}); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := c.ExportProjects(ctx, &buf, ExportOptions{Failing: "codeowners_exists"}); err != nil {
+		t.Fatalf("ExportProjects() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "instance,project_id,profile,project_present,") || !strings.HasPrefix(lines[1], "default,c,") {
+		t.Errorf("filtered CSV export:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err := c.ExportProjects(ctx, &buf, ExportOptions{Format: ExportNDJSON}); err != nil {
+		t.Fatalf("ExportProjects(ndjson) error = %v", err)
+	}
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], `"project_id":"a"`) {
+		t.Errorf("NDJSON export should be ordered by project_id:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if _, err := c.ExportProjects(ctx, &buf, ExportOptions{Format: ExportXLSX}); err != nil {
+		t.Fatalf("ExportProjects(xlsx) error = %v", err)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())); err != nil {
+		t.Errorf("XLSX export is not a zip: %v", err)
+	}
+
+	if _, err := c.ExportProjects(ctx, io.Discard, ExportOptions{Format: "pdf"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("ExportProjects(pdf) error = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestClient_Batch(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	for _, id := range []string{"existing", "doomed"} {
+		if err := repo.Create(ctx, &models.Project{ProjectID: id}); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+
+	ops := []BatchOperation{
+		{Op: BatchCreate, Project: &Project{ProjectID: "new"}},
+		{Op: BatchUpdate, Project: &Project{ProjectID: "existing", Results: map[string]bool{"codeowners_exists": true}}},
+		{Op: BatchUpsert, Project: &Project{ProjectID: "upserted"}},
+		{Op: BatchDelete, ProjectID: "doomed"},
+		{Op: BatchUpdate, Project: &Project{ProjectID: "missing"}},
+		{Op: BatchCreate, ProjectID: "new", Project: &Project{}},
+		{Op: "rename", ProjectID: "x"},
+	}
+
+	// The missing project and the invalid operations roll back the atomic batch
+	result, err := c.Batch(ctx, BatchAtomic, ops)
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Batch(atomic) error = %v, want ErrInvalid", err)
+	}
+	if result == nil || result.Committed || result.Results[0].Status != http.StatusFailedDependency || result.Results[6].Status != http.StatusBadRequest {
+		t.Errorf("Batch(atomic) result = %+v", result)
+	}
+	if _, err := c.GetProject(ctx, "doomed"); err != nil {
+		t.Errorf("expected rolled back batch to keep doomed, got %v", err)
+	}
+
+	result, err = c.Batch(ctx, BatchBestEffort, ops)
+	if err != nil {
+		t.Fatalf("Batch(best_effort) error = %v", err)
+	}
+
+	want := []int{
+		http.StatusCreated,
+		http.StatusOK,
+		http.StatusCreated,
+		http.StatusNoContent,
+		http.StatusNotFound,
+		http.StatusBadRequest, // duplicate project_id
+		http.StatusBadRequest, // unknown op
+	}
+	for i, status := range want {
+		if got := result.Results[i].Status; got != status {
+			t.Errorf("results[%d].Status = %d, want %d (%s)", i, got, status, result.Results[i].Error)
+		}
+	}
+	if !result.Committed || result.Succeeded != 4 || result.Failed != 3 {
+		t.Errorf("Batch(best_effort) summary = %+v", result)
+	}
+
+	if project, err := c.GetProject(ctx, "existing"); err != nil || !project.Result("codeowners_exists") {
+		t.Errorf("expected existing to be updated, got %+v, %v", project, err)
+	}
+	if _, err := c.GetProject(ctx, "doomed"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected doomed to be deleted, got %v", err)
+	}
+
+	if _, err := c.Batch(ctx, "sometimes", ops); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("Batch(bad mode) error = %v, want ErrBadRequest", err)
+	}
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CheckDefinition describes a readiness check registered on the server
+type CheckDefinition struct {
+	ID          string `json:"id"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	Remediation string `json:"remediation"` // How to make the check pass
+	Severity    string `json:"severity"`    // low, medium, high or critical
+
+	Files    []string `json:"files,omitempty"`    // Repository files the check reads
+	Pipeline bool     `json:"pipeline,omitempty"` // The check reads the latest pipeline
+
+	// Unit is what a measured check counts, and Threshold the bound it
+	// holds the count to unless a profile sets one, such as ">= 1"
+	Unit      string `json:"unit,omitempty"`
+	Threshold string `json:"threshold,omitempty"`
+
+	// Captures names the value a capturing check reads, such as a CI
+	// variable
+	Captures string `json:"captures,omitempty"`
+}
+
+// Profile is a readiness profile configured on the server. Thresholds and
+// formats are keyed by check ID.
+type Profile struct {
+	Name              string            `json:"name"`
+	Thresholds        map[string]string `json:"thresholds"` // Such as ">= 2"
+	Formats           map[string]string `json:"formats,omitempty"`
+	ProtectedBranches []string          `json:"protected_branches,omitempty"`
+	ProtectedTags     []string          `json:"protected_tags,omitempty"`
+}
+
+// MemberReview is who has access to a project, as the access review checks
+// see it. Lists name members by username.
+type MemberReview struct {
+	Members         []ReviewedMember `json:"members"`
+	Owners          []string         `json:"owners"`           // Active users with the Owner role
+	Maintainers     []string         `json:"maintainers"`      // Active users with the Maintainer role
+	ExternalWriters []string         `json:"external_writers"` // External users with Developer or above
+	Unverified      []string         `json:"unverified"`       // Writers whose external status is hidden
+	Blocked         []string         `json:"blocked"`          // Members whose account is not active
+}
+
+// ReviewedMember is one member of a project, directly or through its groups
+type ReviewedMember struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	Name        string `json:"name"`
+	State       string `json:"state"`
+	AccessLevel int    `json:"access_level"`
+	Role        string `json:"role"`
+	Inherited   bool   `json:"inherited"` // The role comes from a group, not a direct membership
+	Bot         bool   `json:"bot,omitempty"`
+	ExpiresAt   string `json:"expires_at,omitempty"`
+	External    *bool  `json:"external"` // Null where unknown
+}
+
+// ListChecks calls GET /checks
+func (c *Client) ListChecks(ctx context.Context) ([]*CheckDefinition, error) {
+	env, err := c.do(ctx, http.MethodGet, "/checks", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []*CheckDefinition
+	if err := decodeData(env, &defs); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// ListProfiles calls GET /profiles
+func (c *Client) ListProfiles(ctx context.Context) ([]*Profile, error) {
+	env, err := c.do(ctx, http.MethodGet, "/profiles", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*Profile
+	if err := decodeData(env, &profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// ProjectMembers calls GET /gitlab/projects/{id}/members, which reads the
+// project's members from GitLab
+func (c *Client) ProjectMembers(ctx context.Context, projectID string) (*MemberReview, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	env, err := c.do(ctx, http.MethodGet, c.projectPath(projectID)+"/members", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var review MemberReview
+	if err := decodeData(env, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+)
+
+func TestClient_ListChecks(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	defs, err := c.ListChecks(ctx)
+	if err != nil {
+		t.Fatalf("ListChecks() error = %v", err)
+	}
+	if len(defs) != 29 || defs[0].ID != "project_present" || defs[0].Severity != "critical" {
+		t.Fatalf("ListChecks() = %+v", defs)
+	}
+
+	// Project JSON keeps one boolean per listed check
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "42", Results: map[string]bool{"codeowners_exists": true}}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	resp, err := http.Get(c.baseURL.String() + apiPrefix + "/gitlab/projects/42")
+	if err != nil {
+		t.Fatalf("GET project error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode project: %v", err)
+	}
+	for _, def := range defs {
+		want := def.ID == "codeowners_exists"
+		if got, ok := body.Data[def.ID].(bool); !ok || got != want {
+			t.Errorf("project JSON %s = %v, want %v", def.ID, body.Data[def.ID], want)
+		}
+	}
+}
+
+func TestClient_ListProfiles(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+
+	profiles, err := c.ListProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != checks.DefaultProfile || profiles[1].Name != "tier-1" {
+		t.Fatalf("ListProfiles() = %+v", profiles)
+	}
+
+	// The default profile lists the thresholds checks are defined with
+	if got := profiles[0].Thresholds[checks.MinApprovalsRequired]; got != ">= 1" {
+		t.Errorf("default min_approvals_required threshold = %q, want >= 1", got)
+	}
+	if got := profiles[1].Thresholds[checks.MinApprovalsRequired]; got != ">= 2" {
+		t.Errorf("tier-1 min_approvals_required threshold = %q, want >= 2", got)
+	}
+	if _, ok := profiles[1].Thresholds[checks.CodeownersExists]; ok {
+		t.Error("tier-1 lists a threshold for codeowners_exists, which has none")
+	}
+}
+
+func TestClient_ProjectMembers(t *testing.T) {
+	scanner := &stubScanner{}
+	c, repo := setupTestServerWithScanner(t, scanner, nil)
+	scanner.repo = repo
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+	review, err := c.ProjectMembers(ctx, "42")
+	if err != nil {
+		t.Fatalf("ProjectMembers() error = %v", err)
+	}
+	if len(review.Members) != 1 || review.Members[0].Role != "Owner" || len(review.Owners) != 1 {
+		t.Errorf("ProjectMembers() = %+v", review)
+	}
+
+	_, err = c.ProjectMembers(ctx, "missing")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("ProjectMembers() for an unknown project error = %v, want 404", err)
+	}
+}
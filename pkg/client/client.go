@@ -0,0 +1,303 @@
+// Package client is a Go SDK for the Project Readiness API.
+//
+// It declares the API's resources and response envelopes so callers need not,
+// without depending on the server's packages, and it takes care of retries,
+// pagination and request-ID propagation.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiPrefix = "/api/v1"
+
+// requestIDHeader carries the request ID both ways, as the server's
+// RequestID middleware reads and writes it
+const requestIDHeader = "X-Request-Id"
+
+// PaginationMeta describes the page returned by list endpoints
+type PaginationMeta struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type Config struct {
+	BaseURL    string       // Server root, e.g. http://localhost:8080
+	HTTPClient *http.Client // Defaults to a client with a 30s timeout
+
+	MaxRetries int           // Retries after the first attempt on 5xx/429, default 3
+	MinBackoff time.Duration // Delay before the first retry, default 200ms
+	MaxBackoff time.Duration // Upper bound for a single delay, default 5s
+
+	UserAgent string
+
+	// Instance is the GitLab instance project calls address. Empty means
+	// the server's default instance, with listings spanning every instance.
+	Instance string
+}
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	userAgent  string
+	instance   string
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("base URL is required")
+	}
+
+	baseURL, err := url.Parse(strings.TrimRight(cfg.BaseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if baseURL.Scheme == "" || baseURL.Host == "" {
+		return nil, fmt.Errorf("invalid base URL: %q must include scheme and host", cfg.BaseURL)
+	}
+
+	c := &Client{
+		baseURL:    baseURL,
+		httpClient: cfg.HTTPClient,
+		maxRetries: cfg.MaxRetries,
+		minBackoff: cfg.MinBackoff,
+		maxBackoff: cfg.MaxBackoff,
+		userAgent:  cfg.UserAgent,
+		instance:   cfg.Instance,
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = 3
+	}
+	if c.maxRetries < 0 {
+		c.maxRetries = 0
+	}
+	if c.minBackoff <= 0 {
+		c.minBackoff = 200 * time.Millisecond
+	}
+	if c.maxBackoff <= 0 {
+		c.maxBackoff = 5 * time.Second
+	}
+	if c.userAgent == "" {
+		c.userAgent = "readiness-go-client/1.0"
+	}
+
+	return c, nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose requests carry the given X-Request-Id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context whose write requests carry the given
+// Idempotency-Key. The server replays its first response to retries with the
+// same key, so the client also retries such POSTs on 5xx responses.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
+
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// baseResponse is the part of the envelope every response shares
+type baseResponse struct {
+	Status    string    `json:"status"`
+	Code      int       `json:"code"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// envelope matches both the server's success and paginated responses
+type envelope struct {
+	baseResponse
+	Data       json.RawMessage `json:"data,omitempty"`
+	Pagination *PaginationMeta `json:"pagination,omitempty"`
+}
+
+// rawBody is sent as-is instead of being encoded as JSON
+type rawBody struct {
+	data        []byte
+	contentType string
+}
+
+// do sends the request and decodes the response envelope. A nil envelope is
+// returned for bodiless responses.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}) (*envelope, error) {
+	resp, err := c.send(ctx, method, path, query, body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeResponse(resp)
+}
+
+// doRaw sends the request and returns the undecoded body of a successful
+// response, for endpoints that do not return JSON envelopes
+func (c *Client) doRaw(ctx context.Context, method, path string, query url.Values, accept string) ([]byte, error) {
+	resp, err := c.send(ctx, method, path, query, nil, accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp.StatusCode, resp.Header.Get(requestIDHeader), data)
+	}
+	return data, nil
+}
+
+// send performs the request, retrying on 5xx and 429 responses. The caller
+// must close the returned response body.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}, accept string) (*http.Response, error) {
+	var payload []byte
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		payload, contentType = b.data, b.contentType
+	default:
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+	}
+
+	// path is already escaped by the caller, so build the URL as a string
+	// rather than through url.URL.Path which would escape it again
+	target := c.baseURL.String() + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+		req.Header.Set("Accept", accept)
+		req.Header.Set("User-Agent", c.userAgent)
+		if payload != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if reqID := requestIDFromContext(ctx); reqID != "" {
+			req.Header.Set(requestIDHeader, reqID)
+		}
+		idemKey := idempotencyKeyFromContext(ctx)
+		if idemKey != "" && method != http.MethodGet {
+			req.Header.Set("Idempotency-Key", idemKey)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", method, path, err)
+		}
+
+		if c.shouldRetry(method, resp.StatusCode, idemKey != "") && attempt < c.maxRetries {
+			delay := c.backoff(attempt, resp.Header.Get("Retry-After"))
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		return resp, nil
+	}
+}
+
+// shouldRetry reports whether a response is worth another attempt. POST is
+// not idempotent, so without an Idempotency-Key it is only retried on 429
+// where the server has explicitly refused to process the request.
+func (c *Client) shouldRetry(method string, status int, hasIdempotencyKey bool) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if status < 500 || status == http.StatusNotImplemented {
+		return false
+	}
+	return method != http.MethodPost || hasIdempotencyKey
+}
+
+// backoff returns an exponential delay with jitter, honoring Retry-After
+// when the server sends one
+func (c *Client) backoff(attempt int, retryAfter string) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, c.maxBackoff)
+		}
+		if at, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(time.Until(at), 0), c.maxBackoff)
+		}
+	}
+
+	delay := c.minBackoff << attempt
+	if delay <= 0 || delay > c.maxBackoff {
+		delay = c.maxBackoff
+	}
+	// Jitter over the upper half keeps concurrent clients from retrying in lockstep
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func decodeResponse(resp *http.Response) (*envelope, error) {
+	defer resp.Body.Close()
+
+	requestID := resp.Header.Get(requestIDHeader)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, newAPIError(resp.StatusCode, requestID, data)
+	}
+
+	if resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &env, nil
+}
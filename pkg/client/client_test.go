@@ -0,0 +1,424 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/handlers"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/outbox"
+	"github.com/user/go-backend/internal/repository/repotest"
+	"github.com/user/go-backend/internal/router"
+	"github.com/user/go-backend/internal/webhooks"
+)
+
+func setupTestServer(t *testing.T, wrap func(http.Handler) http.Handler) (*Client, *repotest.ProjectRepository) {
+	t.Helper()
+	return setupTestServerWithScanner(t, nil, wrap)
+}
+
+func setupTestServerWithScanner(t *testing.T, scanner handlers.Scanner, wrap func(http.Handler) http.Handler) (*Client, *repotest.ProjectRepository) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	repo := repotest.NewProjectRepository()
+	reviewer, _ := scanner.(handlers.MemberReviewer)
+	exemptions := repotest.NewExemptionRepository()
+	webhookRepo := repotest.NewWebhookRepository()
+
+	dispatcher := webhooks.New(webhookRepo, webhooks.Config{PollInterval: 10 * time.Millisecond}, logger)
+	relay := outbox.NewRelay(repo.Outbox(), []outbox.Sink{dispatcher}, outbox.Config{PollInterval: 10 * time.Millisecond}, logger)
+	broker := outbox.NewBroker()
+	follower := outbox.NewFollower(repo.Outbox(), broker, nil, outbox.Config{PollInterval: 10 * time.Millisecond}, logger)
+	ctx, stop := context.WithCancel(context.Background())
+	t.Cleanup(stop)
+	go relay.Run(ctx)
+	go dispatcher.Run(ctx)
+	go follower.Run(ctx)
+
+	handler := router.New(router.Handlers{
+		Idempotency: handlers.NewIdempotencyMiddleware(repotest.NewIdempotencyRepository(), time.Hour, logger),
+		Project:     handlers.NewProjectHandler(repo, logger),
+		Gate:        handlers.NewGateHandler(repo, exemptions, scanner, logger),
+		Scan:        handlers.NewScanHandler(scanner, logger),
+		Member:      handlers.NewMemberHandler(reviewer, logger),
+		Exemption:   handlers.NewExemptionHandler(repo, exemptions, logger),
+		Report:      handlers.NewReportHandler(repo, exemptions, logger),
+		Import:      handlers.NewImportHandler(repo, logger),
+		Export:      handlers.NewExportHandler(repo, logger),
+		Batch:       handlers.NewBatchHandler(repo, logger),
+		Webhook:     handlers.NewWebhookHandler(webhookRepo, dispatcher, logger),
+		Events:      handlers.NewEventsHandler(broker, repo.Outbox(), nil, logger),
+		Sync:        handlers.NewSyncHandler(repotest.NewSyncRepository(), nil, logger),
+		Instance: handlers.NewInstanceHandler([]models.GitLabInstance{
+			{Name: models.DefaultInstance, URL: "https://gitlab.com"},
+			{Name: "onprem", URL: "https://gitlab.example.com", RateLimit: 5, Burst: 10},
+		}, logger),
+		Check:       handlers.NewCheckHandler(checks.NewProfiles([]checks.Profile{{Name: "tier-1", Thresholds: map[string]checks.Threshold{"min_approvals_required": {Op: ">=", Value: 2}}}}), logger),
+		Application: handlers.NewApplicationHandler(repo, repotest.NewApplicationRepository(), logger),
+	}, logger)
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	t.Cleanup(broker.Close) // End open event streams so Close does not wait on them
+
+	c, err := New(Config{
+		BaseURL:    srv.URL,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return c, repo
+}
+
+func TestClient_ProjectLifecycle(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	health, err := c.Health(ctx)
+	if err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if health.Service != "gitlab-readiness-api" {
+		t.Errorf("Health().Service = %q", health.Service)
+	}
+
+	created, err := c.CreateProject(ctx, &Project{ProjectID: "42", Results: map[string]bool{"project_present": true}})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if created.ProjectID != "42" || !created.Result("project_present") || created.CreatedAt.IsZero() {
+		t.Errorf("CreateProject() = %+v", created)
+	}
+
+	created.SetCheck("app_name_set", true)
+	updated, err := c.UpdateProject(ctx, created)
+	if err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	if !updated.Result("app_name_set") {
+		t.Error("UpdateProject() did not persist app_name_set")
+	}
+
+	got, err := c.GetProject(ctx, "42")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if !got.Result("app_name_set") || !got.Result("project_present") {
+		t.Errorf("GetProject() = %+v", got)
+	}
+	if len(got.Scores) == 0 || got.Scores[0] != (CategoryScore{Category: "gitlab_presence", Passed: 2, Total: 4, Score: 0.5}) {
+		t.Errorf("GetProject().Scores = %+v", got.Scores)
+	}
+
+	if err := c.DeleteProject(ctx, "42"); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+
+	_, err = c.GetProject(ctx, "42")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("GetProject() after delete error = %v, want ErrNotFound", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error %T is not an *APIError", err)
+	}
+	if apiErr.Message != "project_id not found" || apiErr.RequestID == "" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestClient_GetProjectByRef(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	project := &Project{ProjectID: "payments", Profile: "tier-1"}
+	project.GitLabID = 42
+	project.PathWithNamespace = "platform/payments-api"
+	if _, err := c.CreateProject(ctx, project); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	for _, ref := range []string{"payments", "42", "platform/payments-api"} {
+		got, err := c.GetProject(ctx, ref)
+		if err != nil {
+			t.Errorf("GetProject(%q) error = %v", ref, err)
+			continue
+		}
+		if got.ProjectID != "payments" || got.PathWithNamespace != "platform/payments-api" {
+			t.Errorf("GetProject(%q) = %+v", ref, got)
+		}
+	}
+
+	// Updates without metadata or a profile keep what is stored
+	updated, err := c.UpdateProject(ctx, &Project{ProjectID: "payments", Results: map[string]bool{"app_name_set": true}})
+	if err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	if updated.GitLabID != 42 || updated.Profile != "tier-1" || !updated.Result("app_name_set") {
+		t.Errorf("UpdateProject() = %+v", updated)
+	}
+}
+
+func TestClient_Evaluations(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	seed := &models.Project{ProjectID: "42"}
+	seed.SetEvaluation("codeowners_exists", models.Evaluation{
+		Status:   checks.StatusFail,
+		Reason:   "No CODEOWNERS file found",
+		Evidence: json.RawMessage(`{"paths":["CODEOWNERS"]}`),
+	})
+	seed.SetEvaluation("author_approval_prevented", models.Evaluation{Status: checks.StatusError, Reason: "GitLab API returned 403 for /projects/42/approvals"})
+	if err := repo.Create(ctx, seed); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	got, err := c.GetProject(ctx, "42")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if e := got.Evaluation("codeowners_exists"); e.Reason != "No CODEOWNERS file found" || string(e.Evidence) != `{"paths":["CODEOWNERS"]}` {
+		t.Errorf("codeowners_exists evaluation = %+v", e)
+	}
+	if e := got.Evaluation("author_approval_prevented"); e.Status != StatusError || got.Result("author_approval_prevented") {
+		t.Errorf("author_approval_prevented evaluation = %+v", e)
+	}
+	if e := got.Evaluation("app_name_set"); e.Status != StatusUnknown {
+		t.Errorf("unscanned app_name_set status = %q, want unknown", e.Status)
+	}
+
+	// Setting a result by hand supersedes its evaluation
+	got.SetCheck("codeowners_exists", true)
+	updated, err := c.UpdateProject(ctx, got)
+	if err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	if e := updated.Evaluation("codeowners_exists"); e.Status != StatusPass || e.Reason != "" {
+		t.Errorf("codeowners_exists after update = %+v", e)
+	}
+}
+
+func TestProject_UnmarshalJSON(t *testing.T) {
+	// A check the client has never heard of, as from a newer server
+	data := []byte(`{
+		"instance": "default", "project_id": "42",
+		"project_present": true, "future_check": true, "codeowners_exists": false,
+		"evaluations": {
+			"project_present": {"status": "pass"},
+			"future_check": {"status": "pass"},
+			"codeowners_exists": {"status": "fail", "reason": "No CODEOWNERS file found"}
+		},
+		"archived": true
+	}`)
+
+	var p Project
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got, want := p.CheckIDs(), []string{"project_present", "future_check", "codeowners_exists"}; !slices.Equal(got, want) {
+		t.Errorf("CheckIDs() = %v, want %v", got, want)
+	}
+	if !p.Result("future_check") || p.Ready() || !p.Archived {
+		t.Errorf("project = %+v", p)
+	}
+	if failing := p.FailingChecks(nil); len(failing) != 1 || failing[0].Name != "codeowners_exists" || failing[0].Reason != "No CODEOWNERS file found" {
+		t.Errorf("FailingChecks() = %+v", failing)
+	}
+
+	// Sending it back keeps the unknown check
+	out, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(out, &fields); err != nil || string(fields["future_check"]) != "true" {
+		t.Errorf("Marshal() = %s", out)
+	}
+}
+
+func TestClient_CreateConflict(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "dup"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	_, err := c.CreateProject(ctx, &Project{ProjectID: "dup"})
+	if !errors.Is(err, ErrConflict) {
+		t.Errorf("CreateProject() duplicate error = %v, want ErrConflict", err)
+	}
+}
+
+func TestClient_ProjectsIterator(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		if err := repo.Create(ctx, &models.Project{ProjectID: fmt.Sprintf("p-%02d", i)}); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want int
+	}{
+		{"pages of three", ListOptions{Limit: 3}, 7},
+		{"single page", ListOptions{}, 7},
+		{"from offset", ListOptions{Limit: 2, Offset: 5}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for project, err := range c.Projects(ctx, tt.opts) {
+				if err != nil {
+					t.Fatalf("Projects() error = %v", err)
+				}
+				ids = append(ids, project.ProjectID)
+			}
+			if len(ids) != tt.want {
+				t.Errorf("Projects() yielded %d projects (%v), want %d", len(ids), ids, tt.want)
+			}
+		})
+	}
+
+	// Breaking out early must not fetch further pages
+	var seen int
+	for range c.Projects(ctx, ListOptions{Limit: 2}) {
+		seen++
+		break
+	}
+	if seen != 1 {
+		t.Errorf("early break yielded %d projects", seen)
+	}
+}
+
+func TestClient_ListFilters(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	seed := []*models.Project{
+		{ProjectID: "a", Results: map[string]bool{"project_present": true, "codeowners_exists": true}},
+		{ProjectID: "b", Results: map[string]bool{"project_present": true}},
+		{ProjectID: "c"},
+	}
+	for _, p := range seed {
+		if err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("failed to seed project: %v", err)
+		}
+	}
+
+	notReady := false
+	list, err := c.ListProjects(ctx, ListOptions{Ready: &notReady, Failing: "codeowners_exists"})
+	if err != nil {
+		t.Fatalf("ListProjects() error = %v", err)
+	}
+	if len(list.Projects) != 2 || list.Pagination.Total != 2 {
+		t.Errorf("ListProjects() = %d projects, total %d, want 2", len(list.Projects), list.Pagination.Total)
+	}
+
+	// Code owners are stored by scans
+	seed[1].CodeOwners = []string{"@alice", "@platform/leads"}
+	if err := repo.SaveScan(ctx, seed[1], nil); err != nil {
+		t.Fatalf("failed to save scan: %v", err)
+	}
+	list, err = c.ListProjects(ctx, ListOptions{CodeOwner: "@platform/leads"})
+	if err != nil || len(list.Projects) != 1 || list.Projects[0].ProjectID != "b" || len(list.Projects[0].CodeOwners) != 2 {
+		t.Errorf("ListProjects() by code owner = %+v, %v", list, err)
+	}
+
+	_, err = c.ListProjects(ctx, ListOptions{Failing: "not_a_check"})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("ListProjects() unknown check error = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	flaky := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= 2 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	c, _ := setupTestServer(t, flaky)
+
+	if _, err := c.Health(context.Background()); err != nil {
+		t.Fatalf("Health() error = %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server saw %d calls, want 3", got)
+	}
+}
+
+func TestClient_DoesNotRetryPostOnServerError(t *testing.T) {
+	var calls atomic.Int32
+	failing := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		})
+	}
+
+	c, _ := setupTestServer(t, failing)
+
+	_, err := c.CreateProject(context.Background(), &Project{ProjectID: "1"})
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("CreateProject() error = %v, want ErrServer", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server saw %d calls, want 1", got)
+	}
+}
+
+func TestClient_PropagatesRequestID(t *testing.T) {
+	var received string
+	capture := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Get(middleware.RequestIDHeader)
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	c, _ := setupTestServer(t, capture)
+	ctx := WithRequestID(context.Background(), "req-abc")
+
+	_, err := c.GetProject(ctx, "missing")
+	if received != "req-abc" {
+		t.Errorf("server received request ID %q, want req-abc", received)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-abc" {
+		t.Errorf("APIError request ID not echoed: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for use with errors.Is against an *APIError
+var (
+	ErrBadRequest  = errors.New("bad request")
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrInvalid     = errors.New("unprocessable")
+	ErrRateLimited = errors.New("rate limited")
+	ErrServer      = errors.New("server error")
+)
+
+// APIError mirrors the server's error response along with transport details
+type APIError struct {
+	StatusCode int             `json:"code"`
+	Message    string          `json:"message"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id,omitempty"`
+	Details    json.RawMessage `json:"details,omitempty"` // Endpoint-specific context, e.g. an ImportSummary
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("readiness api: %d %s (request_id=%s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("readiness api: %d %s", e.StatusCode, msg)
+}
+
+// Is lets callers match on the class of failure, e.g. errors.Is(err, client.ErrNotFound)
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrInvalid:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+func newAPIError(status int, requestID string, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: status,
+		RequestID:  requestID,
+	}
+
+	var resp struct {
+		baseResponse
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Message != "" {
+		apiErr.Message = resp.Message
+		apiErr.Timestamp = resp.Timestamp
+		apiErr.Details = resp.Details
+	} else {
+		// Proxies and the router's default handlers may not speak our envelope
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	return apiErr
+}
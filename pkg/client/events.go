@@ -0,0 +1,125 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventStreamOptions filters and positions StreamEvents
+type EventStreamOptions struct {
+	ProjectIDs []string // Only events for these projects
+	Group      string   // Only events for projects in this GitLab group, ID or full path
+
+	// LastEventID resumes after this sequence number, replaying the events
+	// published since. Zero streams only new events.
+	LastEventID int64
+}
+
+// StreamEvents calls GET /events and passes each event to fn until ctx is
+// cancelled or fn returns an error, which StreamEvents then returns. Dropped
+// connections are re-established from the last event received, so no event
+// is missed; this includes those cut by the HTTP client's timeout.
+func (c *Client) StreamEvents(ctx context.Context, opts EventStreamOptions, fn func(*Event) error) error {
+	lastID := opts.LastEventID
+	retry := time.Second
+
+	for {
+		err := c.streamEvents(ctx, opts, &lastID, &retry, fn)
+		var callbackErr callbackError
+		var apiErr *APIError
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.As(err, &callbackErr):
+			return callbackErr.err
+		case errors.As(err, &apiErr):
+			return err
+		}
+
+		timer := time.NewTimer(retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// callbackError marks an error returned by the caller's function
+type callbackError struct{ err error }
+
+func (e callbackError) Error() string { return e.err.Error() }
+
+// streamEvents reads one connection's worth of events, advancing lastID and
+// retry as the server sends them
+func (c *Client) streamEvents(ctx context.Context, opts EventStreamOptions, lastID *int64, retry *time.Duration, fn func(*Event) error) error {
+	query := url.Values{}
+	if len(opts.ProjectIDs) > 0 {
+		query.Set("project_id", strings.Join(opts.ProjectIDs, ","))
+	}
+	if opts.Group != "" {
+		query.Set("group", opts.Group)
+	}
+	if *lastID > 0 {
+		query.Set("last_event_id", strconv.FormatInt(*lastID, 10))
+	}
+
+	resp, err := c.send(ctx, http.MethodGet, "/events", query, nil, "text/event-stream")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		data, _ := io.ReadAll(resp.Body)
+		return newAPIError(resp.StatusCode, resp.Header.Get(requestIDHeader), data)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+
+	var id string
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				var event Event
+				if err := json.Unmarshal([]byte(strings.Join(data, "\n")), &event); err != nil {
+					return fmt.Errorf("failed to decode event: %w", err)
+				}
+				if err := fn(&event); err != nil {
+					return callbackError{err}
+				}
+			}
+			if seq, err := strconv.ParseInt(id, 10, 64); err == nil {
+				*lastID = seq
+			}
+			id, data = "", nil
+		case field == "": // Comment, e.g. a keepalive
+		case field == "id":
+			id = value
+		case field == "data":
+			data = append(data, value)
+		case field == "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				*retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestClient_StreamEvents(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "1"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(repo.Outbox().Pending()) > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	last, err := repo.Outbox().LastSequence(ctx)
+	if err != nil || last == 0 {
+		t.Fatalf("LastSequence() = %d, %v, want the first event published", last, err)
+	}
+
+	// Resuming after the first event replays what follows it, so the
+	// stream receives everything below however soon it connects
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	received := make(chan *Event, 32)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.StreamEvents(streamCtx, EventStreamOptions{ProjectIDs: []string{"2"}, LastEventID: last}, func(e *Event) error {
+			received <- e
+			return nil
+		})
+	}()
+
+	project, err := c.CreateProject(ctx, &Project{ProjectID: "2", Results: map[string]bool{"project_present": true}})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	project.SetCheck("project_present", false)
+	if _, err := c.UpdateProject(ctx, project); err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "3"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if err := c.DeleteProject(ctx, "2"); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+
+	want := []string{EventProjectCreated, EventProjectUpdated, EventProjectRegressed, EventCheckChanged, EventProjectDeleted}
+	var events []*Event
+	timeout := time.After(5 * time.Second)
+	for len(events) < len(want) {
+		select {
+		case e := <-received:
+			events = append(events, e)
+		case <-timeout:
+			t.Fatalf("received %d events before timing out, want %d", len(events), len(want))
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamEvents() error = %v, want context.Canceled", err)
+	}
+
+	var types []string
+	for i, e := range events {
+		types = append(types, e.Type)
+		if e.ProjectID != "2" {
+			t.Errorf("event %d is for project %q, want only project 2", i, e.ProjectID)
+		}
+		if i > 0 && e.Sequence <= events[i-1].Sequence {
+			t.Errorf("event %d sequence = %d, want above %d", i, e.Sequence, events[i-1].Sequence)
+		}
+	}
+	if !slices.Equal(types, want) {
+		t.Fatalf("event types = %v, want %v", types, want)
+	}
+
+	// Resuming mid-way picks up with the next event; an error from the
+	// callback ends the stream
+	stop := errors.New("stop")
+	var resumed *Event
+	err = c.StreamEvents(ctx, EventStreamOptions{ProjectIDs: []string{"2"}, LastEventID: events[1].Sequence}, func(e *Event) error {
+		resumed = e
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("StreamEvents() error = %v, want the callback's error", err)
+	}
+	if resumed == nil || resumed.ID != events[2].ID {
+		t.Errorf("resumed with %+v, want %s", resumed, events[2].Type)
+	}
+}
+
+func TestClient_StreamEvents_GroupWithoutGitLab(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+
+	err := c.StreamEvents(context.Background(), EventStreamOptions{Group: "platform"}, func(*Event) error {
+		return nil
+	})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("StreamEvents() error = %v, want a 400 APIError", err)
+	}
+}
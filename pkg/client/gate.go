@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GateResult is the deploy gate decision for a project
+type GateResult struct {
+	Instance    string `json:"instance"`
+	ProjectID   string `json:"project_id"`
+	Environment string `json:"environment"`
+	Passed      bool   `json:"passed"`
+
+	FailingChecks  []CheckResult `json:"failing_checks"`
+	ExemptedChecks []CheckResult `json:"exempted_checks"`
+	Exemptions     []*Exemption  `json:"exemptions"`
+
+	Freshness Freshness `json:"freshness"`
+}
+
+// Freshness describes how current the check data behind a gate decision is.
+// UpdatedAt is when the project was last scanned in full, and is zero, with
+// no age, if it never was.
+type Freshness struct {
+	UpdatedAt     time.Time `json:"updated_at"`
+	AgeSeconds    int64     `json:"age_seconds"`
+	MaxAgeSeconds int64     `json:"max_age_seconds,omitempty"`
+	Stale         bool      `json:"stale"`
+	Rescanned     bool      `json:"rescanned"`
+	ScanError     string    `json:"scan_error,omitempty"`
+}
+
+// Exemption waives a readiness check for a project. An empty Environment
+// applies to every environment; a nil ExpiresAt never expires.
+type Exemption struct {
+	ID          int64      `json:"id"`
+	Instance    string     `json:"instance"`
+	ProjectID   string     `json:"project_id"`
+	CheckName   string     `json:"check_name"`
+	Environment string     `json:"environment,omitempty"`
+	Reason      string     `json:"reason"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type GateOptions struct {
+	Environment string        // Defaults to production on the server
+	MaxAge      time.Duration // Require check data newer than this, rounded up to minutes
+	ScanTimeout time.Duration // How long the server may spend rescanning stale data
+}
+
+func (o GateOptions) values() url.Values {
+	q := url.Values{}
+	if o.Environment != "" {
+		q.Set("environment", o.Environment)
+	}
+	if o.MaxAge > 0 {
+		minutes := int((o.MaxAge + time.Minute - 1) / time.Minute)
+		q.Set("max_age", strconv.Itoa(minutes))
+	}
+	if o.ScanTimeout > 0 {
+		seconds := int((o.ScanTimeout + time.Second - 1) / time.Second)
+		q.Set("scan_timeout", strconv.Itoa(seconds))
+	}
+	return q
+}
+
+// Gate calls GET /gitlab/projects/{id}/gate
+func (c *Client) Gate(ctx context.Context, projectID string, opts GateOptions) (*GateResult, error) {
+	env, err := c.do(ctx, http.MethodGet, c.projectPath(projectID)+"/gate", opts.values(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result GateResult
+	if err := decodeData(env, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListExemptions calls GET /gitlab/projects/{id}/exemptions
+func (c *Client) ListExemptions(ctx context.Context, projectID string) ([]*Exemption, error) {
+	env, err := c.do(ctx, http.MethodGet, c.projectPath(projectID)+"/exemptions", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var exemptions []*Exemption
+	if err := decodeData(env, &exemptions); err != nil {
+		return nil, err
+	}
+	return exemptions, nil
+}
+
+// CreateExemption calls POST /gitlab/projects/{id}/exemptions
+func (c *Client) CreateExemption(ctx context.Context, exemption *Exemption) (*Exemption, error) {
+	if exemption == nil || exemption.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+
+	env, err := c.do(ctx, http.MethodPost, c.projectPath(exemption.ProjectID)+"/exemptions", nil, exemption)
+	if err != nil {
+		return nil, err
+	}
+
+	var created Exemption
+	if err := decodeData(env, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteExemption calls DELETE /gitlab/projects/{id}/exemptions/{exemptionID}
+func (c *Client) DeleteExemption(ctx context.Context, projectID string, exemptionID int64) error {
+	path := c.projectPath(projectID) + "/exemptions/" + strconv.FormatInt(exemptionID, 10)
+	_, err := c.do(ctx, http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// Report formats accepted by ProjectReport
+const (
+	ReportJUnit = "junit"
+	ReportSARIF = "sarif"
+)
+
+// ProjectReport downloads GET /gitlab/projects/{id}/report as JUnit XML or
+// SARIF, applying the exemptions active for environment (production if empty)
+func (c *Client) ProjectReport(ctx context.Context, projectID, format, environment string) ([]byte, error) {
+	q := url.Values{}
+	q.Set("format", format)
+	if environment != "" {
+		q.Set("environment", environment)
+	}
+	return c.doRaw(ctx, http.MethodGet, c.projectPath(projectID)+"/report", q, "*/*")
+}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/checks"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository/repotest"
+)
+
+// stubScanner marks every check as passing, standing in for a GitLab scan
+type stubScanner struct {
+	repo  *repotest.ProjectRepository
+	calls int
+}
+
+func (s *stubScanner) Scan(ctx context.Context, key models.ProjectKey) (*models.Project, error) {
+	s.calls++
+	project, err := s.repo.GetByID(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	project.SetCheck("project_present", true)
+	project.SetCheck("codeowners_exists", true)
+	if err := s.repo.SaveScan(ctx, project, nil); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// ReviewMembers reports a single owner for any stored project
+func (s *stubScanner) ReviewMembers(ctx context.Context, key models.ProjectKey) (*checks.MemberReview, error) {
+	if _, err := s.repo.GetByID(ctx, key); err != nil {
+		return nil, err
+	}
+	return &checks.MemberReview{
+		Members: []checks.ReviewedMember{{ID: 7, Username: "jdoe", State: "active", AccessLevel: 50, Role: "Owner"}},
+		Owners:  []string{"jdoe"},
+	}, nil
+}
+
+func TestClient_GateWithExemptions(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "gated", Results: map[string]bool{"project_present": true}}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	result, err := c.Gate(ctx, "gated", GateOptions{})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if result.Passed || result.Environment != "production" || len(result.FailingChecks) != 28 {
+		t.Fatalf("Gate() = passed %v, env %q, %d failing", result.Passed, result.Environment, len(result.FailingChecks))
+	}
+
+	// Exempt every failing check for staging only
+	for _, check := range result.FailingChecks {
+		_, err := c.CreateExemption(ctx, &Exemption{
+			ProjectID:   "gated",
+			CheckName:   check.Name,
+			Environment: "staging",
+			Reason:      "migration in progress",
+		})
+		if err != nil {
+			t.Fatalf("CreateExemption() error = %v", err)
+		}
+	}
+
+	staging, err := c.Gate(ctx, "gated", GateOptions{Environment: "staging"})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if !staging.Passed || len(staging.ExemptedChecks) != 28 || len(staging.Exemptions) != 28 {
+		t.Errorf("staging Gate() = passed %v, %d exempted", staging.Passed, len(staging.ExemptedChecks))
+	}
+
+	production, err := c.Gate(ctx, "gated", GateOptions{})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if production.Passed || len(production.Exemptions) != 0 {
+		t.Errorf("production Gate() should ignore staging exemptions: %+v", production)
+	}
+
+	_, err = c.CreateExemption(ctx, &Exemption{ProjectID: "gated", CheckName: "nonsense", Reason: "x"})
+	if !errors.Is(err, ErrBadRequest) {
+		t.Errorf("CreateExemption() unknown check error = %v, want ErrBadRequest", err)
+	}
+
+	exemptions, err := c.ListExemptions(ctx, "gated")
+	if err != nil || len(exemptions) != 28 {
+		t.Fatalf("ListExemptions() = %d, %v", len(exemptions), err)
+	}
+	if err := c.DeleteExemption(ctx, "gated", exemptions[0].ID); err != nil {
+		t.Errorf("DeleteExemption() error = %v", err)
+	}
+	if err := c.DeleteExemption(ctx, "gated", exemptions[0].ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("DeleteExemption() twice error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestClient_GateRescansStaleData(t *testing.T) {
+	scanner := &stubScanner{}
+	c, repo := setupTestServerWithScanner(t, scanner, nil)
+	scanner.repo = repo
+	ctx := context.Background()
+
+	project := &models.Project{ProjectID: "stale"}
+	if err := repo.Create(ctx, project); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	// A project never scanned is stale however recently it was written
+	result, err := c.Gate(ctx, "stale", GateOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if scanner.calls != 1 || !result.Freshness.Rescanned || result.Freshness.Stale || result.Freshness.UpdatedAt.IsZero() {
+		t.Errorf("unscanned Gate() scanned %d times, freshness %+v", scanner.calls, result.Freshness)
+	}
+	if len(result.FailingChecks) != 27 {
+		t.Errorf("rescanned Gate() has %d failing checks, want 27", len(result.FailingChecks))
+	}
+
+	// Fresh data does not trigger a scan
+	result, err = c.Gate(ctx, "stale", GateOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if scanner.calls != 1 || result.Freshness.Stale || result.Freshness.Rescanned {
+		t.Errorf("fresh Gate() scanned %d times, freshness %+v", scanner.calls, result.Freshness)
+	}
+
+	// Writing results does not make an old scan fresh
+	repo.SetLastScannedAt(models.NewProjectKey("", "stale"), time.Now().Add(-2*time.Hour))
+	if _, err := c.UpdateProject(ctx, &Project{ProjectID: "stale", Results: map[string]bool{"project_present": true}}); err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+
+	result, err = c.Gate(ctx, "stale", GateOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if scanner.calls != 2 || !result.Freshness.Rescanned || result.Freshness.Stale {
+		t.Errorf("stale Gate() scanned %d times, freshness %+v", scanner.calls, result.Freshness)
+	}
+
+	if _, err := c.ScanProject(ctx, "stale"); err != nil || scanner.calls != 3 {
+		t.Errorf("ScanProject() error = %v, calls = %d", err, scanner.calls)
+	}
+}
+
+func TestClient_GateStaleWithoutScanner(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "old"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+	repo.SetLastScannedAt(models.NewProjectKey("", "old"), time.Now().Add(-time.Hour))
+
+	result, err := c.Gate(ctx, "old", GateOptions{MaxAge: time.Minute})
+	if err != nil {
+		t.Fatalf("Gate() error = %v", err)
+	}
+	if result.Passed || !result.Freshness.Stale || result.Freshness.ScanError == "" {
+		t.Errorf("Gate() freshness = %+v", result.Freshness)
+	}
+
+	_, err = c.ScanProject(ctx, "old")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Errorf("ScanProject() error = %v, want 503", err)
+	}
+	if _, err := c.ProjectMembers(ctx, "old"); !errors.As(err, &apiErr) || apiErr.StatusCode != 503 {
+		t.Errorf("ProjectMembers() error = %v, want 503", err)
+	}
+}
+
+func TestClient_ProjectReport(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "reported", Results: map[string]bool{"project_present": true}}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	junit, err := c.ProjectReport(ctx, "reported", ReportJUnit, "")
+	if err != nil {
+		t.Fatalf("ProjectReport(junit) error = %v", err)
+	}
+	var suites struct {
+		Failures int `xml:"failures,attr"`
+	}
+	if err := xml.Unmarshal(junit, &suites); err != nil || suites.Failures != 28 {
+		t.Errorf("JUnit report failures = %d, err = %v", suites.Failures, err)
+	}
+
+	sarif, err := c.ProjectReport(ctx, "reported", ReportSARIF, "staging")
+	if err != nil {
+		t.Fatalf("ProjectReport(sarif) error = %v", err)
+	}
+	var log struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(sarif, &log); err != nil || log.Version != "2.1.0" {
+		t.Errorf("SARIF report version = %q, err = %v", log.Version, err)
+	}
+
+	if _, err := c.ProjectReport(ctx, "reported", "pdf", ""); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("ProjectReport(pdf) error = %v, want ErrBadRequest", err)
+	}
+	if _, err := c.ProjectReport(ctx, "missing", ReportJUnit, ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("ProjectReport(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetProjectContentNegotiation(t *testing.T) {
+	c, repo := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	if err := repo.Create(ctx, &models.Project{ProjectID: "negotiated"}); err != nil {
+		t.Fatalf("failed to seed project: %v", err)
+	}
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/junit+xml", "application/xml; charset=utf-8"},
+		{"application/sarif+json", "application/sarif+json"},
+		{"application/json", "application/json"},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String()+"/api/v1/gitlab/projects/negotiated", nil)
+		req.Header.Set("Accept", tt.accept)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != tt.contentType {
+			t.Errorf("Accept %s: status %d, Content-Type %q, want %q", tt.accept, resp.StatusCode, resp.Header.Get("Content-Type"), tt.contentType)
+		}
+	}
+}
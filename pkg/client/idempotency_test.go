@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/handlers"
+	"github.com/user/go-backend/internal/models"
+	"github.com/user/go-backend/internal/repository"
+	"github.com/user/go-backend/internal/repository/repotest"
+)
+
+func TestClient_IdempotencyKey(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := WithIdempotencyKey(context.Background(), "create-idem-1")
+
+	first, err := c.CreateProject(ctx, &Project{ProjectID: "idem", Results: map[string]bool{"codeowners_exists": true}})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+
+	// A retry with the same key gets the original 201 instead of a 409
+	second, err := c.CreateProject(ctx, &Project{ProjectID: "idem", Results: map[string]bool{"codeowners_exists": true}})
+	if err != nil {
+		t.Fatalf("retried CreateProject() error = %v", err)
+	}
+	if !second.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("retry returned a different project: %v vs %v", second.CreatedAt, first.CreatedAt)
+	}
+
+	// Without a key the duplicate is a conflict
+	if _, err := c.CreateProject(context.Background(), &Project{ProjectID: "idem"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("CreateProject() without key error = %v, want ErrConflict", err)
+	}
+
+	// Reusing the key for a different body is rejected
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "other"}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("CreateProject() with reused key error = %v, want ErrInvalid", err)
+	}
+
+	// The same key on another endpoint is a different key
+	if _, err := c.CreateExemption(ctx, &Exemption{ProjectID: "idem", CheckName: "codeowners_exists", Reason: "Migrating"}); err != nil {
+		t.Errorf("CreateExemption() with the key of a create error = %v", err)
+	}
+}
+
+func TestIdempotencyReplayHeader(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+
+	post := func(body string) *http.Response {
+		req, _ := http.NewRequest(http.MethodPost, c.baseURL.String()+"/api/v1/gitlab/projects", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", "replay-1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	first := post(`{"project_id":"replayed"}`)
+	second := post(`{"project_id":"replayed"}`)
+
+	if first.StatusCode != http.StatusCreated || first.Header.Get("Idempotent-Replayed") != "" {
+		t.Errorf("first response: %d, replayed=%q", first.StatusCode, first.Header.Get("Idempotent-Replayed"))
+	}
+	if second.StatusCode != http.StatusCreated || second.Header.Get("Idempotent-Replayed") != "true" {
+		t.Errorf("second response: %d, replayed=%q", second.StatusCode, second.Header.Get("Idempotent-Replayed"))
+	}
+	if second.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("replayed Content-Type = %q", second.Header.Get("Content-Type"))
+	}
+	if first.Header.Get("X-Request-Id") == second.Header.Get("X-Request-Id") {
+		t.Error("replay should carry its own request ID")
+	}
+}
+
+func TestClient_RetriesPostWithIdempotencyKey(t *testing.T) {
+	var attempts atomic.Int32
+	flaky := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	c, _ := setupTestServer(t, flaky)
+
+	if _, err := c.CreateProject(context.Background(), &Project{ProjectID: "no-key"}); !errors.Is(err, ErrServer) {
+		t.Fatalf("CreateProject() without key error = %v, want ErrServer", err)
+	}
+
+	attempts.Store(0)
+	ctx := WithIdempotencyKey(context.Background(), "retry-1")
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "with-key"}); err != nil {
+		t.Fatalf("CreateProject() with key error = %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+// releasingRepository reports the key released by its owner, as when a
+// claim loses to another request that then fails, on the first claim
+type releasingRepository struct {
+	*repotest.IdempotencyRepository
+	claims atomic.Int32
+}
+
+func (r *releasingRepository) Claim(ctx context.Context, key, requestHash string, expiresAt, staleBefore time.Time) (*models.IdempotencyRecord, error) {
+	if r.claims.Add(1) == 1 {
+		return nil, repository.ErrIdempotencyKeyNotFound
+	}
+	return r.IdempotencyRepository.Claim(ctx, key, requestHash, expiresAt, staleBefore)
+}
+
+func TestIdempotency_ClaimsReleasedKeyAgain(t *testing.T) {
+	repo := &releasingRepository{IdempotencyRepository: repotest.NewIdempotencyRepository()}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := handlers.NewIdempotencyMiddleware(repo, time.Hour, logger).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/gitlab/projects", strings.NewReader(`{"project_id":"raced"}`))
+	req.Header.Set("Idempotency-Key", "raced-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated || repo.claims.Load() != 2 {
+		t.Errorf("status = %d after %d claims, want 201 after 2", rec.Code, repo.claims.Load())
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// GitLabInstance describes a GitLab instance configured on the server
+type GitLabInstance struct {
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	RateLimit  float64  `json:"requests_per_second"` // 0 means unthrottled
+	Burst      int      `json:"burst"`
+	Scanning   bool     `json:"scanning"` // A token is configured
+	Hooks      bool     `json:"hooks"`    // A hook secret is configured
+	SyncGroups []string `json:"sync_groups"`
+}
+
+// ListInstances calls GET /gitlab/instances
+func (c *Client) ListInstances(ctx context.Context) ([]*GitLabInstance, error) {
+	env, err := c.do(ctx, http.MethodGet, "/gitlab/instances", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []*GitLabInstance
+	if err := decodeData(env, &instances); err != nil {
+		return nil, err
+	}
+	return instances, nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/user/go-backend/internal/models"
+)
+
+func TestClient_Instances(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	instances, err := c.ListInstances(ctx)
+	if err != nil {
+		t.Fatalf("ListInstances() error = %v", err)
+	}
+	if len(instances) != 2 || instances[1].Name != "onprem" || instances[1].RateLimit != 5 {
+		t.Errorf("ListInstances() = %+v", instances)
+	}
+
+	onPrem, err := New(Config{BaseURL: c.baseURL.String(), Instance: "onprem"})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	// The same project ID is a separate project on each instance
+	if _, err := c.CreateProject(ctx, &Project{ProjectID: "42"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	created, err := onPrem.CreateProject(ctx, &Project{ProjectID: "42", Results: map[string]bool{"app_name_set": true}})
+	if err != nil {
+		t.Fatalf("CreateProject() on onprem error = %v", err)
+	}
+	if created.Instance != "onprem" {
+		t.Errorf("CreateProject() on onprem Instance = %q", created.Instance)
+	}
+
+	got, err := c.GetProject(ctx, "42")
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if got.Instance != models.DefaultInstance || got.Result("app_name_set") {
+		t.Errorf("GetProject() = %+v, want the default instance's project", got)
+	}
+
+	all, err := c.ListProjects(ctx, ListOptions{})
+	if err != nil {
+		t.Fatalf("ListProjects() error = %v", err)
+	}
+	if all.Pagination.Total != 2 {
+		t.Errorf("ListProjects() total = %d, want 2 across instances", all.Pagination.Total)
+	}
+
+	scoped, err := onPrem.ListProjects(ctx, ListOptions{})
+	if err != nil {
+		t.Fatalf("ListProjects() on onprem error = %v", err)
+	}
+	if scoped.Pagination.Total != 1 || scoped.Projects[0].Instance != "onprem" {
+		t.Errorf("ListProjects() on onprem = %+v", scoped.Projects)
+	}
+
+	if err := onPrem.DeleteProject(ctx, "42"); err != nil {
+		t.Fatalf("DeleteProject() on onprem error = %v", err)
+	}
+	if _, err := c.GetProject(ctx, "42"); err != nil {
+		t.Errorf("GetProject() after deleting the onprem project error = %v", err)
+	}
+}
@@ -0,0 +1,528 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Project is the readiness record returned by the API. Its JSON holds one
+// boolean per check the server runs, named by check ID, and an evaluations
+// object detailing each. The checks are read from the evaluations rather
+// than a list built into the client, so projects keep the results of checks
+// added to the server later.
+type Project struct {
+	Instance  string `json:"instance"`
+	ProjectID string `json:"project_id"`
+
+	// Profile names the readiness profile scans hold the project to. Empty
+	// is the default profile on create and keeps the stored profile on
+	// update.
+	Profile string `json:"profile,omitempty"`
+
+	// Results holds whether each check passed, by check ID. Checks missing
+	// from it have not passed.
+	Results map[string]bool `json:"-"`
+
+	// Evaluations details the scanned outcome of each check, by check ID
+	Evaluations map[string]Evaluation `json:"-"`
+
+	// Scores gives the share of each category's checks the project passes.
+	// The server derives them from the results and ignores them on writes.
+	Scores []CategoryScore `json:"scores,omitempty"`
+
+	// CodeOwners lists the owners the project's CODEOWNERS file names
+	CodeOwners []string `json:"code_owners,omitempty"`
+
+	ProjectMetadata
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastScannedAt *time.Time `json:"last_scanned_at,omitempty"` // Nil until a scan evaluates every check
+
+	// checkIDs lists the checks the server reported, in its display order
+	checkIDs []string
+}
+
+// ProjectMetadata describes a project as GitLab reported it on its last scan
+type ProjectMetadata struct {
+	GitLabID          int    `json:"gitlab_id,omitempty"`
+	Name              string `json:"name,omitempty"`
+	PathWithNamespace string `json:"path_with_namespace,omitempty"`
+	DefaultBranch     string `json:"default_branch,omitempty"`
+	WebURL            string `json:"web_url,omitempty"`
+	Visibility        string `json:"visibility,omitempty"`
+	Archived          bool   `json:"archived"`
+}
+
+// Status is the outcome of evaluating a check
+type Status string
+
+const (
+	StatusPass          Status = "pass"
+	StatusFail          Status = "fail"
+	StatusError         Status = "error"   // GitLab refused or failed a request the check needed
+	StatusUnknown       Status = "unknown" // Never evaluated
+	StatusNotApplicable Status = "not_applicable"
+)
+
+// Passing reports whether the status counts as passed for readiness
+func (s Status) Passing() bool {
+	return s == StatusPass || s == StatusNotApplicable
+}
+
+// Evaluation is the detailed outcome of one check for a project
+type Evaluation struct {
+	Status      Status          `json:"status"`
+	Reason      string          `json:"reason,omitempty"`
+	Evidence    json.RawMessage `json:"evidence,omitempty"` // The GitLab data the outcome was decided on
+	Value       *int            `json:"value,omitempty"`    // What a measured check counted, in the check's unit
+	Captured    string          `json:"captured,omitempty"` // The value a capturing check read
+	EvaluatedAt *time.Time      `json:"evaluated_at,omitempty"`
+}
+
+// CategoryScore is how many of a category's checks a project passes
+type CategoryScore struct {
+	Category string  `json:"category"`
+	Passed   int     `json:"passed"`
+	Total    int     `json:"total"`
+	Score    float64 `json:"score"` // Passed over Total
+}
+
+// CheckResult is the outcome of one check, described by its definition
+type CheckResult struct {
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	Status      Status `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+	Value       *int   `json:"value,omitempty"`
+	Passed      bool   `json:"passed"`
+}
+
+// CheckIDs returns the IDs of the project's checks: those the server
+// reported, in display order, or for a project built by hand, those it has
+// results for, sorted
+func (p *Project) CheckIDs() []string {
+	if p.checkIDs != nil {
+		return slices.Clone(p.checkIDs)
+	}
+	return slices.Sorted(maps.Keys(p.Results))
+}
+
+// Result reports whether the project passed the check with the ID
+func (p *Project) Result(id string) bool {
+	return p.Results[id]
+}
+
+// SetCheck records the result of the check with the ID, dropping its
+// evaluation. The server rejects checks it does not run.
+func (p *Project) SetCheck(id string, passed bool) {
+	if p.Results == nil {
+		p.Results = make(map[string]bool)
+	}
+	if p.checkIDs != nil && !slices.Contains(p.checkIDs, id) {
+		p.checkIDs = append(p.checkIDs, id)
+	}
+	p.Results[id] = passed
+	delete(p.Evaluations, id)
+}
+
+// Evaluation returns the detailed outcome of the check with the ID. Results
+// without an agreeing evaluation are reported as a bare pass or fail, and
+// checks with neither as unknown.
+func (p *Project) Evaluation(id string) Evaluation {
+	passed, ok := p.Results[id]
+	if e, evaluated := p.Evaluations[id]; evaluated && e.Status.Passing() == passed {
+		return e
+	}
+	switch {
+	case !ok:
+		return Evaluation{Status: StatusUnknown}
+	case passed:
+		return Evaluation{Status: StatusPass}
+	default:
+		return Evaluation{Status: StatusFail}
+	}
+}
+
+// Checks returns the outcome of each of the project's checks, described by
+// the matching definition from ListChecks. Checks without one are named by
+// ID alone.
+func (p *Project) Checks(defs []*CheckDefinition) []CheckResult {
+	byID := make(map[string]*CheckDefinition, len(defs))
+	for _, def := range defs {
+		byID[def.ID] = def
+	}
+
+	ids := p.CheckIDs()
+	results := make([]CheckResult, 0, len(ids))
+	for _, id := range ids {
+		e := p.Evaluation(id)
+		result := CheckResult{
+			Name:   id,
+			Status: e.Status,
+			Reason: e.Reason,
+			Value:  e.Value,
+			Passed: p.Result(id),
+		}
+		if def, ok := byID[id]; ok {
+			result.Category = def.Category
+			result.Description = def.Description
+			result.Severity = def.Severity
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// FailingChecks returns the checks the project does not pass
+func (p *Project) FailingChecks(defs []*CheckDefinition) []CheckResult {
+	var failing []CheckResult
+	for _, check := range p.Checks(defs) {
+		if !check.Passed {
+			failing = append(failing, check)
+		}
+	}
+	return failing
+}
+
+// Ready reports whether the project passes every one of its checks
+func (p *Project) Ready() bool {
+	for _, id := range p.CheckIDs() {
+		if !p.Result(id) {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxPageSize is the largest page the server will return
+const MaxPageSize = 100
+
+type ListOptions struct {
+	Limit  int // Defaults to the server's page size when zero
+	Offset int
+
+	Instance string // Only projects on this instance, for clients without one
+	Ready    *bool  // Only projects that pass (or fail) every check
+	Failing  string // Only projects failing the named check
+
+	CodeOwner string // Only projects whose CODEOWNERS file names the owner
+}
+
+func (o ListOptions) values() url.Values {
+	q := url.Values{}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Instance != "" {
+		q.Set("instance", o.Instance)
+	}
+	if o.Ready != nil {
+		q.Set("ready", strconv.FormatBool(*o.Ready))
+	}
+	if o.Failing != "" {
+		q.Set("failing", o.Failing)
+	}
+	if o.CodeOwner != "" {
+		q.Set("code_owner", o.CodeOwner)
+	}
+	return q
+}
+
+type ProjectList struct {
+	Projects   []*Project
+	Pagination PaginationMeta
+}
+
+type HealthStatus struct {
+	Service string `json:"service"`
+	Version string `json:"version"`
+}
+
+// Health calls GET /health
+func (c *Client) Health(ctx context.Context) (*HealthStatus, error) {
+	env, err := c.do(ctx, http.MethodGet, "/health", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var status HealthStatus
+	if err := decodeData(env, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ListProjects returns a single page of projects
+func (c *Client) ListProjects(ctx context.Context, opts ListOptions) (*ProjectList, error) {
+	env, err := c.do(ctx, http.MethodGet, c.projectsPath(), opts.values(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ProjectList{}
+	if env == nil {
+		return list, nil
+	}
+	if len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, &list.Projects); err != nil {
+			return nil, fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	if env.Pagination != nil {
+		list.Pagination = *env.Pagination
+	}
+	return list, nil
+}
+
+// Projects iterates over every project starting at opts.Offset, fetching
+// further pages as needed. Iteration stops after the first error is yielded.
+//
+//	for project, err := range c.Projects(ctx, client.ListOptions{}) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (c *Client) Projects(ctx context.Context, opts ListOptions) iter.Seq2[*Project, error] {
+	return func(yield func(*Project, error) bool) {
+		if opts.Limit <= 0 {
+			opts.Limit = MaxPageSize
+		}
+
+		for {
+			page, err := c.ListProjects(ctx, opts)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for _, project := range page.Projects {
+				if !yield(project, nil) {
+					return
+				}
+			}
+
+			opts.Offset += len(page.Projects)
+			if len(page.Projects) == 0 || opts.Offset >= page.Pagination.Total {
+				return
+			}
+		}
+	}
+}
+
+// GetProject calls GET /gitlab/projects/{id}
+func (c *Client) GetProject(ctx context.Context, projectID string) (*Project, error) {
+	return c.projectRequest(ctx, http.MethodGet, c.projectPath(projectID), nil)
+}
+
+// CreateProject calls POST /gitlab/projects and returns the stored project
+func (c *Client) CreateProject(ctx context.Context, project *Project) (*Project, error) {
+	if project == nil || project.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	return c.projectRequest(ctx, http.MethodPost, c.projectsPath(), project)
+}
+
+// UpdateProject calls PUT /gitlab/projects/{id} and returns the stored project
+func (c *Client) UpdateProject(ctx context.Context, project *Project) (*Project, error) {
+	if project == nil || project.ProjectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	return c.projectRequest(ctx, http.MethodPut, c.projectPath(project.ProjectID), project)
+}
+
+// DeleteProject calls DELETE /gitlab/projects/{id}
+func (c *Client) DeleteProject(ctx context.Context, projectID string) error {
+	if projectID == "" {
+		return fmt.Errorf("project ID is required")
+	}
+	_, err := c.do(ctx, http.MethodDelete, c.projectPath(projectID), nil, nil)
+	return err
+}
+
+func (c *Client) projectRequest(ctx context.Context, method, path string, body interface{}) (*Project, error) {
+	env, err := c.do(ctx, method, path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Project
+	if err := decodeData(env, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// projectsPath is the project collection of the client's instance
+func (c *Client) projectsPath() string {
+	if c.instance == "" {
+		return "/gitlab/projects"
+	}
+	return "/gitlab/instances/" + url.PathEscape(c.instance) + "/projects"
+}
+
+func (c *Client) projectPath(projectID string) string {
+	return c.projectsPath() + "/" + url.PathEscape(projectID)
+}
+
+func decodeData(env *envelope, v interface{}) error {
+	if env == nil || len(env.Data) == 0 {
+		return fmt.Errorf("response did not include data")
+	}
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
+
+// ScanProject calls POST /gitlab/projects/{id}/scan and returns the
+// re-evaluated project
+func (c *Client) ScanProject(ctx context.Context, projectID string) (*Project, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	return c.projectRequest(ctx, http.MethodPost, c.projectPath(projectID)+"/scan", nil)
+}
+
+// projectTrailer is the part of a project's JSON after its check results
+type projectTrailer struct {
+	CodeOwners []string `json:"code_owners,omitempty"`
+	ProjectMetadata
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	LastScannedAt *time.Time `json:"last_scanned_at,omitempty"`
+}
+
+// MarshalJSON writes the project as the server does, with one field per
+// check followed by the evaluation of each
+func (p Project) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if err := writeField(&buf, "instance", p.Instance); err != nil {
+		return nil, err
+	}
+	buf.WriteByte(',')
+	if err := writeField(&buf, "project_id", p.ProjectID); err != nil {
+		return nil, err
+	}
+	if p.Profile != "" {
+		buf.WriteByte(',')
+		if err := writeField(&buf, "profile", p.Profile); err != nil {
+			return nil, err
+		}
+	}
+	ids := p.CheckIDs()
+	evaluations := make(map[string]Evaluation, len(ids))
+	for _, id := range ids {
+		buf.WriteByte(',')
+		if err := writeField(&buf, id, p.Result(id)); err != nil {
+			return nil, err
+		}
+		evaluations[id] = p.Evaluation(id)
+	}
+	buf.WriteByte(',')
+	if err := writeField(&buf, "evaluations", evaluations); err != nil {
+		return nil, err
+	}
+	if len(p.Scores) > 0 {
+		buf.WriteByte(',')
+		if err := writeField(&buf, "scores", p.Scores); err != nil {
+			return nil, err
+		}
+	}
+
+	trailer, err := json.Marshal(projectTrailer{
+		CodeOwners:      p.CodeOwners,
+		ProjectMetadata: p.ProjectMetadata,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		LastScannedAt:   p.LastScannedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte(',')
+	buf.Write(trailer[1:]) // Always an object with at least one field
+	return buf.Bytes(), nil
+}
+
+func writeField(buf *bytes.Buffer, name string, value any) error {
+	key, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(v)
+	return nil
+}
+
+// UnmarshalJSON reads a project, taking its checks from the evaluations
+// object and the result of each from the field named by its ID, in the
+// order the fields appear
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type fields Project // Drops the methods, so decoding does not recurse
+	var f fields
+	if err := json.Unmarshal(data, &f); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Evaluations map[string]Evaluation `json:"evaluations"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("invalid evaluations: %w", err)
+	}
+	f.Evaluations = raw.Evaluations
+	f.Results = nil
+	f.checkIDs = make([]string, 0, len(raw.Evaluations))
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil { // The opening brace
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		id, _ := tok.(string)
+		if _, ok := raw.Evaluations[id]; !ok {
+			continue
+		}
+		var passed bool
+		if err := json.Unmarshal(value, &passed); err != nil {
+			return fmt.Errorf("invalid %s: %w", id, err)
+		}
+		if f.Results == nil {
+			f.Results = make(map[string]bool)
+		}
+		f.Results[id] = passed
+		f.checkIDs = append(f.checkIDs, id)
+	}
+
+	*p = Project(f)
+	return nil
+}
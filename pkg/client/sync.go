@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SyncRun reports one sync of the configured GitLab groups
+type SyncRun struct {
+	ID           int64          `json:"id"`
+	Instance     string         `json:"instance"`
+	Groups       []string       `json:"groups"`
+	Trigger      string         `json:"trigger"`
+	Status       string         `json:"status"`
+	ProjectsSeen int            `json:"projects_seen"` // Projects found across the groups
+	Created      []string       `json:"created"`       // Projects registered by this run
+	MarkedAbsent []string       `json:"marked_absent"` // Projects no longer in any group
+	Errors       []SyncRunError `json:"errors"`
+	StartedAt    time.Time      `json:"started_at"`
+	FinishedAt   *time.Time     `json:"finished_at,omitempty"`
+}
+
+// SyncRunError is a group a sync failed to list
+type SyncRunError struct {
+	Group string `json:"group"`
+	Error string `json:"error"`
+}
+
+type SyncRunList struct {
+	Runs       []*SyncRun
+	Pagination PaginationMeta
+}
+
+// ListSyncRuns calls GET /gitlab/sync, newest first, for the client's
+// instance or every instance when it has none. A zero limit uses the
+// server's page size.
+func (c *Client) ListSyncRuns(ctx context.Context, limit, offset int) (*SyncRunList, error) {
+	q := c.instanceQuery()
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+
+	env, err := c.do(ctx, http.MethodGet, "/gitlab/sync", q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &SyncRunList{}
+	if env == nil {
+		return list, nil
+	}
+	if len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, &list.Runs); err != nil {
+			return nil, fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	if env.Pagination != nil {
+		list.Pagination = *env.Pagination
+	}
+	return list, nil
+}
+
+// LatestSyncRun calls GET /gitlab/sync/latest for the client's instance. It
+// returns ErrNotFound before the first sync.
+func (c *Client) LatestSyncRun(ctx context.Context) (*SyncRun, error) {
+	env, err := c.do(ctx, http.MethodGet, "/gitlab/sync/latest", c.instanceQuery(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var run SyncRun
+	if err := decodeData(env, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// TriggerSync calls POST /gitlab/sync for the client's instance. The sync
+// runs in the background; poll LatestSyncRun for its report.
+func (c *Client) TriggerSync(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodPost, "/gitlab/sync", c.instanceQuery(), nil)
+	return err
+}
+
+// instanceQuery names the client's instance, if it has one
+func (c *Client) instanceQuery() url.Values {
+	q := url.Values{}
+	if c.instance != "" {
+		q.Set("instance", c.instance)
+	}
+	return q
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_Sync_NotConfigured(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	list, err := c.ListSyncRuns(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("ListSyncRuns() error = %v", err)
+	}
+	if len(list.Runs) != 0 || list.Pagination.Total != 0 {
+		t.Errorf("ListSyncRuns() = %+v, want no runs", list)
+	}
+
+	if _, err := c.LatestSyncRun(ctx); !errors.Is(err, ErrNotFound) {
+		t.Errorf("LatestSyncRun() error = %v, want ErrNotFound", err)
+	}
+
+	var apiErr *APIError
+	if err := c.TriggerSync(ctx); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("TriggerSync() error = %v, want 503", err)
+	}
+}
@@ -0,0 +1,262 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook is a subscription to readiness events
+type Webhook struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+
+	// Secret is only returned when the subscription is created or rotated
+	Secret string `json:"secret,omitempty"`
+
+	// Events lists the event types to deliver; empty delivers every event
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookRequest creates or replaces a Webhook
+type WebhookRequest struct {
+	URL string `json:"url"`
+
+	// Secret is generated when empty on create, and kept when empty on update
+	Secret      string   `json:"secret,omitempty"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active,omitempty"` // Defaults to true
+	Description string   `json:"description,omitempty"`
+}
+
+// WebhookDelivery is one event sent, or queued, to a Webhook
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Instance       string          `json:"instance"`
+	ProjectID      string          `json:"project_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"` // pending, succeeded or failed
+	Attempts       int             `json:"attempts"`
+	ResponseStatus *int            `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Event is the JSON body of a webhook delivery or streamed event
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Instance   string    `json:"instance"`
+	ProjectID  string    `json:"project_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       EventData `json:"data"`
+
+	// Sequence orders events as they were published, across instances, and
+	// is the ID of server-sent events
+	Sequence int64 `json:"sequence,omitempty"`
+}
+
+// EventData carries the project after the change (before it, for deletes)
+// and the check results that changed
+type EventData struct {
+	Project *Project      `json:"project"`
+	Changes []CheckChange `json:"changes,omitempty"`
+}
+
+// CheckChange is a check whose result differs between two versions of a project
+type CheckChange struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Passed   bool   `json:"passed"`
+	Previous bool   `json:"previous"`
+}
+
+// Event types a Webhook or event stream can receive
+const (
+	EventProjectCreated   = "project.created"
+	EventProjectUpdated   = "project.updated" // Any check result changed
+	EventProjectDeleted   = "project.deleted"
+	EventProjectReady     = "project.ready"     // Now passes every check after failing one
+	EventProjectRegressed = "project.regressed" // A previously passing check now fails
+	EventCheckChanged     = "check.changed"     // One per check whose result changed
+	EventScanCompleted    = "scan.completed"    // A rescan finished, whether or not any result changed
+)
+
+// CreateWebhook calls POST /webhooks. The returned Webhook carries the
+// signing secret, which later reads omit.
+func (c *Client) CreateWebhook(ctx context.Context, req *WebhookRequest) (*Webhook, error) {
+	return c.webhookRequest(ctx, http.MethodPost, "/webhooks", req)
+}
+
+// ListWebhooks calls GET /webhooks
+func (c *Client) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	env, err := c.do(ctx, http.MethodGet, "/webhooks", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []*Webhook
+	if err := decodeData(env, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhook calls GET /webhooks/{id}
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	return c.webhookRequest(ctx, http.MethodGet, webhookPath(id), nil)
+}
+
+// UpdateWebhook calls PUT /webhooks/{id}. A non-empty req.Secret rotates
+// the secret.
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, req *WebhookRequest) (*Webhook, error) {
+	return c.webhookRequest(ctx, http.MethodPut, webhookPath(id), req)
+}
+
+// DeleteWebhook calls DELETE /webhooks/{id}
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	_, err := c.do(ctx, http.MethodDelete, webhookPath(id), nil, nil)
+	return err
+}
+
+type DeliveryListOptions struct {
+	Limit  int // Defaults to the server's page size when zero
+	Offset int
+	Status string // pending, succeeded or failed; empty lists every delivery
+}
+
+type DeliveryList struct {
+	Deliveries []*WebhookDelivery
+	Pagination PaginationMeta
+}
+
+// ListWebhookDeliveries calls GET /webhooks/{id}/deliveries, newest first
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int64, opts DeliveryListOptions) (*DeliveryList, error) {
+	q := url.Values{}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		q.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.Status != "" {
+		q.Set("status", opts.Status)
+	}
+
+	env, err := c.do(ctx, http.MethodGet, webhookPath(id)+"/deliveries", q, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &DeliveryList{}
+	if env == nil {
+		return list, nil
+	}
+	if len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, &list.Deliveries); err != nil {
+			return nil, fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	if env.Pagination != nil {
+		list.Pagination = *env.Pagination
+	}
+	return list, nil
+}
+
+// RedeliverWebhook calls POST /webhooks/{id}/deliveries/{deliveryID}/redeliver
+// and returns the newly queued delivery
+func (c *Client) RedeliverWebhook(ctx context.Context, id, deliveryID int64) (*WebhookDelivery, error) {
+	path := webhookPath(id) + "/deliveries/" + strconv.FormatInt(deliveryID, 10) + "/redeliver"
+	env, err := c.do(ctx, http.MethodPost, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var delivery WebhookDelivery
+	if err := decodeData(env, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (c *Client) webhookRequest(ctx context.Context, method, path string, body interface{}) (*Webhook, error) {
+	env, err := c.do(ctx, method, path, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook Webhook
+	if err := decodeData(env, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func webhookPath(id int64) string {
+	return "/webhooks/" + strconv.FormatInt(id, 10)
+}
+
+// ErrInvalidSignature is returned by VerifyWebhook for deliveries that were
+// not signed with the secret, or were signed too long ago
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// VerifyWebhook checks a received delivery's X-Readiness-Signature against
+// secret and decodes the event. Deliveries whose X-Readiness-Timestamp is
+// more than tolerance away from now are rejected to stop replays; a zero
+// tolerance defaults to five minutes.
+//
+//	body, _ := io.ReadAll(r.Body)
+//	event, err := client.VerifyWebhook(secret, r.Header, body, 0)
+func VerifyWebhook(secret string, header http.Header, body []byte, tolerance time.Duration) (*Event, error) {
+	if tolerance <= 0 {
+		tolerance = 5 * time.Minute
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get("X-Readiness-Timestamp"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing or malformed timestamp", ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return nil, fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	signature, ok := strings.CutPrefix(header.Get("X-Readiness-Signature"), "sha256=")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing sha256 signature", ErrInvalidSignature)
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode event: %w", err)
+	}
+	return &event, nil
+}
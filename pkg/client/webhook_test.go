@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/go-backend/internal/webhooks"
+)
+
+// webhookReceiver verifies and records the events delivered to it
+type webhookReceiver struct {
+	mu     sync.Mutex
+	secret string
+	events []*Event
+	errs   []error
+}
+
+func (rc *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	event, err := VerifyWebhook(rc.secret, r.Header, body, 0)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if err != nil {
+		rc.errs = append(rc.errs, err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	rc.events = append(rc.events, event)
+}
+
+func (rc *webhookReceiver) received() ([]*Event, []error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]*Event(nil), rc.events...), append([]error(nil), rc.errs...)
+}
+
+func TestClient_Webhooks(t *testing.T) {
+	c, _ := setupTestServer(t, nil)
+	ctx := context.Background()
+
+	rc := &webhookReceiver{}
+	receiver := httptest.NewServer(rc)
+	t.Cleanup(receiver.Close)
+
+	webhook, err := c.CreateWebhook(ctx, &WebhookRequest{
+		URL:    receiver.URL,
+		Events: []string{EventProjectRegressed, EventProjectCreated},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	if webhook.ID == 0 || webhook.Secret == "" || !webhook.Active {
+		t.Fatalf("CreateWebhook() = %+v, want an active webhook with a generated secret", webhook)
+	}
+	rc.mu.Lock()
+	rc.secret = webhook.Secret
+	rc.mu.Unlock()
+
+	fetched, err := c.GetWebhook(ctx, webhook.ID)
+	if err != nil {
+		t.Fatalf("GetWebhook() error = %v", err)
+	}
+	if fetched.Secret != "" {
+		t.Error("GetWebhook() returned the secret")
+	}
+
+	project, err := c.CreateProject(ctx, &Project{ProjectID: "42", Results: map[string]bool{"project_present": true}})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	project.SetCheck("project_present", false)
+	if _, err := c.UpdateProject(ctx, project); err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+
+	var events []*Event
+	deadline := time.Now().Add(5 * time.Second)
+	for len(events) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		events, _ = rc.received()
+	}
+	if _, errs := rc.received(); len(errs) > 0 {
+		t.Fatalf("receiver rejected deliveries: %v", errs)
+	}
+	if len(events) != 2 || events[0].Type != EventProjectCreated || events[1].Type != EventProjectRegressed {
+		t.Fatalf("received %d events, want project.created then project.regressed", len(events))
+	}
+	if changes := events[1].Data.Changes; len(changes) != 1 || changes[0].Name != "project_present" {
+		t.Errorf("regression changes = %+v", changes)
+	}
+
+	// The receiver answers before the dispatcher records the delivery as
+	// succeeded, so wait for the log rather than the receiver
+	var list *DeliveryList
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		list, err = c.ListWebhookDeliveries(ctx, webhook.ID, DeliveryListOptions{Status: "succeeded"})
+		if err != nil {
+			t.Fatalf("ListWebhookDeliveries() error = %v", err)
+		}
+		if list.Pagination.Total >= 2 || time.Now().After(deadline) {
+			break
+		}
+	}
+	if list.Pagination.Total != 2 || list.Deliveries[0].EventType != EventProjectRegressed {
+		t.Fatalf("ListWebhookDeliveries() = %+v, want newest first", list)
+	}
+
+	redelivery, err := c.RedeliverWebhook(ctx, webhook.ID, list.Deliveries[0].ID)
+	if err != nil {
+		t.Fatalf("RedeliverWebhook() error = %v", err)
+	}
+	if redelivery.EventID != list.Deliveries[0].EventID {
+		t.Errorf("RedeliverWebhook() event = %q, want %q", redelivery.EventID, list.Deliveries[0].EventID)
+	}
+
+	if _, err := c.RedeliverWebhook(ctx, webhook.ID, 999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("RedeliverWebhook() of a missing delivery error = %v, want ErrNotFound", err)
+	}
+	if _, err := c.CreateWebhook(ctx, &WebhookRequest{URL: "ftp://example.com"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("CreateWebhook() with an ftp URL error = %v, want ErrBadRequest", err)
+	}
+	if _, err := c.CreateWebhook(ctx, &WebhookRequest{URL: receiver.URL, Events: []string{"project.renamed"}}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("CreateWebhook() with an unknown event error = %v, want ErrBadRequest", err)
+	}
+
+	if err := c.DeleteWebhook(ctx, webhook.ID); err != nil {
+		t.Fatalf("DeleteWebhook() error = %v", err)
+	}
+	if _, err := c.GetWebhook(ctx, webhook.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetWebhook() after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"project.ready","project_id":"42"}`)
+	now := time.Now().Unix()
+
+	signed := func(secret string, timestamp int64) http.Header {
+		h := http.Header{}
+		h.Set(webhooks.TimestampHeader, strconv.FormatInt(timestamp, 10))
+		h.Set(webhooks.SignatureHeader, webhooks.Sign(secret, timestamp, body))
+		return h
+	}
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		wantErr bool
+	}{
+		{"valid", signed("s3cret", now), false},
+		{"wrong secret", signed("other", now), true},
+		{"stale timestamp", signed("s3cret", now-3600), true},
+		{"missing headers", http.Header{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := VerifyWebhook("s3cret", tt.header, body, 0)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSignature) {
+					t.Errorf("VerifyWebhook() error = %v, want ErrInvalidSignature", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyWebhook() error = %v", err)
+			}
+			if event.Type != "project.ready" || event.ProjectID != "42" {
+				t.Errorf("VerifyWebhook() = %+v", event)
+			}
+		})
+	}
+}